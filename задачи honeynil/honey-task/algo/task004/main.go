@@ -3,6 +3,13 @@ package main
 // Задача: обход бинарного дерева категорий, подсчёт повторений имён.
 //
 // out: { 'Машины': 3, 'Легковые': 2, ... }
+//
+// HINT 1: Дерево рекурсивное — обойдите его рекурсивно: посетить узел,
+// затем левое и правое поддерево; nil-узел — база рекурсии.
+// HINT 2: Счётчики удобно держать в map[string]int, общей для всех вызовов:
+// объявите её снаружи и замкните в функции обхода.
+// HINT 3: var dfs func(*Category) перед присваиванием позволяет замыканию
+// вызывать само себя.
 
 import "fmt"
 
@@ -5,6 +5,13 @@ package main
 //
 // search_name = "OLED"
 // out: "Бытовая техника > Телевизоры > OLED"
+//
+// HINT 1: Обход в глубину с текущим путём в параметре: в каждом узле
+// current := append(path, node.Name), и он передаётся детям.
+// HINT 2: Функция обхода возвращает путь, если цель найдена, и nil иначе;
+// первый не-nil результат ребёнка и есть ответ — дальше не обходите.
+// HINT 3: "root" попадает в путь первым элементом: отрежьте его path[1:]
+// и соберите строку через strings.Join(path, " > ").
 
 import (
 	"fmt"
@@ -22,6 +22,13 @@ package main
 // Жадно: всегда применяем к максимуму
 // [13,10,8,5,3]: к 13 → [6,10,8,5,3]; к 10 → [6,3,8,5,3]; к 8 → [6,3,1,5,3]; к 6 → [0,3,1,5,3]
 // сумма = 0+3+1+5+3 = 12 ✓
+//
+// HINT 1: Один купон выгоднее всего тратить на самый дорогой предмет
+// в данный момент — жадный выбор на каждом шаге.
+// HINT 2: Сортировать массив заново перед каждым купоном — O(k·n log n).
+// Максимум быстрее доставать из max-кучи: container/heap с Less через ">".
+// HINT 3: Когда максимум стал 0, оставшиеся купоны ничего не дают —
+// можно остановиться раньше.
 
 import (
 	"fmt"
@@ -1,4 +1,4 @@
-// Найти все ошибки
+// ТЗ: сохранить записи в PostgreSQL и отдать их по HTTP. Найти все ошибки.
 package main
 
 import (
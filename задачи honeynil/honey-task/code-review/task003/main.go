@@ -1,5 +1,9 @@
 package main
 
+// ТЗ: HTTP-сервис статистики посещений: по пользователю и периоду (день или
+// неделя) вернуть число посещений по дням и самую посещаемую локацию дня.
+// Найдите ошибки и предложите исправления.
+
 import (
     "context"
     "database/sql"
@@ -1,6 +1,6 @@
 package main
 
-//исправить код и сделать отмену запросов после ошибки
+// ТЗ: исправить код и сделать отмену запросов после ошибки
 import (
 	"context"
 	"fmt"
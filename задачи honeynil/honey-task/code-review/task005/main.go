@@ -6,7 +6,7 @@ import (
     "net/http"
 )
 
-// Необходимо хранить список из username и почты в памяти программы.
+// ТЗ: необходимо хранить список из username и почты в памяти программы.
 // Пользователи программы могут добавлять и просматривать userов.
 // Разработчик написал данный код, но не прошел ревью.
 // Нужно указать на ошибки и исправить, либо оставить комментарий о том, что можно улучшить.
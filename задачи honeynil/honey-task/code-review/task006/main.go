@@ -7,7 +7,7 @@ import (
 	"honnef.co/go/tools/lintcmd/cache"
 )
 
-// Код-ревью. Скажи что не нравится и как бы ты это исправил:
+// ТЗ: код-ревью сервиса товаров. Скажи что не нравится и как бы ты это исправил:
 type Service struct {
 	db     repository.repository
 	logger *zap.Logger
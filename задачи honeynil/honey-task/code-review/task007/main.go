@@ -1,5 +1,8 @@
 package main
 
+// ТЗ: поиск товаров с подборками — параллельно получить товары и подборки
+// из хранилища и собрать ответ. Найдите ошибки и предложите исправления.
+
 import "context"
 
 type Storage interface {
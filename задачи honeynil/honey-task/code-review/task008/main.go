@@ -1,5 +1,7 @@
 package main
 
+// ТЗ: скорость попугая зависит от его типа. Проведите ревью и отрефакторьте код.
+
 import (
 	"errors"
 	"math"
@@ -1,5 +1,7 @@
 package main
 
+// ТЗ: потокобезопасный кэш строк с GetOrCreate и Get. Найдите ошибки.
+
 import (
 	"fmt"
 	"sync"
@@ -1,4 +1,4 @@
-// Задача 1 – Буферизированный канал и select
+// ЗАДАЧА 21: Буферизированный канал и select
 // Как отработает код?
 //
 // ОТВЕТ: DEADLOCK / программа зависнет навсегда.
@@ -1,4 +1,4 @@
-// Задача 2 – «processed» и потерянные сообщения
+// ЗАДАЧА 22: «processed» и потерянные сообщения
 // Как отработает код?
 //
 // ОТВЕТ: Выведет "processed: cmd.1", но "processed: cmd.2" — НЕ ГАРАНТИРОВАНО.
@@ -1,4 +1,4 @@
-// Задача 3 – Канал-стопер done
+// ЗАДАЧА 23: Канал-стопер done
 // Как отработает код?
 //
 // ОТВЕТ:
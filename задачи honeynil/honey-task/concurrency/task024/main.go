@@ -1,4 +1,4 @@
-// Задача 4 – GOMAXPROCS(1) и бесконечный цикл
+// ЗАДАЧА 24: GOMAXPROCS(1) и бесконечный цикл
 // Как отработает код?
 //
 // ОТВЕТ: Зависит от версии Go:
@@ -1,4 +1,4 @@
-// Задача 5 – Срезы и race condition с append
+// ЗАДАЧА 25: Срезы и race condition с append
 // Исправьте код чтобы он работал корректно
 //
 // ПРОБЛЕМА: DATA RACE на data (go run -race выявит)
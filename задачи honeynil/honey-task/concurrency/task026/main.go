@@ -1,4 +1,4 @@
-// Задача 6 – Порядок defer
+// ЗАДАЧА 26: Порядок defer
 // Как отработает код?
 //
 // ОТВЕТ:
@@ -1,4 +1,4 @@
-// Задача 7 – Указатели и «смена адреса»
+// ЗАДАЧА 27: Указатели и «смена адреса»
 // Как отработает код?
 //
 // ОТВЕТ:
@@ -1,4 +1,4 @@
-// Задача 8 – error и nil-интерфейсы
+// ЗАДАЧА 28: error и nil-интерфейсы
 // Как отработает код?
 //
 // ОТВЕТ:
@@ -1,4 +1,4 @@
-// Задача 9 – Указатели в срезе и переменная цикла
+// ЗАДАЧА 29: Указатели в срезе и переменная цикла
 // Как отработает код?
 //
 // ОТВЕТ (Go 1.22+):
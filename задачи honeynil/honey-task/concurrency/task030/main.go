@@ -1,4 +1,4 @@
-// Задача 10 – JSON и неэкспортируемое поле
+// ЗАДАЧА 30: JSON и неэкспортируемое поле
 // Как отработает код?
 //
 // ОТВЕТ:
@@ -1,3 +1,11 @@
 module github.com/honeynil/honey-task
 
 go 1.25.1
+
+require (
+	github.com/go-chi/chi/v5 v5.3.2
+	github.com/lib/pq v1.12.3
+	go.uber.org/zap v1.28.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
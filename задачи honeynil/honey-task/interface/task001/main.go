@@ -1,8 +1,13 @@
 package main
 
 // Задача: реализовать функцию Pipe — читает из Producer батчами, буферизует до MaxItems, пишет в Consumer.
+// PipeWithOptions — то же с контекстом, сбросом по времени, несколькими обработчиками и повторами.
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 const MaxItems = 9999
 
@@ -62,6 +67,239 @@ func Pipe(p Producer, c Consumer) error {
 	}
 }
 
+// Options — настройки PipeWithOptions. Нулевое значение ведёт себя как Pipe.
+type Options struct {
+	MaxItems      int           // сброс буфера по размеру; 0 — MaxItems
+	FlushInterval time.Duration // сколько первый элемент может ждать в буфере; 0 — сброс только по размеру
+	Workers       int           // сколько батчей обрабатывается одновременно; 0 — один
+	Retries       int           // сколько раз повторить Process после ошибки
+	Backoff       time.Duration // пауза перед первым повтором, дальше удваивается
+}
+
+// PipeWithOptions — Pipe с контекстом. Буфер сбрасывается при MaxItems
+// элементов или через FlushInterval после первого батча в буфере. Пустые
+// батчи сбрасываются так же: их cookie коммитятся без вызова Process.
+// Сброшенные батчи обрабатываются в Workers горутин, поэтому при Workers > 1
+// Consumer должен быть потокобезопасным, а Process вызывается не по порядку.
+// Cookie же коммитятся строго в порядке получения: батч коммитится, только
+// когда обработаны он и все батчи до него.
+//
+// Ошибка Process повторяется Retries раз с паузой Backoff, 2*Backoff, ...
+// Если повторы не помогли, батчи после упавшего не коммитятся, даже если уже
+// обработаны. При отмене ctx буфер не сбрасывается, а из батчей в обработке
+// коммитятся только те, что успели обработаться подряд от начала.
+//
+// Next вызывается в отдельной горутине, одновременно с Commit. Заблокированный
+// Next отменой не прерывается: горутина выйдет, когда он вернёт управление.
+func PipeWithOptions(ctx context.Context, p Producer, c Consumer, opts Options) error {
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = MaxItems
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pp := &pipe{
+		ctx:     ctx,
+		p:       p,
+		c:       c,
+		opts:    opts,
+		results: make(chan *batch, opts.Workers),
+		done:    make(map[int]*batch),
+	}
+	// stop отменяет повторы и дожидается батчей в обработке.
+	stop := func(err error) error {
+		cancel()
+		pp.wait()
+		return err
+	}
+
+	reads := make(chan read)
+	go produce(ctx, p, reads)
+
+	var (
+		buffer  []any
+		cookies []int
+		timer   *time.Timer
+		flushC  <-chan time.Time
+	)
+	// Таймер один на весь цикл. Перед новым запуском он останавливается, а
+	// уже сработавший вычитывается, чтобы старое срабатывание не сбросило
+	// следующий буфер раньше времени.
+	disarm := func() {
+		if timer != nil && !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		flushC = nil
+	}
+	arm := func() {
+		disarm()
+		if timer == nil {
+			timer = time.NewTimer(opts.FlushInterval)
+		} else {
+			timer.Reset(opts.FlushInterval)
+		}
+		flushC = timer.C
+	}
+	defer disarm()
+	flush := func() {
+		disarm()
+		if len(cookies) == 0 {
+			return
+		}
+		pp.dispatch(buffer, cookies)
+		buffer, cookies = nil, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return stop(ctx.Err())
+		case <-flushC:
+			flush()
+		case b := <-pp.results:
+			pp.finish(b)
+		case r := <-reads:
+			if r.err != nil {
+				flush()
+				pp.wait()
+				if pp.err != nil {
+					return pp.err
+				}
+				return r.err
+			}
+			if len(cookies) == 0 && opts.FlushInterval > 0 {
+				arm()
+			}
+			buffer = append(buffer, r.items...)
+			cookies = append(cookies, r.cookie)
+			if len(buffer) >= opts.MaxItems {
+				flush()
+			}
+		}
+		if pp.err != nil {
+			return stop(pp.err)
+		}
+	}
+}
+
+// read — результат одного вызова Next.
+type read struct {
+	items  []any
+	cookie int
+	err    error
+}
+
+// produce читает из Producer до первой ошибки или отмены ctx.
+func produce(ctx context.Context, p Producer, out chan<- read) {
+	for {
+		items, cookie, err := p.Next()
+		select {
+		case out <- read{items, cookie, err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// batch — сброшенный буфер и cookie, которые он покрывает.
+type batch struct {
+	seq     int
+	items   []any
+	cookies []int
+	err     error // результат обработки
+}
+
+// pipe — состояние PipeWithOptions. Все поля, кроме results, меняются
+// только в горутине PipeWithOptions.
+type pipe struct {
+	ctx     context.Context
+	p       Producer
+	c       Consumer
+	opts    Options
+	results chan *batch // обработанные батчи; буфер на Workers, чтобы обработчики не блокировались
+
+	inflight int            // батчей в обработке
+	seq      int            // номер следующего сброшенного батча
+	next     int            // номер батча, который коммитится следующим
+	done     map[int]*batch // обработанные, но ещё не закоммиченные батчи
+	err      error          // первая ошибка обработки или коммита
+}
+
+// dispatch отдаёт батч в обработку, дожидаясь свободного обработчика.
+func (pp *pipe) dispatch(items []any, cookies []int) {
+	for pp.inflight >= pp.opts.Workers {
+		pp.finish(<-pp.results)
+	}
+	b := &batch{seq: pp.seq, items: items, cookies: cookies}
+	pp.seq++
+	pp.inflight++
+	go func() {
+		b.err = pp.process(b.items)
+		pp.results <- b
+	}()
+}
+
+// process вызывает Process с повторами; пустой батч обрабатывать нечего.
+func (pp *pipe) process(items []any) error {
+	if len(items) == 0 {
+		return nil
+	}
+	backoff := pp.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		err := pp.c.Process(items)
+		if err == nil || attempt == pp.opts.Retries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-pp.ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// finish принимает обработанный батч и коммитит всё, что теперь идёт подряд.
+// После первой ошибки больше ничего не коммитится.
+func (pp *pipe) finish(b *batch) {
+	pp.inflight--
+	pp.done[b.seq] = b
+	for pp.err == nil {
+		ready, ok := pp.done[pp.next]
+		if !ok {
+			return
+		}
+		delete(pp.done, pp.next)
+		if ready.err != nil {
+			pp.err = ready.err
+			return
+		}
+		for _, cookie := range ready.cookies {
+			if err := pp.p.Commit(cookie); err != nil {
+				pp.err = err
+				return
+			}
+		}
+		pp.next++
+	}
+}
+
+// wait дожидается всех батчей в обработке.
+func (pp *pipe) wait() {
+	for pp.inflight > 0 {
+		pp.finish(<-pp.results)
+	}
+}
+
 // --- Демо ---
 
 type mockProducer struct {
@@ -100,4 +338,15 @@ func main() {
 	c := &mockConsumer{}
 	err := Pipe(p, c)
 	fmt.Println("done:", err)
+
+	// Два обработчика: "processed" может выйти в любом порядке, а cookie
+	// всё равно коммитятся по порядку.
+	p = &mockProducer{batches: [][]any{
+		{1, 2, 3},
+		{4, 5},
+		{6},
+	}}
+	opts := Options{MaxItems: 2, FlushInterval: 50 * time.Millisecond, Workers: 2, Retries: 2, Backoff: 10 * time.Millisecond}
+	err = PipeWithOptions(context.Background(), p, c, opts)
+	fmt.Println("done:", err)
 }
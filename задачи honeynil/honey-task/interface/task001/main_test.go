@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errEOF = errors.New("EOF")
+
+// fakeProducer отдаёт заранее заданные батчи, затем errEOF. Cookie батча — его индекс.
+type fakeProducer struct {
+	batches   [][]any
+	pos       int
+	committed []int
+	// processedBefore[cookie] — сколько элементов обработано к моменту Commit(cookie).
+	processedBefore map[int]int
+	consumer        *fakeConsumer
+}
+
+func (p *fakeProducer) Next() ([]any, int, error) {
+	if p.pos >= len(p.batches) {
+		return nil, 0, errEOF
+	}
+	p.pos++
+	return p.batches[p.pos-1], p.pos - 1, nil
+}
+
+func (p *fakeProducer) Commit(cookie int) error {
+	p.committed = append(p.committed, cookie)
+	if p.processedBefore == nil {
+		p.processedBefore = make(map[int]int)
+	}
+	p.processedBefore[cookie] = len(p.consumer.items)
+	return nil
+}
+
+type fakeConsumer struct {
+	items  []any
+	calls  int
+	failAt int // номер вызова Process (с 1), который вернёт ошибку; 0 — никогда
+}
+
+var errProcess = errors.New("process failed")
+
+func (c *fakeConsumer) Process(items []any) error {
+	c.calls++
+	if c.calls == c.failAt {
+		return errProcess
+	}
+	c.items = append(c.items, items...)
+	return nil
+}
+
+func batchOf(from, n int) []any {
+	b := make([]any, n)
+	for i := range b {
+		b[i] = from + i
+	}
+	return b
+}
+
+func TestPipe(t *testing.T) {
+	tests := []struct {
+		name          string
+		batches       [][]any
+		failAt        int
+		wantErr       error
+		wantItems     int
+		wantCommitted []int
+	}{
+		{
+			name:          "small batches flushed at end",
+			batches:       [][]any{batchOf(0, 3), batchOf(3, 2), batchOf(5, 1)},
+			wantErr:       errEOF,
+			wantItems:     6,
+			wantCommitted: []int{0, 1, 2},
+		},
+		{
+			name:          "no batches",
+			wantErr:       errEOF,
+			wantCommitted: nil,
+		},
+		{
+			name:          "flush when buffer reaches MaxItems",
+			batches:       [][]any{batchOf(0, MaxItems-1), batchOf(MaxItems-1, 1), batchOf(MaxItems, 5)},
+			wantErr:       errEOF,
+			wantItems:     MaxItems + 5,
+			wantCommitted: []int{0, 1, 2},
+		},
+		{
+			name:          "process error stops pipe without commit",
+			batches:       [][]any{batchOf(0, 3), batchOf(3, 2)},
+			failAt:        1,
+			wantErr:       errProcess,
+			wantCommitted: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &fakeConsumer{failAt: tc.failAt}
+			p := &fakeProducer{batches: tc.batches, consumer: c}
+
+			err := Pipe(p, c)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Pipe() error = %v, want %v", err, tc.wantErr)
+			}
+			if len(c.items) != tc.wantItems {
+				t.Fatalf("processed %d items, want %d", len(c.items), tc.wantItems)
+			}
+			for i, v := range c.items {
+				if v != i {
+					t.Fatalf("item %d = %v: items must keep producer order", i, v)
+				}
+			}
+			if !slices.Equal(p.committed, tc.wantCommitted) {
+				t.Fatalf("committed %v, want %v", p.committed, tc.wantCommitted)
+			}
+		})
+	}
+}
+
+func TestPipeCommitsOnlyProcessedBatches(t *testing.T) {
+	batches := [][]any{batchOf(0, MaxItems), batchOf(MaxItems, 10), batchOf(MaxItems+10, 10)}
+	c := &fakeConsumer{}
+	p := &fakeProducer{batches: batches, consumer: c}
+
+	if err := Pipe(p, c); !errors.Is(err, errEOF) {
+		t.Fatalf("Pipe() error = %v, want EOF", err)
+	}
+
+	// Cookie можно коммитить только после того, как все элементы его батча обработаны.
+	end := 0
+	for cookie, b := range batches {
+		end += len(b)
+		got, ok := p.processedBefore[cookie]
+		if !ok {
+			t.Fatalf("cookie %d not committed", cookie)
+		}
+		if got < end {
+			t.Fatalf("cookie %d committed after %d processed items, want at least %d", cookie, got, end)
+		}
+	}
+}
+
+// asyncProducer — Producer для PipeWithOptions: Next и Commit вызываются из
+// разных горутин. Cookie задаются явно и могут идти в любом порядке. После
+// батчей Next ждёт закрытия block (если он задан) и возвращает errEOF.
+type asyncProducer struct {
+	batches  [][]any
+	cookies  []int
+	block    chan struct{}
+	consumer *asyncConsumer
+
+	mu        sync.Mutex
+	pos       int
+	committed []int
+	early     []int // cookie, закоммиченные до обработки их батча
+}
+
+func newAsyncProducer(c *asyncConsumer, cookies []int, batches ...[]any) *asyncProducer {
+	return &asyncProducer{batches: batches, cookies: cookies, consumer: c}
+}
+
+func (p *asyncProducer) Next() ([]any, int, error) {
+	p.mu.Lock()
+	pos := p.pos
+	p.pos++
+	p.mu.Unlock()
+	if pos >= len(p.batches) {
+		if p.block != nil {
+			<-p.block
+		}
+		return nil, 0, errEOF
+	}
+	return p.batches[pos], p.cookies[pos], nil
+}
+
+func (p *asyncProducer) Commit(cookie int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.committed = append(p.committed, cookie)
+	i := slices.Index(p.cookies, cookie)
+	for _, item := range p.batches[i] {
+		if !p.consumer.done(item) {
+			p.early = append(p.early, cookie)
+			break
+		}
+	}
+	return nil
+}
+
+func (p *asyncProducer) result() (committed, early []int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.committed), slices.Clone(p.early)
+}
+
+// asyncConsumer — потокобезопасный Consumer. hook вызывается перед каждой
+// попыткой: может ждать, спать или вернуть ошибку. attempt считается по
+// первому элементу батча, с 1.
+type asyncConsumer struct {
+	hook func(items []any, attempt int) error
+
+	mu          sync.Mutex
+	attempts    map[any]int
+	processed   map[any]bool
+	inflight    int
+	maxInflight int
+}
+
+func (c *asyncConsumer) Process(items []any) error {
+	c.mu.Lock()
+	if c.attempts == nil {
+		c.attempts, c.processed = make(map[any]int), make(map[any]bool)
+	}
+	c.attempts[items[0]]++
+	attempt := c.attempts[items[0]]
+	c.inflight++
+	c.maxInflight = max(c.maxInflight, c.inflight)
+	c.mu.Unlock()
+
+	var err error
+	if c.hook != nil {
+		err = c.hook(items, attempt)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight--
+	if err == nil {
+		for _, item := range items {
+			c.processed[item] = true
+		}
+	}
+	return err
+}
+
+func (c *asyncConsumer) done(item any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.processed[item]
+}
+
+func TestPipeWithOptionsCommitsInOrder(t *testing.T) {
+	// Первые батчи обрабатываются дольше, поэтому заканчиваются последними.
+	c := &asyncConsumer{hook: func(items []any, _ int) error {
+		time.Sleep(time.Duration(10-items[0].(int)) * 3 * time.Millisecond)
+		return nil
+	}}
+	cookies := []int{42, 7, 19, 3, 11}
+	p := newAsyncProducer(c, cookies, batchOf(0, 1), batchOf(1, 1), batchOf(2, 1), batchOf(3, 1), batchOf(4, 1))
+
+	err := PipeWithOptions(context.Background(), p, c, Options{MaxItems: 1, Workers: 4})
+	if !errors.Is(err, errEOF) {
+		t.Fatalf("PipeWithOptions() error = %v, want EOF", err)
+	}
+	committed, early := p.result()
+	if !slices.Equal(committed, cookies) {
+		t.Fatalf("committed %v, want %v in receive order", committed, cookies)
+	}
+	if len(early) > 0 {
+		t.Fatalf("cookies %v committed before their batches were processed", early)
+	}
+	if c.maxInflight < 2 {
+		t.Fatalf("max %d concurrent Process calls, want several", c.maxInflight)
+	}
+}
+
+func TestPipeWithOptionsRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		retries       int
+		wantErr       error
+		wantCommitted []int
+	}{
+		{"retries cover failures", 2, errEOF, []int{0, 1, 2}},
+		{"retries exhausted", 1, errProcess, []int{0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Батч с элементом 1 падает на первых двух попытках.
+			c := &asyncConsumer{hook: func(items []any, attempt int) error {
+				if items[0] == 1 && attempt <= 2 {
+					return errProcess
+				}
+				return nil
+			}}
+			p := newAsyncProducer(c, []int{0, 1, 2}, batchOf(0, 1), batchOf(1, 1), batchOf(2, 1))
+
+			opts := Options{MaxItems: 1, Workers: 2, Retries: tc.retries, Backoff: time.Millisecond}
+			err := PipeWithOptions(context.Background(), p, c, opts)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("PipeWithOptions() error = %v, want %v", err, tc.wantErr)
+			}
+			if committed, _ := p.result(); !slices.Equal(committed, tc.wantCommitted) {
+				t.Fatalf("committed %v, want %v", committed, tc.wantCommitted)
+			}
+		})
+	}
+}
+
+func TestPipeWithOptionsPartialFailure(t *testing.T) {
+	// Батч 2 падает, а батчи после него успевают обработаться раньше.
+	c := &asyncConsumer{hook: func(items []any, _ int) error {
+		switch items[0] {
+		case 2:
+			time.Sleep(20 * time.Millisecond)
+			return errProcess
+		case 0, 1:
+			time.Sleep(10 * time.Millisecond)
+		}
+		return nil
+	}}
+	p := newAsyncProducer(c, []int{0, 1, 2, 3, 4}, batchOf(0, 1), batchOf(1, 1), batchOf(2, 1), batchOf(3, 1), batchOf(4, 1))
+
+	err := PipeWithOptions(context.Background(), p, c, Options{MaxItems: 1, Workers: 5})
+	if !errors.Is(err, errProcess) {
+		t.Fatalf("PipeWithOptions() error = %v, want %v", err, errProcess)
+	}
+	committed, early := p.result()
+	if !slices.Equal(committed, []int{0, 1}) {
+		t.Fatalf("committed %v, want [0 1]: nothing after the failed batch", committed)
+	}
+	if len(early) > 0 {
+		t.Fatalf("cookies %v committed before their batches were processed", early)
+	}
+}
+
+func TestPipeWithOptionsFlushInterval(t *testing.T) {
+	c := &asyncConsumer{}
+	p := newAsyncProducer(c, []int{0, 1}, batchOf(0, 2), batchOf(2, 3))
+	p.block = make(chan struct{}) // после двух батчей producer замолкает
+	defer close(p.block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- PipeWithOptions(ctx, p, c, Options{FlushInterval: 20 * time.Millisecond}) }()
+
+	// До MaxItems далеко, поэтому батчи может сбросить только таймер.
+	deadline := time.Now().Add(time.Second)
+	for {
+		if committed, _ := p.result(); slices.Equal(committed, []int{0, 1}) {
+			break
+		}
+		if time.Now().After(deadline) {
+			committed, _ := p.result()
+			t.Fatalf("committed %v after 1s, want [0 1] flushed by FlushInterval", committed)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("PipeWithOptions() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPipeWithOptionsFlushEmptyBatch(t *testing.T) {
+	// После батчей producer замолкает: cookie коммитятся по таймеру, в том
+	// числе у пустого батча, для которого Process не вызывается.
+	tests := []struct {
+		name    string
+		batches [][]any
+		want    []int
+	}{
+		{"empty batch then timeout", [][]any{{}}, []int{0}},
+		{"empty batch then items", [][]any{{}, batchOf(0, 2)}, []int{0, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &asyncConsumer{}
+			p := newAsyncProducer(c, []int{0, 1}, tc.batches...)
+			p.block = make(chan struct{})
+			defer close(p.block)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errc := make(chan error, 1)
+			go func() { errc <- PipeWithOptions(ctx, p, c, Options{FlushInterval: 20 * time.Millisecond}) }()
+
+			deadline := time.Now().Add(time.Second)
+			for {
+				if committed, _ := p.result(); slices.Equal(committed, tc.want) {
+					break
+				}
+				if time.Now().After(deadline) {
+					committed, _ := p.result()
+					t.Fatalf("committed %v after 1s, want %v flushed by FlushInterval", committed, tc.want)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			cancel()
+			if err := <-errc; !errors.Is(err, context.Canceled) {
+				t.Fatalf("PipeWithOptions() error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestPipeWithOptionsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	// Батч 0 обрабатывается сразу, батч 1 отменяет ctx и доделывается уже
+	// после отмены, а батч 2 остаётся в буфере.
+	c := &asyncConsumer{hook: func(items []any, _ int) error {
+		if items[0] == 2 {
+			cancel()
+			<-release
+		}
+		return nil
+	}}
+	p := newAsyncProducer(c, []int{0, 1, 2}, batchOf(0, 2), batchOf(2, 2), batchOf(4, 1))
+	p.block = make(chan struct{})
+	defer close(p.block)
+
+	errc := make(chan error, 1)
+	go func() { errc <- PipeWithOptions(ctx, p, c, Options{MaxItems: 2, Workers: 2}) }()
+	<-ctx.Done()
+	close(release)
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("PipeWithOptions() error = %v, want context.Canceled", err)
+	}
+	committed, early := p.result()
+	if !slices.Equal(committed, []int{0, 1}) {
+		t.Fatalf("committed %v, want [0 1]: only fully processed batches", committed)
+	}
+	if len(early) > 0 {
+		t.Fatalf("cookies %v committed before their batches were processed", early)
+	}
+	if c.done(4) {
+		t.Fatal("buffered batch was processed after cancel")
+	}
+}
@@ -42,22 +42,24 @@ func (f *FileLogger) Close() error {
 // 4. Нет возможности сменить выход без изменения кода
 
 // SequentialLogger — потокобезопасная обёртка: гарантирует последовательное логирование.
+// Методы с pointer receiver: при value receiver каждый вызов блокировал бы
+// собственную копию mutex и никакой синхронизации не было бы.
 type SequentialLogger struct {
 	wrppedLogger Logger
 	mu           sync.Mutex
 }
 
-func NewSequentialLogger(wrppedLogger Logger) SequentialLogger {
-	return SequentialLogger{wrppedLogger: wrppedLogger}
+func NewSequentialLogger(wrppedLogger Logger) *SequentialLogger {
+	return &SequentialLogger{wrppedLogger: wrppedLogger}
 }
 
-func (sl SequentialLogger) Log(message string) error {
+func (sl *SequentialLogger) Log(message string) error {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
 	return sl.wrppedLogger.Log(message)
 }
 
-func (sl SequentialLogger) Close() error {
+func (sl *SequentialLogger) Close() error {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
 	return sl.wrppedLogger.Close()
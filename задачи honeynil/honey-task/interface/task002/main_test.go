@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// exclusiveLogger падает, если Log вызван конкурентно: сам по себе он не потокобезопасен.
+type exclusiveLogger struct {
+	inflight atomic.Int32
+	overlaps atomic.Int32
+	messages []string
+	closed   bool
+}
+
+func (l *exclusiveLogger) Log(message string) error {
+	if l.inflight.Add(1) > 1 {
+		l.overlaps.Add(1)
+	}
+	defer l.inflight.Add(-1)
+	l.messages = append(l.messages, message)
+	return nil
+}
+
+func (l *exclusiveLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	fl, err := NewFileLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"hello", "world"} {
+		if err := fl.Log(msg); err != nil {
+			t.Fatalf("Log(%q): %v", msg, err)
+		}
+	}
+	if err := fl.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "hello\nworld\n" {
+		t.Fatalf("file content = %q", got)
+	}
+}
+
+func TestSequentialLogger(t *testing.T) {
+	tests := []struct {
+		name       string
+		goroutines int
+		perWorker  int
+	}{
+		{name: "single writer", goroutines: 1, perWorker: 10},
+		{name: "many writers", goroutines: 50, perWorker: 100},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &exclusiveLogger{}
+			var logger Logger = NewSequentialLogger(inner)
+
+			var wg sync.WaitGroup
+			for g := 0; g < tc.goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < tc.perWorker; i++ {
+						if err := logger.Log(fmt.Sprintf("%d-%d", g, i)); err != nil {
+							t.Error(err)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := inner.overlaps.Load(); n > 0 {
+				t.Fatalf("wrapped logger called concurrently %d times", n)
+			}
+			if got, want := len(inner.messages), tc.goroutines*tc.perWorker; got != want {
+				t.Fatalf("logged %d messages, want %d", got, want)
+			}
+			if err := logger.Close(); err != nil || !inner.closed {
+				t.Fatalf("Close() = %v, closed = %v", err, inner.closed)
+			}
+		})
+	}
+}
+
+func TestSequentialLoggerKeepsPerWriterOrder(t *testing.T) {
+	inner := &exclusiveLogger{}
+	logger := NewSequentialLogger(inner)
+	for i := 0; i < 5; i++ {
+		logger.Log(fmt.Sprint(i))
+	}
+	if got := strings.Join(inner.messages, ","); got != "0,1,2,3,4" {
+		t.Fatalf("messages = %s", got)
+	}
+}
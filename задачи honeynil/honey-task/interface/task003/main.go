@@ -1,15 +1,19 @@
 package main
 
 // Задача: простой in-memory cache.
+// ShardedCache — то же с вытеснением (LRU, LFU, ARC, W-TinyLFU), TTL и шардированием.
 
 import (
 	"fmt"
+	"hash/maphash"
+	"math/bits"
 	"sync"
+	"time"
 )
 
-type Cache interface {
-	Set(k, v string)
-	Get(k string) (v string, ok bool)
+type Cache[K comparable, V any] interface {
+	Set(k K, v V)
+	Get(k K) (v V, ok bool)
 }
 
 // inMemoryCache — потокобезопасная реализация Cache.
@@ -18,7 +22,7 @@ type inMemoryCache struct {
 	data map[string]string
 }
 
-func NewCache() Cache {
+func NewCache() Cache[string, string] {
 	return &inMemoryCache{data: make(map[string]string)}
 }
 
@@ -35,6 +39,742 @@ func (c *inMemoryCache) Get(k string) (string, bool) {
 	return v, ok
 }
 
+// --- ShardedCache ---
+
+// Policy — политика вытеснения при заполнении кэша.
+type Policy int
+
+const (
+	LRU     Policy = iota // давно не использованные (по умолчанию)
+	LFU                   // редко использованные, без старения счётчиков
+	ARC                   // Adaptive Replacement Cache: баланс между свежестью и частотой
+	TinyLFU               // W-TinyLFU: LRU-окно, SLRU и допуск по частоте из count-min sketch
+)
+
+// EvictReason — почему запись ушла из кэша.
+type EvictReason int
+
+const (
+	ReasonCapacity EvictReason = iota // вытеснена политикой
+	ReasonExpired                     // истёк TTL
+	ReasonDeleted                     // удалена через Delete
+)
+
+// Options — настройки ShardedCache; нулевое значение — кэш без ограничений.
+type Options[K comparable, V any] struct {
+	// Capacity — сколько записей держит кэш; 0 — без ограничения и вытеснения.
+	// Ёмкость делится между шардами поровну с округлением вверх.
+	Capacity int
+	// Shards — число полос блокировки, округляется вверх до степени двойки.
+	// По умолчанию 16, а у кэша меньше чем на 1024 записи — 1, чтобы
+	// вытеснение оставалось точным.
+	Shards int
+	Policy Policy
+	// TTL — время жизни записей, добавленных через Set; 0 — бессрочно.
+	TTL time.Duration
+	// CleanupInterval — как часто фоново удалять истёкшие записи; 0 — только
+	// лениво, при обращении к ним.
+	CleanupInterval time.Duration
+	// OnEvict вызывается для каждой ушедшей записи вне блокировок кэша.
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+// Stats — счётчики обращений к кэшу.
+type Stats struct {
+	Hits, Misses, Evictions, Expirations uint64
+}
+
+// HitRatio — доля попаданий среди обращений Get.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// ShardedCache — кэш с вытеснением и TTL. Ключи распределяются по шардам по
+// хешу, у каждого шарда своя блокировка и свой экземпляр политики, поэтому
+// обращения к разным шардам не мешают друг другу.
+type ShardedCache[K comparable, V any] struct {
+	shards  []shard[K, V]
+	mask    uint64
+	seed    maphash.Seed
+	ttl     time.Duration
+	onEvict func(K, V, EvictReason)
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type shard[K comparable, V any] struct {
+	mu     sync.Mutex
+	items  map[K]entry[V]
+	policy policy[K] // nil — кэш без ограничения
+	stats  Stats
+}
+
+type entry[V any] struct {
+	value   V
+	expires int64 // UnixNano; 0 — бессрочно
+}
+
+func (e entry[V]) expired(now int64) bool { return e.expires != 0 && e.expires <= now }
+
+// eviction — ушедшая запись, о которой надо сообщить OnEvict.
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+func NewShardedCache[K comparable, V any](opts Options[K, V]) *ShardedCache[K, V] {
+	n := opts.Shards
+	if n <= 0 {
+		n = 16
+		if opts.Capacity > 0 && opts.Capacity < 1024 {
+			n = 1
+		}
+	}
+	n = 1 << bits.Len(uint(n-1)) // вверх до степени двойки
+
+	c := &ShardedCache[K, V]{
+		shards:  make([]shard[K, V], n),
+		mask:    uint64(n - 1),
+		seed:    maphash.MakeSeed(),
+		ttl:     opts.TTL,
+		onEvict: opts.OnEvict,
+		stop:    make(chan struct{}),
+	}
+	perShard := (opts.Capacity + n - 1) / n
+	for i := range c.shards {
+		c.shards[i].items = make(map[K]entry[V])
+		if opts.Capacity > 0 {
+			c.shards[i].policy = newPolicy(opts.Policy, perShard, c.hash)
+		}
+	}
+	if opts.CleanupInterval > 0 {
+		go c.cleanupLoop(opts.CleanupInterval)
+	}
+	return c
+}
+
+func (c *ShardedCache[K, V]) hash(k K) uint64 { return maphash.Comparable(c.seed, k) }
+
+func (c *ShardedCache[K, V]) shard(k K) *shard[K, V] { return &c.shards[c.hash(k)&c.mask] }
+
+// Set добавляет запись с TTL из Options.
+func (c *ShardedCache[K, V]) Set(k K, v V) { c.SetWithTTL(k, v, c.ttl) }
+
+// SetWithTTL добавляет запись со своим временем жизни; 0 — бессрочно.
+func (c *ShardedCache[K, V]) SetWithTTL(k K, v V, ttl time.Duration) {
+	e := entry[V]{value: v}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl).UnixNano()
+	}
+	s := c.shard(k)
+	var evicted *eviction[K, V]
+	s.mu.Lock()
+	_, exists := s.items[k]
+	s.items[k] = e
+	switch {
+	case s.policy == nil:
+	case exists:
+		s.policy.hit(k)
+	default:
+		if victim, ok := s.policy.add(k); ok {
+			old := s.items[victim]
+			delete(s.items, victim)
+			s.stats.Evictions++
+			evicted = &eviction[K, V]{victim, old.value, ReasonCapacity}
+		}
+	}
+	s.mu.Unlock()
+	if evicted != nil {
+		c.notify(*evicted)
+	}
+}
+
+func (c *ShardedCache[K, V]) Get(k K) (V, bool) {
+	s := c.shard(k)
+	s.mu.Lock()
+	e, ok := s.items[k]
+	if ok && e.expires != 0 && e.expired(time.Now().UnixNano()) {
+		s.removeLocked(k)
+		s.stats.Expirations++
+		s.stats.Misses++
+		s.mu.Unlock()
+		c.notify(eviction[K, V]{k, e.value, ReasonExpired})
+		var zero V
+		return zero, false
+	}
+	if ok {
+		s.stats.Hits++
+		if s.policy != nil {
+			s.policy.hit(k)
+		}
+	} else {
+		s.stats.Misses++
+	}
+	s.mu.Unlock()
+	return e.value, ok
+}
+
+// Delete удаляет запись и сообщает, была ли она.
+func (c *ShardedCache[K, V]) Delete(k K) bool {
+	s := c.shard(k)
+	s.mu.Lock()
+	e, ok := s.items[k]
+	if ok {
+		s.removeLocked(k)
+	}
+	s.mu.Unlock()
+	if ok {
+		c.notify(eviction[K, V]{k, e.value, ReasonDeleted})
+	}
+	return ok
+}
+
+func (s *shard[K, V]) removeLocked(k K) {
+	delete(s.items, k)
+	if s.policy != nil {
+		s.policy.remove(k)
+	}
+}
+
+// Len — число записей, включая истёкшие, но ещё не удалённые.
+func (c *ShardedCache[K, V]) Len() int {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		n += len(s.items)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+func (c *ShardedCache[K, V]) Stats() Stats {
+	var total Stats
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		total.Hits += s.stats.Hits
+		total.Misses += s.stats.Misses
+		total.Evictions += s.stats.Evictions
+		total.Expirations += s.stats.Expirations
+		s.mu.Unlock()
+	}
+	return total
+}
+
+// Close останавливает фоновую очистку; кэшем можно пользоваться и дальше.
+func (c *ShardedCache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.stop) })
+}
+
+func (c *ShardedCache[K, V]) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.removeExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// removeExpired обходит шарды по одному, чтобы не держать весь кэш под блокировкой.
+func (c *ShardedCache[K, V]) removeExpired() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		var expired []eviction[K, V]
+		now := time.Now().UnixNano()
+		s.mu.Lock()
+		for k, e := range s.items {
+			if e.expired(now) {
+				s.removeLocked(k)
+				s.stats.Expirations++
+				expired = append(expired, eviction[K, V]{k, e.value, ReasonExpired})
+			}
+		}
+		s.mu.Unlock()
+		c.notify(expired...)
+	}
+}
+
+func (c *ShardedCache[K, V]) notify(evs ...eviction[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, ev := range evs {
+		c.onEvict(ev.key, ev.value, ev.reason)
+	}
+}
+
+// --- Политики вытеснения ---
+
+// policy следит за ключами одного шарда и выбирает, кого вытеснить.
+// Методы вызываются под блокировкой шарда.
+type policy[K comparable] interface {
+	// add учитывает новый ключ и возвращает ключ, который надо вытеснить, если кэш переполнен.
+	add(k K) (victim K, evict bool)
+	// hit учитывает обращение к ключу, который есть в кэше.
+	hit(k K)
+	// remove забывает ключ, удалённый из кэша не политикой.
+	remove(k K)
+}
+
+func newPolicy[K comparable](p Policy, capacity int, hash func(K) uint64) policy[K] {
+	switch p {
+	case LFU:
+		return newLFU[K](capacity)
+	case ARC:
+		return newARC[K](capacity)
+	case TinyLFU:
+		return newTinyLFU(capacity, hash)
+	}
+	return newLRU[K](capacity)
+}
+
+// segment — список, в котором лежит узел: у ARC и W-TinyLFU их несколько.
+type segment uint8
+
+const (
+	segMain segment = iota
+	segT1
+	segT2
+	segB1
+	segB2
+	segWindow
+	segProbation
+	segProtected
+)
+
+type node[K comparable] struct {
+	key        K
+	prev, next *node[K]
+	seg        segment
+	freq       int // LFU
+}
+
+// list — двусвязный список узлов: голова — самый свежий, хвост — кандидат на вытеснение.
+type list[K comparable] struct {
+	root node[K]
+	len  int
+}
+
+func (l *list[K]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next, l.root.prev = &l.root, &l.root
+	}
+}
+
+func (l *list[K]) pushFront(n *node[K]) {
+	l.lazyInit()
+	n.prev, n.next = &l.root, l.root.next
+	l.root.next.prev = n
+	l.root.next = n
+	l.len++
+}
+
+func (l *list[K]) remove(n *node[K]) {
+	n.prev.next, n.next.prev = n.next, n.prev
+	n.prev, n.next = nil, nil
+	l.len--
+}
+
+func (l *list[K]) moveToFront(n *node[K]) {
+	l.remove(n)
+	l.pushFront(n)
+}
+
+// back — самый старый узел или nil.
+func (l *list[K]) back() *node[K] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// --- LRU ---
+
+type lruPolicy[K comparable] struct {
+	capacity int
+	order    list[K]
+	nodes    map[K]*node[K]
+}
+
+func newLRU[K comparable](capacity int) *lruPolicy[K] {
+	return &lruPolicy[K]{capacity: capacity, nodes: make(map[K]*node[K])}
+}
+
+func (p *lruPolicy[K]) add(k K) (K, bool) {
+	n := &node[K]{key: k}
+	p.nodes[k] = n
+	p.order.pushFront(n)
+	if p.order.len <= p.capacity {
+		var zero K
+		return zero, false
+	}
+	victim := p.order.back()
+	p.order.remove(victim)
+	delete(p.nodes, victim.key)
+	return victim.key, true
+}
+
+func (p *lruPolicy[K]) hit(k K) {
+	if n, ok := p.nodes[k]; ok {
+		p.order.moveToFront(n)
+	}
+}
+
+func (p *lruPolicy[K]) remove(k K) {
+	if n, ok := p.nodes[k]; ok {
+		p.order.remove(n)
+		delete(p.nodes, k)
+	}
+}
+
+// --- LFU ---
+
+// lfuPolicy — LFU за O(1): списки ключей по частоте обращений, внутри
+// списка — по свежести, чтобы из равных по частоте вытеснять давний.
+type lfuPolicy[K comparable] struct {
+	capacity int
+	nodes    map[K]*node[K]
+	freqs    map[int]*list[K]
+	minFreq  int
+}
+
+func newLFU[K comparable](capacity int) *lfuPolicy[K] {
+	return &lfuPolicy[K]{capacity: capacity, nodes: make(map[K]*node[K]), freqs: make(map[int]*list[K])}
+}
+
+func (p *lfuPolicy[K]) add(k K) (victim K, evict bool) {
+	if len(p.nodes) >= p.capacity {
+		if _, ok := p.freqs[p.minFreq]; !ok {
+			p.minFreq = p.lowestFreq() // минимальную частоту могли удалить через remove
+		}
+		n := p.freqs[p.minFreq].back()
+		p.unlink(n)
+		delete(p.nodes, n.key)
+		victim, evict = n.key, true
+	}
+	n := &node[K]{key: k, freq: 1}
+	p.nodes[k] = n
+	p.link(n)
+	p.minFreq = 1
+	return victim, evict
+}
+
+func (p *lfuPolicy[K]) hit(k K) {
+	n, ok := p.nodes[k]
+	if !ok {
+		return
+	}
+	p.unlink(n)
+	if n.freq == p.minFreq && p.freqs[n.freq] == nil {
+		p.minFreq++
+	}
+	n.freq++
+	p.link(n)
+}
+
+func (p *lfuPolicy[K]) remove(k K) {
+	if n, ok := p.nodes[k]; ok {
+		p.unlink(n)
+		delete(p.nodes, k)
+	}
+}
+
+func (p *lfuPolicy[K]) link(n *node[K]) {
+	l, ok := p.freqs[n.freq]
+	if !ok {
+		l = &list[K]{}
+		p.freqs[n.freq] = l
+	}
+	l.pushFront(n)
+}
+
+func (p *lfuPolicy[K]) unlink(n *node[K]) {
+	l := p.freqs[n.freq]
+	l.remove(n)
+	if l.len == 0 {
+		delete(p.freqs, n.freq)
+	}
+}
+
+func (p *lfuPolicy[K]) lowestFreq() int {
+	lowest := 0
+	for f := range p.freqs {
+		if lowest == 0 || f < lowest {
+			lowest = f
+		}
+	}
+	return lowest
+}
+
+// --- ARC ---
+
+// arcPolicy — Adaptive Replacement Cache (Megiddo, Modha, 2003). T1 — ключи,
+// к которым обращались один раз, T2 — больше одного; B1 и B2 — «призраки»
+// недавно вытесненных из них ключей без значений. Попадание в призрак
+// сдвигает целевой размер T1 (p) в пользу того списка, который ошибся.
+type arcPolicy[K comparable] struct {
+	c, p           int
+	t1, t2, b1, b2 list[K]
+	nodes          map[K]*node[K] // и записи, и призраки
+}
+
+func newARC[K comparable](capacity int) *arcPolicy[K] {
+	return &arcPolicy[K]{c: capacity, nodes: make(map[K]*node[K])}
+}
+
+func (p *arcPolicy[K]) list(s segment) *list[K] {
+	switch s {
+	case segT1:
+		return &p.t1
+	case segT2:
+		return &p.t2
+	case segB1:
+		return &p.b1
+	}
+	return &p.b2
+}
+
+func (p *arcPolicy[K]) move(n *node[K], to segment) {
+	p.list(n.seg).remove(n)
+	n.seg = to
+	p.list(to).pushFront(n)
+}
+
+// drop забывает узел совсем.
+func (p *arcPolicy[K]) drop(n *node[K]) {
+	p.list(n.seg).remove(n)
+	delete(p.nodes, n.key)
+}
+
+func (p *arcPolicy[K]) add(k K) (victim K, evict bool) {
+	if n, ok := p.nodes[k]; ok { // призрак: ключ недавно вытесняли
+		if n.seg == segB1 {
+			p.p = min(p.c, p.p+max(p.b2.len/p.b1.len, 1))
+		} else {
+			p.p = max(0, p.p-max(p.b1.len/p.b2.len, 1))
+		}
+		victim, evict = p.replace(n.seg == segB2)
+		p.move(n, segT2)
+		return victim, evict
+	}
+
+	switch total := p.t1.len + p.t2.len + p.b1.len + p.b2.len; {
+	case p.t1.len+p.b1.len >= p.c:
+		if p.t1.len < p.c {
+			p.drop(p.b1.back())
+			victim, evict = p.replace(false)
+		} else {
+			n := p.t1.back()
+			p.drop(n)
+			victim, evict = n.key, true
+		}
+	case total >= p.c:
+		if total >= 2*p.c {
+			p.drop(p.b2.back())
+		}
+		victim, evict = p.replace(false)
+	}
+	n := &node[K]{key: k, seg: segT1}
+	p.nodes[k] = n
+	p.t1.pushFront(n)
+	return victim, evict
+}
+
+// replace освобождает место под новый ключ, переводя запись из T1 или T2
+// в её призрачный список.
+func (p *arcPolicy[K]) replace(inB2 bool) (K, bool) {
+	if p.t1.len+p.t2.len < p.c {
+		var zero K
+		return zero, false // место есть: записи удаляли снаружи
+	}
+	n := p.t2.back()
+	if p.t1.len > 0 && (p.t1.len > p.p || inB2 && p.t1.len == p.p) || n == nil {
+		n = p.t1.back()
+		p.move(n, segB1)
+	} else {
+		p.move(n, segB2)
+	}
+	return n.key, true
+}
+
+func (p *arcPolicy[K]) hit(k K) {
+	if n, ok := p.nodes[k]; ok && (n.seg == segT1 || n.seg == segT2) {
+		p.move(n, segT2)
+	}
+}
+
+func (p *arcPolicy[K]) remove(k K) {
+	if n, ok := p.nodes[k]; ok && (n.seg == segT1 || n.seg == segT2) {
+		p.drop(n)
+	}
+}
+
+// --- W-TinyLFU ---
+
+// tinyLFUPolicy — W-TinyLFU (Einziger, Friedman, Manes, 2017): новые ключи
+// попадают в маленькое LRU-окно (1% ёмкости), вытесненные из окна — в
+// основную SLRU-часть (probation 20% и protected 80%), но только если по
+// оценке count-min sketch к ним обращаются чаще, чем к кандидату на
+// вытеснение оттуда. Так однократный проход по ключам не вымывает горячие.
+type tinyLFUPolicy[K comparable] struct {
+	hash                          func(K) uint64
+	freq                          *cmSketch
+	window, probation, protected  list[K]
+	windowCap, mainCap, protecCap int
+	nodes                         map[K]*node[K]
+}
+
+func newTinyLFU[K comparable](capacity int, hash func(K) uint64) *tinyLFUPolicy[K] {
+	windowCap := max(1, capacity/100)
+	mainCap := capacity - windowCap
+	return &tinyLFUPolicy[K]{
+		hash:      hash,
+		freq:      newCMSketch(capacity),
+		windowCap: windowCap,
+		mainCap:   mainCap,
+		protecCap: mainCap * 8 / 10,
+		nodes:     make(map[K]*node[K]),
+	}
+}
+
+func (p *tinyLFUPolicy[K]) list(s segment) *list[K] {
+	switch s {
+	case segWindow:
+		return &p.window
+	case segProbation:
+		return &p.probation
+	}
+	return &p.protected
+}
+
+func (p *tinyLFUPolicy[K]) add(k K) (K, bool) {
+	var zero K
+	p.freq.add(p.hash(k))
+	n := &node[K]{key: k, seg: segWindow}
+	p.nodes[k] = n
+	p.window.pushFront(n)
+	if p.window.len <= p.windowCap {
+		return zero, false
+	}
+
+	// Окно переполнено: его самый старый ключ просится в основную часть.
+	cand := p.window.back()
+	p.window.remove(cand)
+	if p.probation.len+p.protected.len < p.mainCap {
+		cand.seg = segProbation
+		p.probation.pushFront(cand)
+		return zero, false
+	}
+	victim := p.probation.back()
+	if victim == nil {
+		victim = p.protected.back()
+	}
+	if victim != nil && p.freq.estimate(p.hash(cand.key)) > p.freq.estimate(p.hash(victim.key)) {
+		p.list(victim.seg).remove(victim)
+		delete(p.nodes, victim.key)
+		cand.seg = segProbation
+		p.probation.pushFront(cand)
+		return victim.key, true
+	}
+	delete(p.nodes, cand.key)
+	return cand.key, true
+}
+
+func (p *tinyLFUPolicy[K]) hit(k K) {
+	p.freq.add(p.hash(k))
+	n, ok := p.nodes[k]
+	if !ok {
+		return
+	}
+	switch n.seg {
+	case segWindow:
+		p.window.moveToFront(n)
+	case segProtected:
+		p.protected.moveToFront(n)
+	case segProbation:
+		// Повторное обращение переводит ключ в protected, а самый старый
+		// ключ protected при переполнении возвращается в probation.
+		p.probation.remove(n)
+		n.seg = segProtected
+		p.protected.pushFront(n)
+		if p.protected.len > p.protecCap {
+			d := p.protected.back()
+			p.protected.remove(d)
+			d.seg = segProbation
+			p.probation.pushFront(d)
+		}
+	}
+}
+
+func (p *tinyLFUPolicy[K]) remove(k K) {
+	if n, ok := p.nodes[k]; ok {
+		p.list(n.seg).remove(n)
+		delete(p.nodes, k)
+	}
+}
+
+// cmSketch — count-min sketch с 4-битными по смыслу счётчиками (до 15);
+// строки вчетверо шире ёмкости, чтобы однократные ключи реже задевали
+// счётчики горячих.
+// Когда число добавлений доходит до 10 ёмкостей кэша, все счётчики
+// делятся пополам: старая популярность постепенно забывается.
+type cmSketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+var cmSeeds = [4]uint64{0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325}
+
+func newCMSketch(capacity int) *cmSketch {
+	width := 1 << bits.Len(uint(4*max(capacity, 16)-1))
+	s := &cmSketch{mask: uint64(width - 1), resetAt: 10 * max(capacity, 1)}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *cmSketch) index(h uint64, row int) uint64 {
+	x := (h + cmSeeds[row]) * 0x9e3779b97f4a7c15
+	return (x ^ x>>32) & s.mask
+}
+
+func (s *cmSketch) add(h uint64) {
+	for i := range s.rows {
+		if c := &s.rows[i][s.index(h, i)]; *c < 15 {
+			*c++
+		}
+	}
+	if s.additions++; s.additions >= s.resetAt {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] /= 2
+			}
+		}
+		s.additions /= 2
+	}
+}
+
+func (s *cmSketch) estimate(h uint64) uint8 {
+	est := uint8(15)
+	for i := range s.rows {
+		est = min(est, s.rows[i][s.index(h, i)])
+	}
+	return est
+}
+
 func main() {
 	c := NewCache()
 	c.Set("name", "Alice")
@@ -42,4 +782,18 @@ func main() {
 	fmt.Println(v, ok) // Alice true
 	_, ok2 := c.Get("missing")
 	fmt.Println(ok2) // false
+
+	sc := NewShardedCache(Options[string, int]{
+		Capacity: 2,
+		Policy:   LRU,
+		OnEvict: func(k string, v int, reason EvictReason) {
+			fmt.Println("evicted", k, v) // evicted b 2
+		},
+	})
+	sc.Set("a", 1)
+	sc.Set("b", 2)
+	sc.Get("a")
+	sc.Set("c", 3) // b давно не использовался
+	_, ok = sc.Get("b")
+	fmt.Println(ok, sc.Stats().Evictions) // false 1
 }
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// implementations — кэши, которые должны вести себя одинаково, пока не заполнены.
+func implementations() []struct {
+	name string
+	new  func() Cache[string, string]
+} {
+	impls := []struct {
+		name string
+		new  func() Cache[string, string]
+	}{
+		{"single-lock", NewCache},
+		{"sharded", func() Cache[string, string] {
+			return NewShardedCache(Options[string, string]{Shards: 16})
+		}},
+	}
+	for _, p := range []struct {
+		name   string
+		policy Policy
+	}{{"LRU", LRU}, {"LFU", LFU}, {"ARC", ARC}, {"TinyLFU", TinyLFU}} {
+		impls = append(impls, struct {
+			name string
+			new  func() Cache[string, string]
+		}{p.name, func() Cache[string, string] {
+			return NewShardedCache(Options[string, string]{Capacity: 1 << 14, Shards: 4, Policy: p.policy})
+		}})
+	}
+	return impls
+}
+
+func TestCache(t *testing.T) {
+	type op struct {
+		set       bool
+		key, val  string
+		wantOK    bool
+		wantValue string
+	}
+	tests := []struct {
+		name string
+		ops  []op
+	}{
+		{
+			name: "get missing key",
+			ops:  []op{{key: "a", wantOK: false}},
+		},
+		{
+			name: "set then get",
+			ops: []op{
+				{set: true, key: "name", val: "Alice"},
+				{key: "name", wantOK: true, wantValue: "Alice"},
+			},
+		},
+		{
+			name: "overwrite",
+			ops: []op{
+				{set: true, key: "k", val: "1"},
+				{set: true, key: "k", val: "2"},
+				{key: "k", wantOK: true, wantValue: "2"},
+			},
+		},
+		{
+			name: "empty value is stored",
+			ops: []op{
+				{set: true, key: "k", val: ""},
+				{key: "k", wantOK: true, wantValue: ""},
+			},
+		},
+	}
+
+	for _, impl := range implementations() {
+		for _, tc := range tests {
+			t.Run(impl.name+"/"+tc.name, func(t *testing.T) {
+				c := impl.new()
+				for _, o := range tc.ops {
+					if o.set {
+						c.Set(o.key, o.val)
+						continue
+					}
+					v, ok := c.Get(o.key)
+					if ok != o.wantOK || v != o.wantValue {
+						t.Fatalf("Get(%q) = %q, %v; want %q, %v", o.key, v, ok, o.wantValue, o.wantOK)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	for _, impl := range implementations() {
+		t.Run(impl.name, func(t *testing.T) { testConcurrent(t, impl.new()) })
+	}
+}
+
+func testConcurrent(t *testing.T, c Cache[string, string]) {
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				c.Set(fmt.Sprintf("%d-%d", g, i), fmt.Sprint(i))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				c.Get(fmt.Sprintf("%d-%d", g, i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for g := 0; g < 20; g++ {
+		if v, ok := c.Get(fmt.Sprintf("%d-199", g)); !ok || v != "199" {
+			t.Fatalf("Get(%d-199) = %q, %v", g, v, ok)
+		}
+	}
+}
+
+func TestEvictionPolicies(t *testing.T) {
+	// Ключ hot прочитан дважды, затем идёт однократный проход по 20 ключам:
+	// LRU его вытесняет, политики с учётом частоты — нет.
+	tests := []struct {
+		policy  Policy
+		name    string
+		keepHot bool
+	}{
+		{LRU, "LRU", false},
+		{LFU, "LFU", true},
+		{ARC, "ARC", true},
+		{TinyLFU, "TinyLFU", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []string
+			c := NewShardedCache(Options[string, int]{
+				Capacity: 4,
+				Policy:   tc.policy,
+				OnEvict: func(k string, _ int, reason EvictReason) {
+					if reason != ReasonCapacity {
+						t.Errorf("OnEvict(%q) reason = %v, want ReasonCapacity", k, reason)
+					}
+					evicted = append(evicted, k)
+				},
+			})
+			c.Set("hot", 1)
+			c.Get("hot")
+			c.Get("hot")
+			for i := range 20 {
+				c.Set(fmt.Sprint("scan", i), i)
+			}
+
+			if _, ok := c.Get("hot"); ok != tc.keepHot {
+				t.Fatalf("hot key kept = %v, want %v", ok, tc.keepHot)
+			}
+			if n := c.Len(); n != 4 {
+				t.Fatalf("Len() = %d, want 4", n)
+			}
+			if s := c.Stats(); s.Evictions != 17 || len(evicted) != 17 {
+				t.Fatalf("Evictions = %d, callbacks = %d, want 17", s.Evictions, len(evicted))
+			}
+		})
+	}
+}
+
+func TestLRUEvictsLeastRecent(t *testing.T) {
+	c := NewShardedCache(Options[string, int]{Capacity: 2})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b survived, want it evicted as least recently used")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Fatalf("%s was evicted", k)
+		}
+	}
+}
+
+func TestLFUEvictsLeastFrequent(t *testing.T) {
+	c := NewShardedCache(Options[string, int]{Capacity: 3, Policy: LFU})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("c")
+	c.Set("d", 4) // b прочитан реже всех
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b survived, want it evicted as least frequently used")
+	}
+	c.Delete("c")
+	c.Set("e", 5)
+	c.Set("f", 6) // d и e — по одному обращению, d давнее
+	if _, ok := c.Get("d"); ok {
+		t.Fatal("d survived, want the older of equally used keys evicted")
+	}
+}
+
+func TestARCAdaptsToGhostHits(t *testing.T) {
+	p := newARC[int](4)
+	evict := func(k int) (int, bool) { return p.add(k) }
+	for k := range 4 {
+		evict(k)
+	}
+	p.hit(0)
+	p.hit(1) // T1 = {3, 2}, T2 = {1, 0}
+	if v, ok := evict(4); !ok || v != 2 {
+		t.Fatalf("add(4) evicted %d, %v; want 2 from T1", v, ok)
+	}
+	if p.p != 0 {
+		t.Fatalf("p = %d before ghost hit, want 0", p.p)
+	}
+	evict(2) // 2 в призраках B1: T1 надо было дать больше места
+	if p.p == 0 {
+		t.Fatal("p did not grow after a hit in B1")
+	}
+	if n := p.nodes[2]; n.seg != segT2 {
+		t.Fatalf("ghost hit placed key in segment %d, want T2", n.seg)
+	}
+	if live := p.t1.len + p.t2.len; live != 4 {
+		t.Fatalf("|T1|+|T2| = %d, want 4", live)
+	}
+	if ghosts := p.b1.len + p.b2.len; ghosts > 4 {
+		t.Fatalf("%d ghosts, want at most capacity", ghosts)
+	}
+}
+
+func TestTinyLFURejectsRareCandidates(t *testing.T) {
+	c := NewShardedCache(Options[int, int]{Capacity: 100, Policy: TinyLFU})
+	for k := range 100 {
+		c.Set(k, k)
+		for range 3 {
+			c.Get(k)
+		}
+	}
+	for k := 1000; k < 2000; k++ {
+		c.Set(k, k) // каждый ключ — один раз
+	}
+	kept := 0
+	for k := range 100 {
+		if _, ok := c.Get(k); ok {
+			kept++
+		}
+	}
+	if kept < 75 {
+		t.Fatalf("kept %d of 100 hot keys after a scan, want at least 75", kept)
+	}
+}
+
+func TestCMSketchAging(t *testing.T) {
+	s := newCMSketch(16)
+	for range 10 {
+		s.add(42)
+	}
+	if e := s.estimate(42); e != 10 {
+		t.Fatalf("estimate = %d, want 10", e)
+	}
+	for h := range uint64(s.resetAt) {
+		s.add(h * 0x9e3779b97f4a7c15)
+	}
+	if e := s.estimate(42); e >= 10 {
+		t.Fatalf("estimate after reset = %d, want counters halved", e)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	var mu sync.Mutex
+	var reasons []EvictReason
+	c := NewShardedCache(Options[string, int]{
+		TTL: 20 * time.Millisecond,
+		OnEvict: func(_ string, _ int, reason EvictReason) {
+			mu.Lock()
+			reasons = append(reasons, reason)
+			mu.Unlock()
+		},
+	})
+	c.Set("short", 1)
+	c.SetWithTTL("forever", 2, 0)
+	if _, ok := c.Get("short"); !ok {
+		t.Fatal("entry expired too early")
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Fatal("Get returned an expired entry")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Fatal("entry without TTL expired")
+	}
+	if s := c.Stats(); s.Expirations != 1 || s.Hits != 2 || s.Misses != 1 {
+		t.Fatalf("Stats() = %+v, want 1 expiration, 2 hits, 1 miss", s)
+	}
+	if !slices.Equal(reasons, []EvictReason{ReasonExpired}) {
+		t.Fatalf("OnEvict reasons = %v, want [ReasonExpired]", reasons)
+	}
+}
+
+func TestBackgroundExpiry(t *testing.T) {
+	var expired atomic.Int64
+	c := NewShardedCache(Options[int, int]{
+		Capacity:        100,
+		Policy:          ARC,
+		TTL:             10 * time.Millisecond,
+		CleanupInterval: 5 * time.Millisecond,
+		OnEvict:         func(int, int, EvictReason) { expired.Add(1) },
+	})
+	defer c.Close()
+	for k := range 50 {
+		c.Set(k, k)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Len() = %d after a second, want expired entries removed without Get", c.Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := expired.Load(); n != 50 {
+		t.Fatalf("OnEvict called %d times, want 50", n)
+	}
+	// Место освободилось и в политике: новые записи ничего не вытесняют.
+	for k := 100; k < 200; k++ {
+		c.SetWithTTL(k, k, 0)
+	}
+	if s := c.Stats(); s.Evictions != 0 || s.Expirations != 50 {
+		t.Fatalf("Stats() = %+v, want 0 evictions, 50 expirations", s)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	var reason EvictReason = -1
+	c := NewShardedCache(Options[string, int]{
+		Capacity: 2,
+		Policy:   TinyLFU,
+		OnEvict:  func(_ string, _ int, r EvictReason) { reason = r },
+	})
+	c.Set("a", 1)
+	if !c.Delete("a") || c.Delete("a") {
+		t.Fatal("Delete should report true once, then false")
+	}
+	if reason != ReasonDeleted {
+		t.Fatalf("OnEvict reason = %v, want ReasonDeleted", reason)
+	}
+	c.Set("b", 2)
+	c.Set("c", 3)
+	if c.Len() != 2 || c.Stats().Evictions != 0 {
+		t.Fatalf("Len() = %d, Evictions = %d after delete freed a slot", c.Len(), c.Stats().Evictions)
+	}
+}
+
+func TestShardedCapacityUnderContention(t *testing.T) {
+	for _, policy := range []Policy{LRU, LFU, ARC, TinyLFU} {
+		c := NewShardedCache(Options[int, int]{Capacity: 256, Shards: 8, Policy: policy})
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rng := rand.New(rand.NewPCG(uint64(g), 0))
+				for range 5000 {
+					k := rng.IntN(2048)
+					switch rng.IntN(10) {
+					case 0:
+						c.Delete(k)
+					case 1, 2, 3:
+						c.Set(k, k)
+					default:
+						if v, ok := c.Get(k); ok && v != k {
+							t.Errorf("Get(%d) = %d", k, v)
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if n := c.Len(); n > 256 {
+			t.Fatalf("policy %d: Len() = %d, want at most 256", policy, n)
+		}
+	}
+}
+
+// BenchmarkCache сравнивает кэш с одной блокировкой и шардированный при
+// смешанной нагрузке: ключи по закону Ципфа, доля записей — в имени.
+func BenchmarkCache(b *testing.B) {
+	keys := make([]string, 1<<16)
+	for i := range keys {
+		keys[i] = fmt.Sprint("key-", i)
+	}
+	// Последовательность обращений готовится заранее, чтобы в замер не
+	// попадала генерация распределения.
+	rng := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(rng, 1.1, 1, uint64(len(keys)-1))
+	trace := make([]struct {
+		key   string
+		write int
+	}, 1<<20)
+	for i := range trace {
+		trace[i].key, trace[i].write = keys[zipf.Uint64()], rng.IntN(100)
+	}
+	caches := []struct {
+		name string
+		new  func() Cache[string, string]
+	}{
+		{"single-lock", NewCache},
+		{"sharded", func() Cache[string, string] { return NewShardedCache(Options[string, string]{}) }},
+	}
+	for _, p := range []struct {
+		name   string
+		policy Policy
+	}{{"LRU", LRU}, {"LFU", LFU}, {"ARC", ARC}, {"TinyLFU", TinyLFU}} {
+		caches = append(caches, struct {
+			name string
+			new  func() Cache[string, string]
+		}{"sharded-" + p.name, func() Cache[string, string] {
+			return NewShardedCache(Options[string, string]{Capacity: len(keys) / 8, Policy: p.policy})
+		}})
+	}
+
+	for _, writes := range []int{10, 50} {
+		for _, cc := range caches {
+			b.Run(fmt.Sprintf("%s/writes=%d%%", cc.name, writes), func(b *testing.B) {
+				c := cc.new()
+				for _, k := range keys[:len(keys)/8] {
+					c.Set(k, k)
+				}
+				var worker atomic.Uint64
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := int(worker.Add(1) * 7919) // у каждой горутины свой участок trace
+					for pb.Next() {
+						op := trace[i&(len(trace)-1)]
+						if op.write < writes {
+							c.Set(op.key, op.key)
+						} else {
+							c.Get(op.key)
+						}
+						i++
+					}
+				})
+				if sc, ok := c.(*ShardedCache[string, string]); ok {
+					b.ReportMetric(sc.Stats().HitRatio(), "hit-ratio")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []any
+}
+
+func (h *recordingHandler) Handle(e Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, e.Data)
+	return nil
+}
+
+func (h *recordingHandler) got() []any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.events)
+}
+
+func TestEventBus(t *testing.T) {
+	tests := []struct {
+		name      string
+		subscribe []string // тип событий для каждого обработчика
+		publish   []Event
+		want      [][]any // полученные данные для каждого обработчика
+	}{
+		{
+			name:      "single handler keeps order",
+			subscribe: []string{"a"},
+			publish:   []Event{{"a", 1}, {"a", 2}, {"a", 3}},
+			want:      [][]any{{1, 2, 3}},
+		},
+		{
+			name:      "fan-out to every subscriber",
+			subscribe: []string{"a", "a"},
+			publish:   []Event{{"a", "x"}, {"a", "y"}},
+			want:      [][]any{{"x", "y"}, {"x", "y"}},
+		},
+		{
+			name:      "only matching type delivered",
+			subscribe: []string{"a", "b"},
+			publish:   []Event{{"a", 1}, {"b", 2}, {"c", 3}},
+			want:      [][]any{{1}, {2}},
+		},
+		{
+			name:    "publish without subscribers",
+			publish: []Event{{"a", 1}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var bus EventBus = NewSimpleEventBus()
+			handlers := make([]*recordingHandler, len(tc.subscribe))
+			for i, typ := range tc.subscribe {
+				handlers[i] = &recordingHandler{}
+				if err := bus.Subscribe(typ, handlers[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, e := range tc.publish {
+				if err := bus.Publish(e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Close дожидается доставки всех опубликованных событий.
+			if err := bus.Close(); err != nil {
+				t.Fatal(err)
+			}
+			for i, h := range handlers {
+				if got := h.got(); !slices.Equal(got, tc.want[i]) {
+					t.Fatalf("handler %d got %v, want %v", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventBusUnsubscribe(t *testing.T) {
+	bus := NewSimpleEventBus()
+	h := &recordingHandler{}
+	bus.Subscribe("a", h)
+	bus.Publish(Event{"a", 1})
+
+	if err := bus.Unsubscribe("a", h); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if err := bus.Unsubscribe("a", h); err == nil {
+		t.Fatal("second Unsubscribe must fail: handler is not subscribed")
+	}
+	bus.Publish(Event{"a", 2})
+	bus.Close()
+
+	if got := h.got(); !slices.Equal(got, []any{1}) {
+		t.Fatalf("got %v, want [1]", got)
+	}
+}
+
+func TestEventBusConcurrentPublish(t *testing.T) {
+	bus := NewSimpleEventBus()
+	h := &recordingHandler{}
+	bus.Subscribe("a", h)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				bus.Publish(Event{"a", fmt.Sprintf("%d-%d", g, i)})
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Close()
+
+	if got := len(h.got()); got != 200 {
+		t.Fatalf("delivered %d events, want 200", got)
+	}
+}
+
+func TestEventBusPublishAfterClose(t *testing.T) {
+	bus := NewSimpleEventBus()
+	bus.Subscribe("a", &recordingHandler{})
+	bus.Close()
+	// Главное — не паниковать отправкой в закрытый канал.
+	bus.Publish(Event{"a", 1})
+}
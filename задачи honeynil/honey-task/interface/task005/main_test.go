@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllow(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     int
+		capacity int
+		calls    int
+		want     int
+	}{
+		{name: "burst up to capacity", rate: 1, capacity: 5, calls: 5, want: 5},
+		{name: "deny above capacity", rate: 1, capacity: 3, calls: 10, want: 3},
+		{name: "capacity one", rate: 1, capacity: 1, calls: 2, want: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var rl RateLimiter = NewTokenBucket(tc.rate, tc.capacity)
+			allowed := 0
+			for i := 0; i < tc.calls; i++ {
+				if rl.Allow() {
+					allowed++
+				}
+			}
+			if allowed != tc.want {
+				t.Fatalf("allowed %d of %d, want %d", allowed, tc.calls, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	tb := NewTokenBucket(100, 1) // один токен каждые 10ms
+	if !tb.Allow() {
+		t.Fatal("first Allow must succeed")
+	}
+	if tb.Allow() {
+		t.Fatal("bucket must be empty")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !tb.Allow() {
+		t.Fatal("token must be refilled after 30ms at 100/s")
+	}
+}
+
+func TestTokenBucketWaitN(t *testing.T) {
+	tb := NewTokenBucket(100, 5)
+	for tb.Allow() {
+	}
+
+	start := time.Now()
+	if err := tb.WaitN(3); err != nil {
+		t.Fatal(err)
+	}
+	// 3 токена при 100/с — около 30ms.
+	if d := time.Since(start); d < 20*time.Millisecond || d > time.Second {
+		t.Fatalf("WaitN(3) took %v, want ~30ms", d)
+	}
+}
+
+func TestTokenBucketWaitContext(t *testing.T) {
+	tb := NewTokenBucket(1, 1)
+	tb.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tb.WaitNContext(ctx, 1); err == nil {
+		t.Fatal("WaitNContext must fail when context is done before a token is available")
+	}
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	tb := NewTokenBucket(1, 20)
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tb.Allow() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	// Допускаем один токен пополнения, пока горутины стартуют.
+	if n := allowed.Load(); n < 20 || n > 21 {
+		t.Fatalf("allowed %d concurrent requests, want 20", n)
+	}
+}
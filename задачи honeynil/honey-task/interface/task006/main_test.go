@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolRunsAllTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		workers   int
+		queueSize int
+		tasks     int
+	}{
+		{name: "one worker", workers: 1, queueSize: 1, tasks: 10},
+		{name: "more tasks than queue", workers: 3, queueSize: 2, tasks: 50},
+		{name: "no tasks", workers: 2, queueSize: 2, tasks: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var pool WorkerPool = NewWorkerPool(tc.workers, tc.queueSize)
+			if err := pool.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			var done, inflight, peak atomic.Int32
+			for i := 0; i < tc.tasks; i++ {
+				err := pool.Submit(func() error {
+					n := inflight.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(time.Millisecond)
+					inflight.Add(-1)
+					done.Add(1)
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := pool.Shutdown(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := done.Load(); int(got) != tc.tasks {
+				t.Fatalf("Shutdown returned after %d of %d tasks", got, tc.tasks)
+			}
+			if p := peak.Load(); int(p) > tc.workers {
+				t.Fatalf("%d tasks ran concurrently, limit is %d", p, tc.workers)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolSubmitAfterShutdown(t *testing.T) {
+	pool := NewWorkerPool(1, 1)
+	pool.Start(context.Background())
+	pool.Shutdown()
+
+	if err := pool.Submit(func() error { return nil }); err == nil {
+		t.Fatal("Submit after Shutdown must fail")
+	}
+	// Повторный Shutdown не должен паниковать на закрытии канала.
+	if err := pool.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkerPoolShutdownDuringSubmit(t *testing.T) {
+	pool := NewWorkerPool(2, 4)
+	pool.Start(context.Background())
+
+	var accepted, executed atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if pool.Submit(func() error { executed.Add(1); return nil }) == nil {
+					accepted.Add(1)
+				}
+			}
+		}()
+	}
+	time.Sleep(time.Millisecond)
+	pool.Shutdown()
+	wg.Wait()
+
+	// Каждая принятая задача должна быть выполнена, отклонённые — нет.
+	if a, e := accepted.Load(), executed.Load(); a != e {
+		t.Fatalf("accepted %d tasks, executed %d", a, e)
+	}
+}
+
+func TestWorkerPoolShutdownNow(t *testing.T) {
+	pool := NewWorkerPool(1, 10)
+	pool.Start(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var executed atomic.Int32
+	pool.Submit(func() error {
+		close(started)
+		<-release
+		executed.Add(1)
+		return nil
+	})
+	for i := 0; i < 5; i++ {
+		pool.Submit(func() error { executed.Add(1); return nil })
+	}
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		pool.ShutdownNow()
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ShutdownNow did not return")
+	}
+	if n := executed.Load(); n == 6 {
+		t.Fatal("ShutdownNow must drop queued tasks")
+	}
+}
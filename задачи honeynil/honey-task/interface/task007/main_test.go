@@ -0,0 +1,153 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInMemoryRepository(t *testing.T) {
+	var repo Repository = NewInMemoryRepository()
+
+	if _, err := repo.GetByID(1); err == nil {
+		t.Fatal("GetByID on empty repository must fail")
+	}
+	if err := repo.Delete(1); err == nil {
+		t.Fatal("Delete of missing user must fail")
+	}
+
+	users := []User{{1, "Alice", "a@mail"}, {2, "Bob", "b@mail"}}
+	for _, u := range users {
+		if err := repo.Save(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo.Save(User{1, "Alice2", "a2@mail"}) // перезапись
+
+	u, err := repo.GetByID(1)
+	if err != nil || u.Name != "Alice2" {
+		t.Fatalf("GetByID(1) = %v, %v; want Alice2", u, err)
+	}
+	all, _ := repo.GetAll()
+	ids := make([]int, 0, len(all))
+	for _, u := range all {
+		ids = append(ids, u.ID)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []int{1, 2}) {
+		t.Fatalf("GetAll ids = %v, want [1 2]", ids)
+	}
+
+	if err := repo.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(2); err == nil {
+		t.Fatal("deleted user must not be found")
+	}
+}
+
+// countingRepository считает обращения к нижележащему хранилищу.
+type countingRepository struct {
+	Repository
+	mu   sync.Mutex
+	gets int
+}
+
+func (r *countingRepository) GetByID(id int) (User, error) {
+	r.mu.Lock()
+	r.gets++
+	r.mu.Unlock()
+	return r.Repository.GetByID(id)
+}
+
+func (r *countingRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gets
+}
+
+func TestCachedRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		between  func(c *CachedRepository) // действие между двумя GetByID
+		wantGets int
+		wantName string
+	}{
+		{
+			name:     "second read served from cache",
+			ttl:      time.Minute,
+			between:  func(*CachedRepository) {},
+			wantGets: 1,
+			wantName: "Alice",
+		},
+		{
+			name:     "entry expires after ttl",
+			ttl:      10 * time.Millisecond,
+			between:  func(*CachedRepository) { time.Sleep(20 * time.Millisecond) },
+			wantGets: 2,
+			wantName: "Alice",
+		},
+		{
+			name:     "save invalidates cache",
+			ttl:      time.Minute,
+			between:  func(c *CachedRepository) { c.Save(User{1, "Alice2", "a@mail"}) },
+			wantGets: 2,
+			wantName: "Alice2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &countingRepository{Repository: NewInMemoryRepository()}
+			inner.Save(User{1, "Alice", "a@mail"})
+			cached := NewCachedRepository(inner, tc.ttl)
+
+			cached.GetByID(1)
+			tc.between(cached)
+			u, err := cached.GetByID(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Name != tc.wantName {
+				t.Fatalf("GetByID(1).Name = %q, want %q", u.Name, tc.wantName)
+			}
+			if got := inner.count(); got != tc.wantGets {
+				t.Fatalf("underlying GetByID called %d times, want %d", got, tc.wantGets)
+			}
+		})
+	}
+}
+
+func TestCachedRepositoryDelete(t *testing.T) {
+	cached := NewCachedRepository(NewInMemoryRepository(), time.Minute)
+	cached.Save(User{1, "Alice", "a@mail"})
+	cached.GetByID(1)
+
+	if err := cached.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.GetByID(1); err == nil {
+		t.Fatal("deleted user must not be served from cache")
+	}
+}
+
+func TestCachedRepositoryConcurrent(t *testing.T) {
+	cached := NewCachedRepository(NewInMemoryRepository(), time.Millisecond)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cached.Save(User{ID: i % 5, Name: "u"})
+				cached.GetByID(i % 5)
+				if i%7 == 0 {
+					cached.Delete(i % 5)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		name        string
+		enc         Encoder
+		contentType string
+		contains    string
+	}{
+		{name: "json", enc: &JSONEncoder{}, contentType: "application/json", contains: `"name":"test"`},
+		{name: "xml", enc: &XMLEncoder{}, contentType: "application/xml", contains: "<name>test</name>"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if ct := tc.enc.ContentType(); ct != tc.contentType {
+				t.Fatalf("ContentType() = %q, want %q", ct, tc.contentType)
+			}
+			in := Sample{Name: "test", Value: 42}
+			b, err := tc.enc.Encode(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tc.contains) {
+				t.Fatalf("Encode() = %s, want it to contain %s", b, tc.contains)
+			}
+			var out Sample
+			if err := tc.enc.Decode(b, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.Name != in.Name || out.Value != in.Value {
+				t.Fatalf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+// failingEncoder всегда возвращает ошибку кодирования.
+type failingEncoder struct{ JSONEncoder }
+
+var errEncode = errors.New("encode failed")
+
+func (*failingEncoder) Encode(any) ([]byte, error) { return nil, errEncode }
+
+func TestCompositeEncoder(t *testing.T) {
+	var c MultiEncoder = NewCompositeEncoder()
+	c.AddEncoder("json", &JSONEncoder{})
+	c.AddEncoder("xml", &XMLEncoder{})
+
+	res, err := c.Encode(Sample{Name: "x", Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res["json"] == nil || res["xml"] == nil {
+		t.Fatalf("Encode() keys = %v, want json and xml", res)
+	}
+
+	var out Sample
+	if err := c.DecodeWith("xml", res["xml"], &out); err != nil || out.Name != "x" {
+		t.Fatalf("DecodeWith(xml) = %+v, %v", out, err)
+	}
+	if err := c.DecodeWith("yaml", res["json"], &out); err == nil {
+		t.Fatal("DecodeWith with unknown encoder must fail")
+	}
+}
+
+func TestCompositeEncoderError(t *testing.T) {
+	c := NewCompositeEncoder()
+	c.AddEncoder("json", &JSONEncoder{})
+	c.AddEncoder("bad", &failingEncoder{})
+
+	if _, err := c.Encode(Sample{}); !errors.Is(err, errEncode) {
+		t.Fatalf("Encode() error = %v, want wrapped %v", err, errEncode)
+	}
+}
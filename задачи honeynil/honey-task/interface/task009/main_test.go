@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errService = errors.New("service error")
+
+func fail() (interface{}, error) { return nil, errService }
+func ok() (interface{}, error)   { return "ok", nil }
+
+func TestCircuitBreakerStates(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls []func() (interface{}, error)
+		sleep time.Duration // пауза после вызовов
+		want  State
+	}{
+		{name: "initially closed", want: StateClosed},
+		{name: "stays closed below threshold", calls: []func() (interface{}, error){fail, fail}, want: StateClosed},
+		{name: "opens at threshold", calls: []func() (interface{}, error){fail, fail, fail}, want: StateOpen},
+		{name: "success does not open", calls: []func() (interface{}, error){ok, ok, ok, ok}, want: StateClosed},
+		{
+			name:  "half-open after timeout",
+			calls: []func() (interface{}, error){fail, fail, fail},
+			sleep: 30 * time.Millisecond,
+			want:  StateHalfOpen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var cb CircuitBreaker = NewCircuitBreaker(3, 20*time.Millisecond)
+			for _, fn := range tc.calls {
+				cb.Call(fn)
+			}
+			time.Sleep(tc.sleep)
+			if got := cb.State(); got != tc.want {
+				t.Fatalf("State() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerOpenRejects(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute)
+	if _, err := cb.Call(fail); !errors.Is(err, errService) {
+		t.Fatalf("Call() error = %v, want %v", err, errService)
+	}
+
+	called := false
+	_, err := cb.Call(func() (interface{}, error) { called = true; return nil, nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Call() on open breaker error = %v, want ErrCircuitOpen", err)
+	}
+	if called {
+		t.Fatal("open breaker must not call fn")
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe func() (interface{}, error)
+		want  State
+	}{
+		{name: "success closes", probe: ok, want: StateClosed},
+		{name: "failure reopens", probe: fail, want: StateOpen},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cb := NewCircuitBreaker(2, 10*time.Millisecond)
+			cb.Call(fail)
+			cb.Call(fail)
+			time.Sleep(20 * time.Millisecond)
+
+			cb.Call(tc.probe)
+			if got := cb.State(); got != tc.want {
+				t.Fatalf("State() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpenSuccessResetsFailures(t *testing.T) {
+	cb := NewCircuitBreaker(2, 10*time.Millisecond)
+	cb.Call(fail)
+	cb.Call(fail)
+	time.Sleep(20 * time.Millisecond)
+	cb.Call(ok)
+
+	// После восстановления одна ошибка не должна снова размыкать цепь.
+	cb.Call(fail)
+	if got := cb.State(); got != StateClosed {
+		t.Fatalf("State() = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerReset(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute)
+	cb.Call(fail)
+	cb.Reset()
+
+	if got := cb.State(); got != StateClosed {
+		t.Fatalf("State() after Reset = %s, want closed", got)
+	}
+	if v, err := cb.Call(ok); err != nil || v != "ok" {
+		t.Fatalf("Call() after Reset = %v, %v", v, err)
+	}
+}
+
+func TestCircuitBreakerConcurrent(t *testing.T) {
+	cb := NewCircuitBreaker(5, time.Millisecond)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if i%3 == 0 {
+					cb.Call(fail)
+				} else {
+					cb.Call(ok)
+				}
+				cb.State()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func messages(errs []ValidationError) []string {
+	out := make([]string, len(errs))
+	for i, e := range errs {
+		out[i] = e.Field + ": " + e.Message
+	}
+	return out
+}
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       Validator
+		data    interface{}
+		wantErr bool
+	}{
+		{name: "required empty", v: &RequiredValidator{Field: "f"}, data: "", wantErr: true},
+		{name: "required nil", v: &RequiredValidator{Field: "f"}, data: nil, wantErr: true},
+		{name: "required ok", v: &RequiredValidator{Field: "f"}, data: "x"},
+		{name: "length short", v: &LengthValidator{Field: "f", Min: 3, Max: 5}, data: "ab", wantErr: true},
+		{name: "length long", v: &LengthValidator{Field: "f", Min: 3, Max: 5}, data: "abcdef", wantErr: true},
+		{name: "length bounds", v: &LengthValidator{Field: "f", Min: 3, Max: 5}, data: "abcde"},
+		{name: "length no max", v: &LengthValidator{Field: "f", Min: 1}, data: "a long string"},
+		{name: "email ok", v: &EmailValidator{Field: "f"}, data: "user.name+tag@example.co"},
+		{name: "email no at", v: &EmailValidator{Field: "f"}, data: "notanemail", wantErr: true},
+		{name: "email no tld", v: &EmailValidator{Field: "f"}, data: "a@b", wantErr: true},
+		{name: "email not string", v: &EmailValidator{Field: "f"}, data: 42, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.v.Validate(tc.data)
+			if (len(errs) > 0) != tc.wantErr {
+				t.Fatalf("Validate(%v) = %v, wantErr %v", tc.data, errs, tc.wantErr)
+			}
+			for _, e := range errs {
+				if e.Field != "f" {
+					t.Fatalf("error field = %q, want %q", e.Field, "f")
+				}
+			}
+		})
+	}
+}
+
+// countingValidator считает вызовы и всегда возвращает ошибку.
+type countingValidator struct {
+	calls atomic.Int32
+	field string
+}
+
+func (v *countingValidator) Validate(interface{}) []ValidationError {
+	v.calls.Add(1)
+	return []ValidationError{{Field: v.field, Message: "bad"}}
+}
+
+func TestChainValidatorStopsAtFirstError(t *testing.T) {
+	first := &countingValidator{field: "first"}
+	second := &countingValidator{field: "second"}
+	var chain CompositeValidator = &ChainValidator{}
+	chain.Add(&RequiredValidator{Field: "ok"})
+	chain.Add(first)
+	chain.Add(second)
+
+	got := messages(chain.Validate("value"))
+	if !slices.Equal(got, []string{"first: bad"}) {
+		t.Fatalf("Validate() = %v, want only the first error", got)
+	}
+	if second.calls.Load() != 0 {
+		t.Fatal("chain must not run validators after the first error")
+	}
+	if errs := chain.ValidateField("email", "value"); len(errs) != 1 {
+		t.Fatalf("ValidateField() = %v, want one error", errs)
+	}
+}
+
+func TestChainValidatorPasses(t *testing.T) {
+	chain := &ChainValidator{}
+	chain.Add(&RequiredValidator{Field: "email"})
+	chain.Add(&LengthValidator{Field: "email", Min: 5, Max: 100})
+	chain.Add(&EmailValidator{Field: "email"})
+
+	if errs := chain.Validate("a@b.com"); len(errs) != 0 {
+		t.Fatalf("Validate(valid email) = %v, want no errors", errs)
+	}
+}
+
+func TestParallelValidatorCollectsAll(t *testing.T) {
+	var parallel CompositeValidator = &ParallelValidator{}
+	validators := make([]*countingValidator, 10)
+	for i := range validators {
+		validators[i] = &countingValidator{field: "f"}
+		parallel.Add(validators[i])
+	}
+	parallel.Add(&RequiredValidator{Field: "name"})
+
+	errs := parallel.Validate("x")
+	if len(errs) != len(validators) {
+		t.Fatalf("Validate() returned %d errors, want %d", len(errs), len(validators))
+	}
+	for i, v := range validators {
+		if v.calls.Load() != 1 {
+			t.Fatalf("validator %d called %d times, want 1", i, v.calls.Load())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy RetryStrategy
+		want     []time.Duration
+	}{
+		{
+			name:     "constant",
+			strategy: &ConstantBackoff{Delay: 5 * time.Millisecond},
+			want:     []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond},
+		},
+		{
+			name:     "exponential",
+			strategy: &ExponentialBackoff{Initial: time.Millisecond},
+			want:     []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond},
+		},
+		{
+			name:     "exponential capped",
+			strategy: &ExponentialBackoff{Initial: 10 * time.Millisecond, Max: 30 * time.Millisecond},
+			want:     []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for attempt, want := range tc.want {
+				if got := tc.strategy.NextDelay(attempt); got != want {
+					t.Fatalf("NextDelay(%d) = %v, want %v", attempt, got, want)
+				}
+			}
+		})
+	}
+}
+
+var errTemporary = errors.New("temporary error")
+
+func TestRetryDo(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		failures     int // сколько первых вызовов вернут ошибку
+		wantErr      error
+		wantAttempts int
+	}{
+		{name: "first try", maxAttempts: 3, failures: 0, wantAttempts: 1},
+		{name: "succeeds on third", maxAttempts: 5, failures: 2, wantAttempts: 3},
+		{name: "succeeds on last", maxAttempts: 3, failures: 2, wantAttempts: 3},
+		{name: "gives up", maxAttempts: 3, failures: 10, wantErr: errTemporary, wantAttempts: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var r Retry = NewRetryExecutor(tc.maxAttempts, &ConstantBackoff{Delay: time.Millisecond})
+			attempts := 0
+			err := r.Do(context.Background(), func() error {
+				attempts++
+				if attempts <= tc.failures {
+					return errTemporary
+				}
+				return nil
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Do() error = %v, want %v", err, tc.wantErr)
+			}
+			if attempts != tc.wantAttempts {
+				t.Fatalf("fn called %d times, want %d", attempts, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryDoWithData(t *testing.T) {
+	r := NewRetryExecutor(3, &ConstantBackoff{Delay: time.Millisecond})
+	attempts := 0
+	v, err := r.DoWithData(context.Background(), func() (interface{}, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, errTemporary
+		}
+		return 42, nil
+	})
+	if err != nil || v != 42 {
+		t.Fatalf("DoWithData() = %v, %v; want 42, nil", v, err)
+	}
+}
+
+func TestRetryContextCancel(t *testing.T) {
+	r := NewRetryExecutor(100, &ConstantBackoff{Delay: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := r.Do(ctx, func() error { return errTemporary })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error = %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Do() returned after %v: must not wait out the backoff", d)
+	}
+}
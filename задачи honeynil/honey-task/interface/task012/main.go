@@ -82,7 +82,7 @@ func (c *ChannelStream) Map(transform func(interface{}) interface{}) Stream { re
 func (c *ChannelStream) Reduce(init interface{}, acc func(interface{}, interface{}) interface{}) interface{} {
 	return NewSliceStream(c.items).Reduce(init, acc)
 }
-func (c *ChannelStream) Collect() []interface{} { return c.items }
+func (c *ChannelStream) Collect() []interface{} { return NewSliceStream(c.items).Collect() }
 func (c *ChannelStream) ForEach(f func(interface{})) { NewSliceStream(c.items).ForEach(f) }
 func (c *ChannelStream) Count() int { return len(c.items) }
 
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func ints(n int) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = i + 1
+	}
+	return items
+}
+
+func channelOf(items []interface{}) *ChannelStream {
+	ch := make(chan interface{}, len(items))
+	for _, v := range items {
+		ch <- v
+	}
+	close(ch)
+	return NewChannelStream(ch)
+}
+
+func isEven(v interface{}) bool          { return v.(int)%2 == 0 }
+func double(v interface{}) interface{}   { return v.(int) * 2 }
+func sum(acc, v interface{}) interface{} { return acc.(int) + v.(int) }
+
+func TestStream(t *testing.T) {
+	constructors := []struct {
+		name string
+		new  func([]interface{}) Stream
+	}{
+		{name: "slice", new: func(items []interface{}) Stream { return NewSliceStream(items) }},
+		{name: "channel", new: func(items []interface{}) Stream { return channelOf(items) }},
+	}
+	tests := []struct {
+		name     string
+		items    []interface{}
+		pipeline func(Stream) Stream
+		want     []interface{}
+		wantSum  int
+	}{
+		{
+			name:     "filter then map",
+			items:    ints(10),
+			pipeline: func(s Stream) Stream { return s.Filter(isEven).Map(double) },
+			want:     []interface{}{4, 8, 12, 16, 20},
+			wantSum:  60,
+		},
+		{
+			name:     "map then filter",
+			items:    ints(5),
+			pipeline: func(s Stream) Stream { return s.Map(double).Filter(isEven) },
+			want:     []interface{}{2, 4, 6, 8, 10},
+			wantSum:  30,
+		},
+		{
+			name:     "filter everything",
+			items:    ints(5),
+			pipeline: func(s Stream) Stream { return s.Filter(func(interface{}) bool { return false }) },
+			want:     []interface{}{},
+		},
+		{
+			name:     "empty source",
+			pipeline: func(s Stream) Stream { return s.Map(double) },
+			want:     []interface{}{},
+		},
+	}
+
+	for _, c := range constructors {
+		for _, tc := range tests {
+			t.Run(c.name+"/"+tc.name, func(t *testing.T) {
+				s := tc.pipeline(c.new(tc.items))
+				got := s.Collect()
+				if !slices.Equal(got, tc.want) {
+					t.Fatalf("Collect() = %v, want %v", got, tc.want)
+				}
+				if n := s.Count(); n != len(tc.want) {
+					t.Fatalf("Count() = %d, want %d", n, len(tc.want))
+				}
+				if total := s.Reduce(0, sum); total != tc.wantSum {
+					t.Fatalf("Reduce() = %v, want %d", total, tc.wantSum)
+				}
+				var seen []interface{}
+				s.ForEach(func(v interface{}) { seen = append(seen, v) })
+				if !slices.Equal(seen, got) {
+					t.Fatalf("ForEach visited %v, want %v", seen, got)
+				}
+			})
+		}
+	}
+}
+
+func TestStreamIsImmutable(t *testing.T) {
+	constructors := map[string]func([]interface{}) Stream{
+		"slice":   func(items []interface{}) Stream { return NewSliceStream(items) },
+		"channel": func(items []interface{}) Stream { return channelOf(items) },
+	}
+	for name, newStream := range constructors {
+		t.Run(name, func(t *testing.T) {
+			items := ints(3)
+			s := newStream(items)
+
+			s.Map(double)
+			s.Filter(isEven)
+			out := s.Collect()
+			out[0] = 100 // изменение результата Collect не должно затрагивать поток
+
+			if got := s.Collect(); !slices.Equal(got, []interface{}{1, 2, 3}) {
+				t.Fatalf("stream changed to %v", got)
+			}
+			if items[0] != 1 {
+				t.Fatalf("source slice changed to %v", items)
+			}
+		})
+	}
+}
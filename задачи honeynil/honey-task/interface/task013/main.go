@@ -1,9 +1,20 @@
 package main
 
-// Задача: Metrics — счётчики, gauge, гистограммы.
+// Задача: Metrics — счётчики, gauge, гистограммы и сводки с отдачей в формате Prometheus.
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"log"
+	"maps"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -14,14 +25,32 @@ const (
 	Counter   MetricType = "counter"
 	Gauge     MetricType = "gauge"
 	Histogram MetricType = "histogram"
+	Summary   MetricType = "summary"
 )
 
+// Metric — снимок одного ряда: метрики с конкретным набором меток. У счётчика
+// Value — сумма, у gauge — последнее значение, у гистограммы и сводки —
+// сумма наблюдений, а их число — в Count.
 type Metric struct {
 	Name      string
 	Type      MetricType
 	Value     float64
 	Labels    map[string]string
-	Timestamp time.Time
+	Timestamp time.Time // время последнего изменения
+
+	Count     uint64
+	Buckets   []Bucket   // гистограмма: накопительные счётчики, без +Inf (это Count)
+	Quantiles []Quantile // сводка
+}
+
+type Bucket struct {
+	UpperBound float64
+	Count      uint64 // наблюдений <= UpperBound
+}
+
+type Quantile struct {
+	Quantile float64
+	Value    float64
 }
 
 type Metrics interface {
@@ -33,36 +62,395 @@ type Metrics interface {
 	Reset()
 }
 
+var (
+	// DefBuckets — границы корзин гистограммы по умолчанию, как в клиенте
+	// Prometheus: под длительности в секундах.
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefObjectives — квантили сводки по умолчанию.
+	DefObjectives = []float64{0.5, 0.9, 0.99}
+)
+
+// SimpleMetrics — реестр метрик: значения агрегируются по имени и набору
+// меток, поэтому память зависит от числа рядов, а не от числа вызовов.
+// Тип метрики закрепляется первым вызовом; вызовы с другим типом для того же
+// имени игнорируются. Observe пишет в гистограмму с DefBuckets, если имя не
+// зарегистрировано через RegisterHistogram или RegisterSummary.
+// Нулевое значение готово к работе.
 type SimpleMetrics struct {
-	mu      sync.Mutex
-	metrics []Metric
+	mu       sync.Mutex
+	families map[string]*family
 }
 
-func (m *SimpleMetrics) record(name string, t MetricType, value float64, labels map[string]string) {
+// family — метрика со всеми её рядами.
+type family struct {
+	typ        MetricType
+	buckets    []float64          // гистограмма
+	objectives []float64          // сводка
+	series     map[string]*series // ключ — метки в формате экспозиции
+}
+
+type series struct {
+	labels  map[string]string
+	updated time.Time
+	value   float64 // счётчик и gauge; у гистограммы и сводки — сумма
+	count   uint64
+	counts  []uint64      // гистограмма: наблюдения по корзинам, не накопительно
+	streams []*quantileP2 // сводка: по оценке на квантиль
+}
+
+// RegisterHistogram делает name гистограммой с границами корзин buckets
+// (по возрастанию; пусто — DefBuckets). Регистрировать нужно до первого Observe.
+func (m *SimpleMetrics) RegisterHistogram(name string, buckets ...float64) error {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	if !slices.IsSorted(buckets) {
+		return fmt.Errorf("histogram %s: buckets must be sorted", name)
+	}
+	// Корзина +Inf есть всегда и выводится отдельно.
+	buckets = slices.DeleteFunc(slices.Compact(slices.Clone(buckets)), func(b float64) bool { return math.IsInf(b, 1) })
+	return m.register(name, &family{typ: Histogram, buckets: buckets})
+}
+
+// RegisterSummary делает name сводкой с квантилями objectives из (0, 1)
+// (пусто — DefObjectives). Квантили считаются потоково по всем наблюдениям.
+func (m *SimpleMetrics) RegisterSummary(name string, objectives ...float64) error {
+	if len(objectives) == 0 {
+		objectives = DefObjectives
+	}
+	for _, q := range objectives {
+		if q <= 0 || q >= 1 {
+			return fmt.Errorf("summary %s: quantile %v is out of (0, 1)", name, q)
+		}
+	}
+	return m.register(name, &family{typ: Summary, objectives: slices.Clone(objectives)})
+}
+
+func (m *SimpleMetrics) register(name string, f *family) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.metrics = append(m.metrics, Metric{
-		Name: name, Type: t, Value: value, Labels: labels, Timestamp: time.Now(),
-	})
+	if old, ok := m.families[name]; ok && len(old.series) > 0 {
+		return fmt.Errorf("metric %s is already in use as %s", name, old.typ)
+	}
+	if m.families == nil {
+		m.families = make(map[string]*family)
+	}
+	f.series = make(map[string]*series)
+	m.families[name] = f
+	return nil
+}
+
+// record находит ряд и обновляет его под блокировкой.
+func (m *SimpleMetrics) record(name string, t MetricType, labels map[string]string, update func(f *family, s *series)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.families == nil {
+		m.families = make(map[string]*family)
+	}
+	f, ok := m.families[name]
+	if !ok {
+		f = &family{typ: t, series: make(map[string]*series)}
+		if t == Histogram {
+			f.buckets = DefBuckets
+		}
+		m.families[name] = f
+	}
+	// Observe годится и для гистограммы, и для сводки.
+	if f.typ != t && !(t == Histogram && f.typ == Summary) {
+		return
+	}
+	key := labelKey(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: maps.Clone(labels)}
+		switch f.typ {
+		case Histogram:
+			s.counts = make([]uint64, len(f.buckets))
+		case Summary:
+			for _, q := range f.objectives {
+				s.streams = append(s.streams, newQuantileP2(q))
+			}
+		}
+		f.series[key] = s
+	}
+	update(f, s)
+	s.updated = time.Now()
 }
 
-func (m *SimpleMetrics) Inc(name string, labels map[string]string)                    { m.Add(name, 1, labels) }
-func (m *SimpleMetrics) Add(name string, value float64, labels map[string]string)     { m.record(name, Counter, value, labels) }
-func (m *SimpleMetrics) Set(name string, value float64, labels map[string]string)     { m.record(name, Gauge, value, labels) }
-func (m *SimpleMetrics) Observe(name string, value float64, labels map[string]string) { m.record(name, Histogram, value, labels) }
+func (m *SimpleMetrics) Inc(name string, labels map[string]string) { m.Add(name, 1, labels) }
 
+// Add увеличивает счётчик; отрицательные значения игнорируются: счётчик не убывает.
+func (m *SimpleMetrics) Add(name string, value float64, labels map[string]string) {
+	if value < 0 {
+		return
+	}
+	m.record(name, Counter, labels, func(_ *family, s *series) { s.value += value })
+}
+
+func (m *SimpleMetrics) Set(name string, value float64, labels map[string]string) {
+	m.record(name, Gauge, labels, func(_ *family, s *series) { s.value = value })
+}
+
+func (m *SimpleMetrics) Observe(name string, value float64, labels map[string]string) {
+	m.record(name, Histogram, labels, func(f *family, s *series) {
+		s.value += value
+		s.count++
+		if i, _ := slices.BinarySearch(f.buckets, value); i < len(s.counts) {
+			s.counts[i]++
+		}
+		for _, q := range s.streams {
+			q.add(value)
+		}
+	})
+}
+
+// GetAll возвращает снимки всех рядов по имени и меткам.
 func (m *SimpleMetrics) GetAll() []Metric {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	out := make([]Metric, len(m.metrics))
-	copy(out, m.metrics)
+	var out []Metric
+	for _, name := range slices.Sorted(maps.Keys(m.families)) {
+		f := m.families[name]
+		for _, key := range slices.Sorted(maps.Keys(f.series)) {
+			s := f.series[key]
+			metric := Metric{
+				Name: name, Type: f.typ, Value: s.value, Labels: maps.Clone(s.labels),
+				Timestamp: s.updated, Count: s.count,
+			}
+			var cum uint64
+			for i, n := range s.counts {
+				cum += n
+				metric.Buckets = append(metric.Buckets, Bucket{UpperBound: f.buckets[i], Count: cum})
+			}
+			for _, q := range s.streams {
+				metric.Quantiles = append(metric.Quantiles, Quantile{Quantile: q.p, Value: q.value()})
+			}
+			out = append(out, metric)
+		}
+	}
 	return out
 }
 
+// Reset удаляет все ряды; зарегистрированные гистограммы и сводки остаются.
 func (m *SimpleMetrics) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.metrics = nil
+	for name, f := range m.families {
+		if f.typ == Histogram || f.typ == Summary {
+			f.series = make(map[string]*series)
+			continue
+		}
+		delete(m.families, name)
+	}
+}
+
+// labelKey — метки в формате экспозиции, отсортированные по имени:
+// method="GET",code="200". Одинаковые наборы меток дают одинаковый ключ.
+func labelKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, k := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sanitizeName(k, false))
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(labels[k]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sanitizeName заменяет недопустимые в имени символы на _: в имени метрики
+// допустимы [a-zA-Z0-9_:], в имени метки — [a-zA-Z0-9_], и оба не
+// начинаются с цифры.
+func sanitizeName(name string, metric bool) string {
+	b := []byte(name)
+	for i, c := range b {
+		ok := c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+			i > 0 && c >= '0' && c <= '9' || metric && c == ':'
+		if !ok {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
+
+// --- Экспозиция Prometheus ---
+
+// Handler отдаёт метрики в текстовом формате Prometheus 0.0.4. Ответ
+// собирается целиком до отправки: при ошибке клиент получает 500, а не
+// обрезанный список метрик с кодом 200.
+func (m *SimpleMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := m.WriteText(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		// По Content-Length клиент заметит, если соединение оборвётся посреди ответа.
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		if _, err := buf.WriteTo(w); err != nil {
+			log.Printf("metrics: write response: %v", err)
+		}
+	})
+}
+
+// WriteText пишет метрики в текстовом формате Prometheus 0.0.4.
+func (m *SimpleMetrics) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var last string
+	for _, metric := range m.GetAll() {
+		name := sanitizeName(metric.Name, true)
+		if name != last {
+			fmt.Fprintf(bw, "# TYPE %s %s\n", name, metric.Type)
+			last = name
+		}
+		labels := labelKey(metric.Labels)
+		switch metric.Type {
+		case Counter, Gauge:
+			writeSample(bw, name, labels, "", metric.Value)
+		case Histogram:
+			for _, b := range metric.Buckets {
+				writeSample(bw, name+"_bucket", labels, `le="`+formatFloat(b.UpperBound)+`"`, float64(b.Count))
+			}
+			writeSample(bw, name+"_bucket", labels, `le="+Inf"`, float64(metric.Count))
+			writeSample(bw, name+"_sum", labels, "", metric.Value)
+			writeSample(bw, name+"_count", labels, "", float64(metric.Count))
+		case Summary:
+			for _, q := range metric.Quantiles {
+				writeSample(bw, name, labels, `quantile="`+formatFloat(q.Quantile)+`"`, q.Value)
+			}
+			writeSample(bw, name+"_sum", labels, "", metric.Value)
+			writeSample(bw, name+"_count", labels, "", float64(metric.Count))
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSample(w io.Writer, name, labels, extra string, value float64) {
+	switch {
+	case labels != "" && extra != "":
+		labels += "," + extra
+	case extra != "":
+		labels = extra
+	}
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// --- Потоковые квантили ---
+
+// quantileP2 оценивает квантиль p алгоритмом P² (Jain, Chlamtac, 1985):
+// пять маркеров вместо всех наблюдений, поэтому память постоянна.
+type quantileP2 struct {
+	p       float64
+	n       int
+	height  [5]float64 // высоты маркеров; до 5 наблюдений — сами наблюдения
+	pos     [5]float64 // позиции маркеров, с 1
+	desired [5]float64 // желаемые позиции
+	step    [5]float64 // прирост желаемых позиций на наблюдение
+}
+
+func newQuantileP2(p float64) *quantileP2 {
+	return &quantileP2{
+		p:       p,
+		pos:     [5]float64{1, 2, 3, 4, 5},
+		desired: [5]float64{1, 1 + 2*p, 1 + 4*p, 3 + 2*p, 5},
+		step:    [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+}
+
+func (q *quantileP2) add(x float64) {
+	if q.n < 5 {
+		q.height[q.n] = x
+		q.n++
+		if q.n == 5 {
+			slices.Sort(q.height[:])
+		}
+		return
+	}
+	q.n++
+
+	// Ячейка k, в которую попало наблюдение: height[k] <= x < height[k+1].
+	var k int
+	switch {
+	case x < q.height[0]:
+		q.height[0] = x
+	case x >= q.height[4]:
+		q.height[4] = x
+		k = 3
+	default:
+		for k = 0; x >= q.height[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		q.pos[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.step[i]
+	}
+
+	// Средние маркеры сдвигаются к желаемым позициям по параболе или, если
+	// она выходит за соседей, линейно.
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.pos[i]
+		if d >= 1 && q.pos[i+1]-q.pos[i] > 1 || d <= -1 && q.pos[i-1]-q.pos[i] < -1 {
+			s := math.Copysign(1, d)
+			h := q.parabolic(i, s)
+			if q.height[i-1] >= h || h >= q.height[i+1] {
+				h = q.linear(i, s)
+			}
+			q.height[i] = h
+			q.pos[i] += s
+		}
+	}
+}
+
+func (q *quantileP2) parabolic(i int, s float64) float64 {
+	return q.height[i] + s/(q.pos[i+1]-q.pos[i-1])*
+		((q.pos[i]-q.pos[i-1]+s)*(q.height[i+1]-q.height[i])/(q.pos[i+1]-q.pos[i])+
+			(q.pos[i+1]-q.pos[i]-s)*(q.height[i]-q.height[i-1])/(q.pos[i]-q.pos[i-1]))
+}
+
+func (q *quantileP2) linear(i int, s float64) float64 {
+	j := i + int(s)
+	return q.height[i] + s*(q.height[j]-q.height[i])/(q.pos[j]-q.pos[i])
+}
+
+// value — текущая оценка; пока наблюдений меньше пяти — точный квантиль.
+func (q *quantileP2) value() float64 {
+	switch {
+	case q.n == 0:
+		return math.NaN()
+	case q.n < 5:
+		sorted := slices.Clone(q.height[:q.n])
+		slices.Sort(sorted)
+		return sorted[int(q.p*float64(q.n-1)+0.5)]
+	}
+	return q.height[2]
 }
 
 // --- BufferedMetrics ---
@@ -75,6 +463,7 @@ type BufferedMetrics struct {
 	mu            sync.Mutex
 	ticker        *time.Ticker
 	done          chan struct{}
+	stopped       chan struct{} // закрывается после финального flush
 }
 
 func NewBufferedMetrics(underlying Metrics, bufferSize int, flushInterval time.Duration) *BufferedMetrics {
@@ -83,6 +472,7 @@ func NewBufferedMetrics(underlying Metrics, bufferSize int, flushInterval time.D
 		bufferSize:    bufferSize,
 		flushInterval: flushInterval,
 		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
 	}
 	bm.ticker = time.NewTicker(flushInterval)
 	go bm.flushLoop()
@@ -123,6 +513,7 @@ func (b *BufferedMetrics) flushLoop() {
 			b.flush()
 		case <-b.done:
 			b.flush()
+			close(b.stopped)
 			return
 		}
 	}
@@ -142,17 +533,25 @@ func (b *BufferedMetrics) Observe(name string, v float64, labels map[string]stri
 }
 func (b *BufferedMetrics) GetAll() []Metric  { return b.underlying.GetAll() }
 func (b *BufferedMetrics) Reset()            { b.underlying.Reset() }
+// Close останавливает фоновый flush и дожидается отправки остатка буфера.
 func (b *BufferedMetrics) Close() {
 	b.ticker.Stop()
 	close(b.done)
+	<-b.stopped
 }
 
 func main() {
 	m := &SimpleMetrics{}
+	m.RegisterHistogram("latency_ms", 10, 50, 100)
+	m.Inc("requests", map[string]string{"method": "GET"})
 	m.Inc("requests", map[string]string{"method": "GET"})
 	m.Set("memory_bytes", 1024*1024, nil)
 	m.Observe("latency_ms", 42.5, map[string]string{"endpoint": "/api"})
 	for _, metric := range m.GetAll() {
 		fmt.Printf("%s(%s)=%.1f\n", metric.Name, metric.Type, metric.Value)
 	}
+
+	// То же, что отдаёт http.Handle("/metrics", m.Handler()).
+	fmt.Println()
+	m.WriteText(os.Stdout)
 }
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSimpleMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		record    func(m Metrics)
+		wantType  MetricType
+		wantValue float64
+	}{
+		{name: "inc", record: func(m Metrics) { m.Inc("x", nil) }, wantType: Counter, wantValue: 1},
+		{name: "add", record: func(m Metrics) { m.Add("x", 2.5, nil) }, wantType: Counter, wantValue: 2.5},
+		{name: "set", record: func(m Metrics) { m.Set("x", 42, nil) }, wantType: Gauge, wantValue: 42},
+		{name: "observe", record: func(m Metrics) { m.Observe("x", 0.3, nil) }, wantType: Histogram, wantValue: 0.3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var m Metrics = &SimpleMetrics{}
+			tc.record(m)
+			all := m.GetAll()
+			if len(all) != 1 {
+				t.Fatalf("GetAll() returned %d metrics, want 1", len(all))
+			}
+			got := all[0]
+			if got.Name != "x" || got.Type != tc.wantType || got.Value != tc.wantValue {
+				t.Fatalf("metric = %+v, want x(%s)=%v", got, tc.wantType, tc.wantValue)
+			}
+			if got.Timestamp.IsZero() {
+				t.Fatal("metric timestamp is not set")
+			}
+		})
+	}
+}
+
+func TestSimpleMetricsLabelsAndReset(t *testing.T) {
+	m := &SimpleMetrics{}
+	m.Inc("requests", map[string]string{"method": "GET"})
+
+	all := m.GetAll()
+	if all[0].Labels["method"] != "GET" {
+		t.Fatalf("labels = %v, want method=GET", all[0].Labels)
+	}
+	all[0].Name = "changed" // GetAll возвращает копию
+	if m.GetAll()[0].Name != "requests" {
+		t.Fatal("modifying GetAll() result must not affect stored metrics")
+	}
+
+	m.Reset()
+	if n := len(m.GetAll()); n != 0 {
+		t.Fatalf("after Reset GetAll() returned %d metrics", n)
+	}
+}
+
+func TestSimpleMetricsConcurrent(t *testing.T) {
+	m := &SimpleMetrics{}
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Inc("x", nil)
+			}
+		}()
+	}
+	wg.Wait()
+	all := m.GetAll()
+	if len(all) != 1 || all[0].Value != 1000 {
+		t.Fatalf("GetAll() = %+v, want one counter x=1000", all)
+	}
+}
+
+func TestBufferedMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		interval   time.Duration
+		records    int
+		wait       time.Duration
+		wantBefore int // сколько вызовов дошло до Close
+	}{
+		{name: "flush by size", bufferSize: 3, interval: time.Hour, records: 7, wantBefore: 6},
+		{name: "flush by interval", bufferSize: 100, interval: 10 * time.Millisecond, records: 5, wait: 50 * time.Millisecond, wantBefore: 5},
+		{name: "buffered until close", bufferSize: 100, interval: time.Hour, records: 5, wantBefore: 0},
+	}
+
+	// count — значение счётчика c или 0, если он ещё не создан.
+	count := func(m Metrics) float64 {
+		for _, metric := range m.GetAll() {
+			if metric.Name == "c" {
+				return metric.Value
+			}
+		}
+		return 0
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &SimpleMetrics{}
+			bm := NewBufferedMetrics(inner, tc.bufferSize, tc.interval)
+			for i := 0; i < tc.records; i++ {
+				bm.Inc("c", nil)
+			}
+			time.Sleep(tc.wait)
+
+			if got := count(bm); got != float64(tc.wantBefore) {
+				t.Fatalf("before Close: %v calls flushed, want %d", got, tc.wantBefore)
+			}
+			bm.Close()
+			if got := count(inner); got != float64(tc.records) {
+				t.Fatalf("after Close: %v calls flushed, want %d", got, tc.records)
+			}
+		})
+	}
+}
+
+func TestBufferedMetricsKeepsOrderAndTypes(t *testing.T) {
+	inner := &SimpleMetrics{}
+	bm := NewBufferedMetrics(inner, 100, time.Hour)
+	for i := 0; i < 10; i++ {
+		bm.Set("g", float64(i), nil)
+		bm.Observe("h", float64(i), nil)
+	}
+	bm.Close()
+
+	all := inner.GetAll()
+	if len(all) != 2 {
+		t.Fatalf("GetAll() = %+v, want gauge g and histogram h", all)
+	}
+	if g := all[0]; g.Type != Gauge || g.Value != 9 {
+		t.Fatalf("gauge = %+v, want the last Set value 9", g)
+	}
+	if h := all[1]; h.Type != Histogram || h.Count != 10 || h.Value != 45 {
+		t.Fatalf("histogram = %+v, want 10 observations with sum 45", h)
+	}
+}
+
+func TestSimpleMetricsAggregation(t *testing.T) {
+	m := &SimpleMetrics{}
+	get := map[string]string{"method": "GET", "code": "200"}
+	m.Inc("requests", get)
+	m.Add("requests", 2, map[string]string{"code": "200", "method": "GET"}) // тот же набор меток
+	m.Inc("requests", map[string]string{"method": "POST", "code": "200"})
+	m.Add("requests", -5, get)  // счётчик не убывает
+	m.Set("requests", 100, get) // тип уже закреплён за счётчиком
+	m.Set("temperature", 20, nil)
+	m.Set("temperature", 18.5, nil)
+
+	get["method"] = "PUT" // реестр хранит свою копию меток
+
+	all := m.GetAll()
+	want := []struct {
+		name   string
+		method string
+		value  float64
+	}{
+		{"requests", "GET", 3},
+		{"requests", "POST", 1},
+		{"temperature", "", 18.5},
+	}
+	if len(all) != len(want) {
+		t.Fatalf("GetAll() returned %d series, want %d: %+v", len(all), len(want), all)
+	}
+	for i, w := range want {
+		got := all[i]
+		if got.Name != w.name || got.Labels["method"] != w.method || got.Value != w.value {
+			t.Fatalf("series %d = %s%v %v, want %s{method=%s} %v", i, got.Name, got.Labels, got.Value, w.name, w.method, w.value)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	m := &SimpleMetrics{}
+	if err := m.RegisterHistogram("latency", 1, 5, 10); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{0.5, 1, 3, 7, 20} {
+		m.Observe("latency", v, nil)
+	}
+	h := m.GetAll()[0]
+	want := []Bucket{{1, 2}, {5, 3}, {10, 4}}
+	if !slices.Equal(h.Buckets, want) || h.Count != 5 || h.Value != 31.5 {
+		t.Fatalf("histogram = buckets %v count %d sum %v, want %v count 5 sum 31.5", h.Buckets, h.Count, h.Value, want)
+	}
+
+	if err := m.RegisterHistogram("latency", 1, 2); err == nil {
+		t.Fatal("RegisterHistogram for a histogram with observations: error = nil")
+	}
+	if err := m.RegisterHistogram("unsorted", 5, 1); err == nil {
+		t.Fatal("RegisterHistogram with unsorted buckets: error = nil")
+	}
+
+	m.Observe("default", 0.2, nil)
+	if h := m.GetAll()[0]; h.Name != "default" || len(h.Buckets) != len(DefBuckets) {
+		t.Fatalf("unregistered histogram = %+v, want DefBuckets", h)
+	}
+}
+
+func TestSummaryQuantiles(t *testing.T) {
+	m := &SimpleMetrics{}
+	if err := m.RegisterSummary("latency", 0.5, 0.9, 0.99); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterSummary("bad", 1); err == nil {
+		t.Fatal("RegisterSummary with quantile 1: error = nil")
+	}
+
+	// 1..100000 в случайном порядке: квантиль q — примерно q*100000.
+	const n = 100000
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, v := range rng.Perm(n) {
+		m.Observe("latency", float64(v+1), map[string]string{"path": "/"})
+	}
+	s := m.GetAll()[0]
+	if s.Type != Summary || s.Count != n || s.Value != n*(n+1)/2 {
+		t.Fatalf("summary = %s count %d sum %v", s.Type, s.Count, s.Value)
+	}
+	for _, q := range s.Quantiles {
+		if want := q.Quantile * n; math.Abs(q.Value-want) > 0.01*n {
+			t.Errorf("quantile %v = %v, want %v ± 1%%", q.Quantile, q.Value, want)
+		}
+	}
+
+	// Меньше пяти наблюдений — точные значения.
+	m.RegisterSummary("small")
+	for _, v := range []float64{3, 1, 2} {
+		m.Observe("small", v, nil)
+	}
+	if q := m.GetAll()[1].Quantiles[0]; q.Value != 2 {
+		t.Fatalf("median of 1, 2, 3 = %v, want 2", q.Value)
+	}
+}
+
+func TestObserveMemoryIsBounded(t *testing.T) {
+	m := &SimpleMetrics{}
+	m.RegisterSummary("s")
+	labels := map[string]string{"path": "/api"}
+	m.Observe("h", 1, labels)
+	m.Observe("s", 1, labels)
+
+	// Наблюдение в существующий ряд не выделяет память: ни гистограмма,
+	// ни сводка не хранят сами наблюдения.
+	allocs := testing.AllocsPerRun(1000, func() {
+		m.Observe("h", 0.3, nil)
+		m.Observe("s", 0.3, nil)
+	})
+	if allocs != 0 {
+		t.Fatalf("Observe allocates %v times per call, want 0", allocs)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := &SimpleMetrics{}
+	m.RegisterHistogram("latency_seconds", 0.1, 1)
+	m.RegisterSummary("size_bytes", 0.5)
+	m.Add("http_requests_total", 3, map[string]string{"method": "GET", "path": `/a"b\c` + "\n"})
+	m.Set("queue-depth", 7, nil)
+	m.Observe("latency_seconds", 0.05, map[string]string{"method": "GET"})
+	m.Observe("latency_seconds", 0.5, map[string]string{"method": "GET"})
+	m.Observe("size_bytes", 512, nil)
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	want := `# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/a\"b\\c\n"} 3
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="GET",le="0.1"} 1
+latency_seconds_bucket{method="GET",le="1"} 2
+latency_seconds_bucket{method="GET",le="+Inf"} 2
+latency_seconds_sum{method="GET"} 0.55
+latency_seconds_count{method="GET"} 2
+# TYPE queue_depth gauge
+queue_depth 7
+# TYPE size_bytes summary
+size_bytes{quantile="0.5"} 512
+size_bytes_sum 512
+size_bytes_count 1
+`
+	if string(body) != want {
+		t.Fatalf("exposition:\n%s\nwant:\n%s", body, want)
+	}
+	if resp.ContentLength != int64(len(want)) {
+		t.Fatalf("Content-Length = %d, want %d", resp.ContentLength, len(want))
+	}
+}
+
+// brokenWriter — ResponseWriter, у которого обрывается соединение.
+type brokenWriter struct{ *httptest.ResponseRecorder }
+
+func (brokenWriter) Write([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestHandlerWriteError(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	m := &SimpleMetrics{}
+	m.Add("requests_total", 1, nil)
+	m.Handler().ServeHTTP(brokenWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(logged.String(), "connection reset") {
+		t.Fatalf("write error is not logged: %q", logged.String())
+	}
+}
@@ -1,12 +1,23 @@
 package main
 
 // Задача: Scheduler — одноразовые и повторяющиеся задачи, cron-like расписание.
+// Задачи с зарегистрированными функциями сохраняются в JobStore и переживают перезапуск.
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,6 +35,11 @@ type Scheduler interface {
 	ScheduleOnce(delay time.Duration, task func() error) (ScheduledTask, error)
 	ScheduleRepeat(interval time.Duration, task func() error) (ScheduledTask, error)
 	ScheduleWithSchedule(schedule Schedule, task func() error) (ScheduledTask, error)
+	// ScheduleJob ставит по расписанию функцию, зарегистрированную под именем
+	// name, и сохраняет задачу в хранилище: она переживёт перезапуск.
+	ScheduleJob(name string, schedule Schedule, opts JobOptions) (ScheduledTask, error)
+	// History — последние запуски задачи, в том числе пропущенные.
+	History(taskID string) ([]RunRecord, error)
 	Cancel(taskID string) error
 	Start() error
 	Stop() error
@@ -34,12 +50,210 @@ type Scheduler interface {
 type scheduledTask struct {
 	id     string
 	fn     func() error
-	cancel context.CancelFunc
+	ctx    context.Context    // контекст цикла задачи
+	cancel context.CancelFunc // останавливает ожидание запусков
+	s      *SimpleScheduler
+	sched  Schedule // nil — одноразовая задача
+	opts   JobOptions
+	name   string // имя функции; "" — задача с замыканием, в хранилище не попадает
+
+	spec, location string // расписание в виде для хранилища
+
+	mu       sync.Mutex
+	next     time.Time
+	history  []RunRecord
+	lastDone chan struct{} // закрывается по окончании последнего запуска
+	version  uint64        // номер последнего снимка задачи
+
+	saveMu  sync.Mutex // упорядочивает записи в хранилище; t.mu при записи не держится
+	saved   uint64     // версия последнего сохранённого снимка
+	removed bool       // задача отменена: больше не сохранять
+}
+
+func (t *scheduledTask) ID() string { return t.id }
+func (t *scheduledTask) Run() error { return t.fn() }
+func (t *scheduledTask) Cancel()    { t.s.Cancel(t.id) }
+
+// loop ждёт запусков задачи, пока не отменён ctx.
+func (t *scheduledTask) loop(ctx context.Context) {
+	t.mu.Lock()
+	next := t.next
+	t.mu.Unlock()
+	if next.IsZero() { // у расписания нет запусков: невозможное cron-выражение или исчерпанная задача
+		return
+	}
+	if now := time.Now(); next.Before(now) {
+		next = t.misfire(ctx, next, now)
+		if ctx.Err() != nil {
+			return
+		}
+		t.setNext(next)
+	}
+	for !next.IsZero() { // нулевое время — у расписания больше нет запусков
+		select {
+		case <-time.After(time.Until(next)):
+			t.trigger(ctx, next)
+			next = t.following(time.Now())
+			t.setNext(next)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// following — следующий запуск после after по расписанию задачи.
+func (t *scheduledTask) following(after time.Time) time.Time {
+	if t.sched == nil {
+		return time.Time{}
+	}
+	return t.sched.Next(after)
+}
+
+// misfire разбирается с запусками, пропущенными, пока планировщик не
+// работал, и возвращает следующий запуск.
+func (t *scheduledTask) misfire(ctx context.Context, missed, now time.Time) time.Time {
+	switch t.opts.Misfire {
+	case MisfireSkip:
+		t.record(RunRecord{Scheduled: missed, Skipped: "misfire"})
+	case MisfireCatchUp:
+		// Пропущенные запуски выполняются по очереди, а не все разом.
+		for at := missed; !at.IsZero() && !at.After(now); at = t.following(at) {
+			select {
+			case <-t.run(at):
+			case <-ctx.Done():
+				return time.Time{}
+			}
+		}
+	default:
+		t.trigger(ctx, missed)
+	}
+	return t.following(now)
+}
+
+// trigger запускает задачу, назначенную на at, с учётом OverlapPolicy.
+func (t *scheduledTask) trigger(ctx context.Context, at time.Time) {
+	busy := t.busy()
+	switch t.opts.Overlap {
+	case OverlapAllow:
+		t.run(at)
+	case OverlapSkip:
+		if busy != nil {
+			t.record(RunRecord{Scheduled: at, Skipped: "overlap"})
+			return
+		}
+		t.run(at)
+	default: // OverlapWait: следующий запуск считается только после окончания этого
+		if busy != nil {
+			select {
+			case <-busy:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-t.run(at):
+		case <-ctx.Done():
+		}
+	}
 }
 
-func (t *scheduledTask) ID() string   { return t.id }
-func (t *scheduledTask) Run() error   { return t.fn() }
-func (t *scheduledTask) Cancel()      { t.cancel() }
+// busy возвращает канал окончания идущего запуска или nil, если задача не выполняется.
+func (t *scheduledTask) busy() <-chan struct{} {
+	t.mu.Lock()
+	done := t.lastDone
+	t.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	default:
+		return done
+	}
+}
+
+// run выполняет задачу в отдельной горутине и записывает запуск в историю.
+func (t *scheduledTask) run(at time.Time) <-chan struct{} {
+	done := make(chan struct{})
+	t.mu.Lock()
+	t.lastDone = done
+	t.mu.Unlock()
+	t.s.wg.Add(1)
+	go func() {
+		defer t.s.wg.Done()
+		defer close(done)
+		rec := RunRecord{Scheduled: at, Started: time.Now()}
+		if err := t.fn(); err != nil {
+			rec.Err = err.Error()
+		}
+		rec.Finished = time.Now()
+		t.record(rec)
+	}()
+	return done
+}
+
+func (t *scheduledTask) record(rec RunRecord) {
+	t.mu.Lock()
+	t.history = append(t.history, rec)
+	t.history = lastRuns(t.history)
+	job, version := t.snapshot()
+	t.mu.Unlock()
+	t.logSave(job, version)
+}
+
+func (t *scheduledTask) setNext(next time.Time) {
+	t.mu.Lock()
+	t.next = next
+	job, version := t.snapshot()
+	t.mu.Unlock()
+	t.logSave(job, version)
+}
+
+// snapshot — задача в виде для хранилища; вызывается под t.mu. Версия
+// растёт с каждым снимком, чтобы save не затёр новый снимок старым.
+func (t *scheduledTask) snapshot() (Job, uint64) {
+	if t.name == "" {
+		return Job{}, 0
+	}
+	t.version++
+	return Job{
+		ID:       t.id,
+		Func:     t.name,
+		Schedule: t.spec,
+		Location: t.location,
+		NextRun:  t.next,
+		Options:  t.opts,
+		History:  slices.Clone(t.history),
+	}, t.version
+}
+
+// save сохраняет снимок задачи. Запись идёт без t.mu: медленное хранилище
+// не задерживает запуски и History. Снимок старше уже сохранённого
+// пропускается, после Cancel задача больше не сохраняется.
+func (t *scheduledTask) save(job Job, version uint64) error {
+	if t.name == "" {
+		return nil
+	}
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+	if t.removed || version <= t.saved {
+		return nil
+	}
+	if err := t.s.store.Save(job); err != nil {
+		return err
+	}
+	t.saved = version
+	return nil
+}
+
+// logSave — save там, где ошибку некому вернуть: задача продолжает работать,
+// а в хранилище останется прошлый снимок.
+func (t *scheduledTask) logSave(job Job, version uint64) {
+	if err := t.save(job, version); err != nil {
+		log.Printf("scheduler: save job %s: %v", t.id, err)
+	}
+}
 
 // --- PeriodicSchedule ---
 
@@ -47,53 +261,662 @@ type PeriodicSchedule struct{ Interval time.Duration }
 
 func (s *PeriodicSchedule) Next(after time.Time) time.Time { return after.Add(s.Interval) }
 
-// --- CronSchedule (упрощённый: просто обёртка над периодом) ---
+// --- CronSchedule ---
+
+// CronSchedule — расписание в формате cron: 5 полей "минута час день месяц
+// день-недели" или 6 полей с секундами впереди. В поле допустимы *, ?,
+// значения, диапазоны a-b, шаги */n и a-b/n, списки через запятую, имена
+// месяцев и дней недели (JAN, MON) и 7 как воскресенье. Вместо полей можно
+// написать @yearly, @monthly, @weekly, @daily или @hourly.
+//
+// Как в классическом cron, если заданы и день месяца, и день недели,
+// подходит любой из них; если одно из полей — *, учитывается только другое.
+//
+// Время считается по часам loc. При переводе часов задачи на конкретный час
+// не теряются и не дублируются: время, которого нет, срабатывает в момент
+// перевода, а повторившееся — один раз, в первый проход. Расписания на
+// каждый час идут по реальному времени: пропущенный час пропускается,
+// повторившийся выполняется дважды.
+type CronSchedule struct {
+	expr             string
+	loc              *time.Location
+	sec, min, hour   cronBits
+	dom, month, dow  cronBits
+	domStar, dowStar bool // поле начинается с * или ?: день выбирает другое поле
+}
+
+type cronBits uint64
 
-type CronSchedule struct{ interval time.Duration }
+func (b cronBits) has(n int) bool { return b&(1<<n) != 0 }
 
-func NewCronSchedule(interval time.Duration) *CronSchedule { return &CronSchedule{interval: interval} }
-func (c *CronSchedule) Next(after time.Time) time.Time     { return after.Add(c.interval) }
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // имена значений начиная с min
+}
+
+var (
+	secField   = cronField{name: "second", min: 0, max: 59}
+	minField   = cronField{name: "minute", min: 0, max: 59}
+	hourField  = cronField{name: "hour", min: 0, max: 23}
+	domField   = cronField{name: "day of month", min: 1, max: 31}
+	monthField = cronField{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// NewCronSchedule разбирает выражение cron; время считается по loc, nil — time.Local.
+func NewCronSchedule(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("cron %q: unknown macro", expr)
+		}
+		spec = macro
+	}
+	parts := strings.Fields(spec)
+	switch len(parts) {
+	case 5:
+		parts = append([]string{"0"}, parts...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron %q: want 5 or 6 fields, got %d", expr, len(parts))
+	}
+
+	c := &CronSchedule{expr: expr, loc: loc}
+	fields := []struct {
+		f    cronField
+		bits *cronBits
+	}{
+		{secField, &c.sec}, {minField, &c.min}, {hourField, &c.hour},
+		{domField, &c.dom}, {monthField, &c.month}, {dowField, &c.dow},
+	}
+	for i, fd := range fields {
+		bits, err := parseCronField(parts[i], fd.f)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		*fd.bits = bits
+	}
+	if c.dow.has(7) {
+		c.dow = c.dow&^(1<<7) | 1 // 7 — тоже воскресенье
+	}
+	c.domStar = strings.HasPrefix(parts[3], "*") || strings.HasPrefix(parts[3], "?")
+	c.dowStar = strings.HasPrefix(parts[5], "*") || strings.HasPrefix(parts[5], "?")
+	return c, nil
+}
+
+// parseCronField разбирает одно поле: список элементов через запятую.
+func parseCronField(s string, f cronField) (cronBits, error) {
+	var bits cronBits
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: bad step %q", f.name, stepStr)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" && rng != "?" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				hi = lo // "5/15" — от 5 до конца поля
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: bad range %q", f.name, rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value разбирает число или имя значения поля.
+func (f cronField) value(s string) (int, error) {
+	if i := slices.Index(f.names, strings.ToUpper(s)); i >= 0 {
+		return f.min + i, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: bad value %q", f.name, s)
+	}
+	return n, nil
+}
+
+// cronMaxDays — сколько дней искать подходящий: 29 февраля в нужный день
+// недели может не случиться и 8 лет (между 2096 и 2104 годами нет
+// високосного).
+const cronMaxDays = 8*366 + 1
+
+// Next возвращает первый подходящий момент строго после after в часовом
+// поясе расписания или нулевое время, если такого нет (например, "0 0 30 2 *").
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	local := after.In(c.loc)
+	y, m, d := local.Date()
+	for i := 0; i < cronMaxDays; i++ {
+		date := time.Date(y, m, d+i, 0, 0, 0, 0, time.UTC) // календарная дата без часового пояса
+		if !c.matchDay(date) {
+			continue
+		}
+		if t, ok := c.nextInDay(date, after); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *CronSchedule) matchDay(date time.Time) bool {
+	if !c.month.has(int(date.Month())) {
+		return false
+	}
+	dom, dow := c.dom.has(date.Day()), c.dow.has(int(date.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// nextInDay ищет первый момент после after в календарный день date.
+func (c *CronSchedule) nextInDay(date, after time.Time) (time.Time, bool) {
+	y, m, d := date.Date()
+	_, startOff := time.Date(y, m, d, 0, 0, 0, 0, c.loc).Zone()
+	_, endOff := time.Date(y, m, d, 23, 59, 59, 0, c.loc).Zone()
+	shift := startOff != endOff // в этот день переводят часы
+
+	// Без перевода часов настенное время идёт по порядку моментов: можно
+	// начать с часов after и вернуть первый подходящий момент.
+	h0, m0, s0 := 0, 0, 0
+	if ay, am, ad := after.In(c.loc).Date(); !shift && ay == y && am == m && ad == d {
+		h0, m0, s0 = after.In(c.loc).Clock()
+	}
+
+	var best time.Time
+	for h := h0; h < 24; h++ {
+		if !c.hour.has(h) {
+			continue
+		}
+		for mi := 0; mi < 60; mi++ {
+			if !c.min.has(mi) || h == h0 && mi < m0 {
+				continue
+			}
+			for s := 0; s < 60; s++ {
+				if !c.sec.has(s) || h == h0 && mi == m0 && s < s0 {
+					continue
+				}
+				for _, t := range c.at(y, m, d, h, mi, s) {
+					if t.After(after) && (best.IsZero() || t.Before(best)) {
+						best = t
+					}
+				}
+				if !shift && !best.IsZero() {
+					return best, true
+				}
+			}
+		}
+	}
+	return best, !best.IsZero()
+}
+
+// at переводит настенное время в моменты по правилам перевода часов из
+// описания CronSchedule.
+func (c *CronSchedule) at(y int, m time.Month, d, h, mi, s int) []time.Time {
+	wall := time.Date(y, m, d, h, mi, s, 0, time.UTC)
+	guess := time.Date(y, m, d, h, mi, s, 0, c.loc)
+	// Смещения до и после возможного перевода; настенное время существует
+	// при смещении, с которым оно переводится обратно в себя.
+	var found []time.Time
+	var gap time.Time
+	for _, probe := range []time.Time{guess.Add(-24 * time.Hour), guess.Add(24 * time.Hour)} {
+		_, off := probe.Zone()
+		t := wall.Add(-time.Duration(off) * time.Second).In(c.loc)
+		if _, o := t.Zone(); o != off {
+			if gap.IsZero() {
+				gap = t // по смещению до перевода — уже после пропущенного часа
+			}
+			continue
+		}
+		if !slices.ContainsFunc(found, t.Equal) {
+			found = append(found, t)
+		}
+	}
+	everyHour := c.hour == 1<<24-1
+	switch {
+	case len(found) == 0 && everyHour:
+		return nil
+	case len(found) == 0:
+		start, _ := gap.ZoneBounds() // первый момент после пропущенного часа
+		return []time.Time{start}
+	case len(found) == 2 && !everyHour:
+		return []time.Time{minTime(found[0], found[1])}
+	}
+	return found
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// --- Хранилище задач ---
+
+// MisfirePolicy — что делать с запусками, пропущенными, пока планировщик не работал.
+type MisfirePolicy string
+
+const (
+	MisfireFireOnce MisfirePolicy = "fire-once" // один запуск сразу вместо всех пропущенных (по умолчанию)
+	MisfireSkip     MisfirePolicy = "skip"      // пропущенные не выполняются
+	MisfireCatchUp  MisfirePolicy = "catch-up"  // выполнить каждый пропущенный запуск по очереди
+)
+
+// OverlapPolicy — что делать, если пора запускать задачу, а предыдущий запуск ещё идёт.
+type OverlapPolicy string
+
+const (
+	OverlapWait  OverlapPolicy = "wait"  // запуски идут по очереди: следующий назначается после окончания предыдущего (по умолчанию)
+	OverlapSkip  OverlapPolicy = "skip"  // пропустить запуск
+	OverlapAllow OverlapPolicy = "allow" // запускать параллельно
+)
+
+// JobOptions — политики задачи; пустые поля — политики по умолчанию.
+type JobOptions struct {
+	Misfire MisfirePolicy `json:"misfire,omitempty"`
+	Overlap OverlapPolicy `json:"overlap,omitempty"`
+}
+
+func (o JobOptions) validate() error {
+	switch o.Misfire {
+	case "", MisfireFireOnce, MisfireSkip, MisfireCatchUp:
+	default:
+		return fmt.Errorf("unknown misfire policy %q", o.Misfire)
+	}
+	switch o.Overlap {
+	case "", OverlapWait, OverlapSkip, OverlapAllow:
+	default:
+		return fmt.Errorf("unknown overlap policy %q", o.Overlap)
+	}
+	return nil
+}
+
+// MaxHistory — сколько последних запусков хранится у задачи.
+const MaxHistory = 100
+
+// lastRuns оставляет от истории последние MaxHistory запусков.
+func lastRuns(history []RunRecord) []RunRecord {
+	if len(history) > MaxHistory {
+		return history[len(history)-MaxHistory:]
+	}
+	return history
+}
+
+// RunRecord — запуск задачи: выполненный или пропущенный.
+type RunRecord struct {
+	Scheduled time.Time `json:"scheduled"` // на когда запуск был назначен
+	Started   time.Time `json:"started,omitzero"`
+	Finished  time.Time `json:"finished,omitzero"`
+	Err       string    `json:"error,omitempty"`
+	Skipped   string    `json:"skipped,omitempty"` // почему не выполнялся: "misfire" или "overlap"
+}
+
+// Job — задача в хранилище. Функция хранится по имени и при Start ищется
+// среди зарегистрированных через Register.
+type Job struct {
+	ID       string      `json:"id"`
+	Func     string      `json:"func"`
+	Schedule string      `json:"schedule,omitempty"` // "@every 1m" или выражение cron; пусто — одноразовая задача
+	Location string      `json:"location,omitempty"` // часовой пояс cron-расписания
+	NextRun  time.Time   `json:"next_run,omitzero"`  // нулевое — запусков больше нет
+	Options  JobOptions  `json:"options"`
+	History  []RunRecord `json:"history,omitempty"`
+}
+
+// JobStore хранит задачи между перезапусками планировщика.
+type JobStore interface {
+	Load() ([]Job, error)
+	Save(job Job) error
+	Delete(id string) error
+}
+
+// MemoryJobStore — хранилище в памяти: задачи живут, пока жив процесс.
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func NewMemoryJobStore() *MemoryJobStore { return &MemoryJobStore{jobs: make(map[string]Job)} }
+
+func (m *MemoryJobStore) Load() ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedJobs(m.jobs), nil
+}
+
+func (m *MemoryJobStore) Save(job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *MemoryJobStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+// FileJobStore — хранилище в JSON-файле. Файл переписывается целиком через
+// временный файл и rename, чтобы падение посреди записи его не испортило.
+type FileJobStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileJobStore(path string) *FileJobStore { return &FileJobStore{path: path} }
+
+func (f *FileJobStore) Load() ([]Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	jobs, err := f.read()
+	return sortedJobs(jobs), err
+}
+
+func (f *FileJobStore) Save(job Job) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	jobs, err := f.read()
+	if err != nil {
+		return err
+	}
+	jobs[job.ID] = job
+	return f.write(jobs)
+}
+
+func (f *FileJobStore) Delete(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	jobs, err := f.read()
+	if err != nil {
+		return err
+	}
+	delete(jobs, id)
+	return f.write(jobs)
+}
+
+func (f *FileJobStore) read() (map[string]Job, error) {
+	jobs := make(map[string]Job)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return jobs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.path, err)
+	}
+	return jobs, nil
+}
+
+func (f *FileJobStore) write(jobs map[string]Job) error {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Имя временного файла уникально: FileJobStore на тот же путь в другом
+	// планировщике или процессе не перепишет его посреди записи.
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // после rename файла с этим именем уже нет
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func sortedJobs(jobs map[string]Job) []Job {
+	out := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, job)
+	}
+	slices.SortFunc(out, func(a, b Job) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// scheduleSpec — расписание в виде для хранилища.
+func scheduleSpec(sched Schedule) (spec, location string, err error) {
+	switch s := sched.(type) {
+	case *PeriodicSchedule:
+		return "@every " + s.Interval.String(), "", nil
+	case *CronSchedule:
+		return s.expr, s.loc.String(), nil
+	}
+	return "", "", fmt.Errorf("schedule %T cannot be stored: use PeriodicSchedule or CronSchedule", sched)
+}
+
+// parseSchedule восстанавливает расписание из хранилища; пустое — одноразовая задача.
+func parseSchedule(spec, location string) (Schedule, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(every)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("bad schedule %q", spec)
+		}
+		return &PeriodicSchedule{Interval: d}, nil
+	}
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return NewCronSchedule(spec, loc)
+}
 
 // --- SimpleScheduler ---
 
+// SimpleScheduler запускает каждую задачу в своей горутине. Задачи
+// ScheduleJob сохраняются в JobStore и при Start поднимаются из него;
+// задачи с замыканиями живут только в памяти. Задачи, поставленные до
+// Start, начинают ждать запусков в Start.
 type SimpleScheduler struct {
-	mu    sync.Mutex
-	tasks map[string]*scheduledTask
-	ctx   context.Context
-	cancel context.CancelFunc
+	mu      sync.Mutex
+	tasks   map[string]*scheduledTask
+	funcs   map[string]func() error
+	store   JobStore
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool           // Start отработал
+	stopped bool           // Stop вызван: новые циклы не запускаются
+	wg      sync.WaitGroup // циклы задач и идущие запуски: Stop их дожидается
 }
 
+// ErrStarted — Start вызван повторно или после Stop. Для перезапуска нужен
+// новый планировщик: он поднимет задачи из того же хранилища.
+var ErrStarted = errors.New("scheduler can only be started once")
+
 func newID() string { return fmt.Sprintf("%016x", rand.Int63()) }
 
-func NewSimpleScheduler() *SimpleScheduler { return &SimpleScheduler{tasks: make(map[string]*scheduledTask)} }
+func NewSimpleScheduler() *SimpleScheduler { return NewPersistentScheduler(NewMemoryJobStore()) }
 
+// NewPersistentScheduler — планировщик, который хранит задачи ScheduleJob в store.
+func NewPersistentScheduler(store JobStore) *SimpleScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SimpleScheduler{
+		tasks:  make(map[string]*scheduledTask),
+		funcs:  make(map[string]func() error),
+		store:  store,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register связывает имя с функцией для ScheduleJob и задач из хранилища.
+// Функции задач из хранилища должны быть зарегистрированы до Start.
+func (s *SimpleScheduler) Register(name string, fn func() error) {
+	s.mu.Lock()
+	s.funcs[name] = fn
+	s.mu.Unlock()
+}
+
+// Start поднимает задачи из хранилища; запуски, пропущенные, пока
+// планировщик не работал, обрабатываются по MisfirePolicy задачи.
+// Неудачный Start можно повторить, например зарегистрировав недостающую функцию.
 func (s *SimpleScheduler) Start() error {
-	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.mu.Lock()
+	done := s.started || s.stopped
+	s.mu.Unlock()
+	if done {
+		return ErrStarted
+	}
+	jobs, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+	var restored []*scheduledTask
+	for _, job := range jobs {
+		s.mu.Lock()
+		_, ok := s.tasks[job.ID]
+		s.mu.Unlock()
+		if ok { // поставлена через ScheduleJob до Start
+			continue
+		}
+		t, err := s.restore(job)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		restored = append(restored, t)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return ErrStarted
+	}
+	s.started = true
+	for _, t := range s.tasks {
+		s.launch(t)
+	}
+	for _, t := range restored {
+		s.add(t)
+	}
 	return nil
 }
 
+// Stop останавливает все задачи и дожидается идущих запусков: после Stop
+// планировщик больше не пишет в хранилище. Задачи в хранилище остаются до
+// следующего Start.
 func (s *SimpleScheduler) Stop() error {
+	if s.cancel == nil { // нулевой SimpleScheduler, не из конструктора
+		return nil
+	}
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
 	s.cancel()
+	s.wg.Wait()
 	return nil
 }
 
-func (s *SimpleScheduler) register(t *scheduledTask) {
+func (s *SimpleScheduler) restore(job Job) (*scheduledTask, error) {
+	fn, err := s.lookup(job.Func)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := parseSchedule(job.Schedule, job.Location)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledTask{
+		id: job.ID, fn: fn, s: s, sched: sched, opts: job.Options, name: job.Func,
+		spec: job.Schedule, location: job.Location,
+		next: job.NextRun, history: lastRuns(job.History),
+	}, nil
+}
+
+func (s *SimpleScheduler) lookup(name string) (func() error, error) {
 	s.mu.Lock()
-	s.tasks[t.id] = t
+	defer s.mu.Unlock()
+	fn, ok := s.funcs[name]
+	if !ok {
+		return nil, fmt.Errorf("function %q is not registered", name)
+	}
+	return fn, nil
+}
+
+// start регистрирует задачу и, если планировщик работает, запускает
+// ожидание её запусков.
+func (s *SimpleScheduler) start(t *scheduledTask) {
+	s.mu.Lock()
+	s.add(t)
 	s.mu.Unlock()
 }
 
-func (s *SimpleScheduler) ScheduleOnce(delay time.Duration, fn func() error) (ScheduledTask, error) {
-	ctx, cancel := context.WithCancel(s.ctx)
-	t := &scheduledTask{id: newID(), fn: fn, cancel: cancel}
-	s.register(t)
+// add — start под s.mu.
+func (s *SimpleScheduler) add(t *scheduledTask) {
+	t.ctx, t.cancel = context.WithCancel(s.ctx)
+	s.tasks[t.id] = t
+	if s.started {
+		s.launch(t)
+	}
+}
+
+// launch запускает цикл задачи; вызывается под s.mu. После Stop циклы не
+// запускаются: Stop уже ждёт на wg.
+func (s *SimpleScheduler) launch(t *scheduledTask) {
+	if s.stopped {
+		return
+	}
+	s.wg.Add(1)
 	go func() {
-		select {
-		case <-time.After(delay):
-			t.fn()
-		case <-ctx.Done():
-		}
+		defer s.wg.Done()
+		t.loop(t.ctx)
 	}()
+}
+
+func (s *SimpleScheduler) ScheduleOnce(delay time.Duration, fn func() error) (ScheduledTask, error) {
+	t := &scheduledTask{id: newID(), fn: fn, s: s, next: time.Now().Add(delay)}
+	s.start(t)
 	return t, nil
 }
 
@@ -102,47 +925,102 @@ func (s *SimpleScheduler) ScheduleRepeat(interval time.Duration, fn func() error
 }
 
 func (s *SimpleScheduler) ScheduleWithSchedule(sched Schedule, fn func() error) (ScheduledTask, error) {
-	ctx, cancel := context.WithCancel(s.ctx)
-	t := &scheduledTask{id: newID(), fn: fn, cancel: cancel}
-	s.register(t)
-	go func() {
-		next := sched.Next(time.Now())
-		for {
-			select {
-			case <-time.After(time.Until(next)):
-				fn()
-				next = sched.Next(time.Now())
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
+	t := &scheduledTask{id: newID(), fn: fn, s: s, sched: sched, next: sched.Next(time.Now())}
+	s.start(t)
+	return t, nil
+}
+
+func (s *SimpleScheduler) ScheduleJob(name string, sched Schedule, opts JobOptions) (ScheduledTask, error) {
+	fn, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	spec, location, err := scheduleSpec(sched)
+	if err != nil {
+		return nil, err
+	}
+	t := &scheduledTask{
+		id: newID(), fn: fn, s: s, sched: sched, opts: opts, name: name,
+		spec: spec, location: location, next: sched.Next(time.Now()),
+	}
+	if err := t.save(t.snapshot()); err != nil { // задача ещё не запущена, t.mu не нужен
+		return nil, err
+	}
+	s.start(t)
 	return t, nil
 }
 
+func (s *SimpleScheduler) History(taskID string) ([]RunRecord, error) {
+	s.mu.Lock()
+	t, ok := s.tasks[taskID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.history), nil
+}
+
+// Cancel останавливает задачу и удаляет её из хранилища.
 func (s *SimpleScheduler) Cancel(taskID string) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	if t, ok := s.tasks[taskID]; ok {
-		t.cancel()
-		delete(s.tasks, taskID)
+	t, ok := s.tasks[taskID]
+	delete(s.tasks, taskID)
+	s.mu.Unlock()
+	if !ok {
+		return nil
 	}
-	return nil
+	t.cancel()
+	t.saveMu.Lock() // дожидается идущей записи, чтобы она не вернула задачу в хранилище
+	defer t.saveMu.Unlock()
+	t.removed = true
+	if t.name == "" {
+		return nil
+	}
+	return s.store.Delete(taskID)
 }
 
 func main() {
 	scheduler := NewSimpleScheduler()
 	scheduler.Start()
 
-	count := 0
+	var count atomic.Int32 // fn выполняется в горутине планировщика
 	t, _ := scheduler.ScheduleRepeat(100*time.Millisecond, func() error {
-		count++
-		fmt.Println("tick", count)
+		fmt.Println("tick", count.Add(1))
 		return nil
 	})
 
 	time.Sleep(350 * time.Millisecond)
 	t.Cancel()
-	fmt.Println("total ticks:", count)
+	fmt.Println("total ticks:", count.Load())
 	scheduler.Stop()
+
+	// Задача по имени функции: с файловым хранилищем (NewFileJobStore) она
+	// переживёт перезапуск, а история запусков доступна через History.
+	durable := NewSimpleScheduler()
+	durable.Register("report", func() error {
+		fmt.Println("report")
+		return nil
+	})
+	durable.Start()
+	job, _ := durable.ScheduleJob("report", &PeriodicSchedule{Interval: 100 * time.Millisecond}, JobOptions{Misfire: MisfireCatchUp})
+	time.Sleep(250 * time.Millisecond)
+	durable.Stop()
+	history, _ := durable.History(job.ID())
+	fmt.Println("report runs:", len(history))
+
+	cron, err := NewCronSchedule("30 9 * * MON-FRI", time.UTC)
+	if err != nil {
+		fmt.Println("cron:", err)
+		return
+	}
+	next := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC) // пятница, 09:30 уже прошло
+	for i := 0; i < 3; i++ {
+		next = cron.Next(next)
+		fmt.Println("weekday 09:30:", next.Format("Mon 2006-01-02 15:04"))
+	}
 }
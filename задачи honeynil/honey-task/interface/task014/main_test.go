@@ -0,0 +1,718 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "time/tzdata" // часовые пояса для тестов cron без системной базы
+)
+
+func newStartedScheduler(t *testing.T) Scheduler {
+	t.Helper()
+	var s Scheduler = NewSimpleScheduler()
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Stop() })
+	return s
+}
+
+func counter(n *atomic.Int32) func() error {
+	return func() error { n.Add(1); return nil }
+}
+
+func TestScheduleOnce(t *testing.T) {
+	s := newStartedScheduler(t)
+	var runs atomic.Int32
+	task, err := s.ScheduleOnce(10*time.Millisecond, counter(&runs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID() == "" {
+		t.Fatal("task ID is empty")
+	}
+	if runs.Load() != 0 {
+		t.Fatal("task ran before its delay")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("task ran %d times, want 1", n)
+	}
+}
+
+func TestScheduleRepeat(t *testing.T) {
+	s := newStartedScheduler(t)
+	var runs atomic.Int32
+	task, _ := s.ScheduleRepeat(20*time.Millisecond, counter(&runs))
+
+	time.Sleep(110 * time.Millisecond)
+	task.Cancel()
+	n := runs.Load()
+	if n < 3 || n > 6 {
+		t.Fatalf("task ran %d times in 110ms with 20ms interval", n)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != n {
+		t.Fatal("task kept running after Cancel")
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(s Scheduler, task ScheduledTask)
+	}{
+		{name: "task cancel", cancel: func(_ Scheduler, task ScheduledTask) { task.Cancel() }},
+		{name: "scheduler cancel by id", cancel: func(s Scheduler, task ScheduledTask) { s.Cancel(task.ID()) }},
+		{name: "scheduler stop", cancel: func(s Scheduler, _ ScheduledTask) { s.Stop() }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var s Scheduler = NewSimpleScheduler()
+			s.Start()
+			defer s.Stop()
+
+			var once, repeat atomic.Int32
+			onceTask, _ := s.ScheduleOnce(30*time.Millisecond, counter(&once))
+			repeatTask, _ := s.ScheduleRepeat(30*time.Millisecond, counter(&repeat))
+			tc.cancel(s, onceTask)
+			tc.cancel(s, repeatTask)
+
+			time.Sleep(80 * time.Millisecond)
+			if once.Load() != 0 || repeat.Load() != 0 {
+				t.Fatalf("cancelled tasks ran: once=%d repeat=%d", once.Load(), repeat.Load())
+			}
+		})
+	}
+}
+
+// fixedSchedule возвращает заранее заданные задержки по очереди.
+type fixedSchedule struct {
+	delays []time.Duration
+	calls  atomic.Int32
+}
+
+func (f *fixedSchedule) Next(after time.Time) time.Time {
+	i := int(f.calls.Add(1)) - 1
+	if i >= len(f.delays) {
+		return after.Add(time.Hour)
+	}
+	return after.Add(f.delays[i])
+}
+
+func TestScheduleWithSchedule(t *testing.T) {
+	s := newStartedScheduler(t)
+	var runs atomic.Int32
+	sched := &fixedSchedule{delays: []time.Duration{5 * time.Millisecond, 5 * time.Millisecond}}
+	if _, err := s.ScheduleWithSchedule(sched, counter(&runs)); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if n := runs.Load(); n != 2 {
+		t.Fatalf("task ran %d times, want 2 (as many as schedule delays)", n)
+	}
+}
+
+func TestRunCallsTask(t *testing.T) {
+	s := newStartedScheduler(t)
+	var runs atomic.Int32
+	task, _ := s.ScheduleOnce(time.Hour, counter(&runs))
+	if err := task.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if runs.Load() != 1 {
+		t.Fatal("Run must execute the task immediately")
+	}
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	utc := time.UTC
+	ny := mustLoad(t, "America/New_York")
+	msk := mustLoad(t, "Europe/Moscow")
+	date := func(loc *time.Location, y int, m time.Month, d, h, mi, s int) time.Time {
+		return time.Date(y, m, d, h, mi, s, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		loc   *time.Location
+		after time.Time
+		want  time.Time // нулевое — запусков больше нет
+	}{
+		{"weekday skips weekend", "30 9 * * MON-FRI", utc, date(utc, 2024, 3, 1, 10, 0, 0), date(utc, 2024, 3, 4, 9, 30, 0)},
+		{"weekday same day", "30 9 * * MON-FRI", utc, date(utc, 2024, 3, 1, 9, 29, 59), date(utc, 2024, 3, 1, 9, 30, 0)},
+		{"strictly after", "30 9 * * *", utc, date(utc, 2024, 3, 1, 9, 30, 0), date(utc, 2024, 3, 2, 9, 30, 0)},
+		{"seconds field", "*/15 * * * * *", utc, date(utc, 2024, 3, 1, 10, 0, 7), date(utc, 2024, 3, 1, 10, 0, 15)},
+		{"seconds roll over hour", "*/15 * * * * *", utc, date(utc, 2024, 3, 1, 10, 59, 45), date(utc, 2024, 3, 1, 11, 0, 0)},
+		{"step from value", "5/20 * * * *", utc, date(utc, 2024, 3, 1, 10, 6, 0), date(utc, 2024, 3, 1, 10, 25, 0)},
+		{"range with step", "0 9-17/4 * * *", utc, date(utc, 2024, 3, 1, 10, 0, 0), date(utc, 2024, 3, 1, 13, 0, 0)},
+		{"month names list", "0 8 1 JAN,jul *", utc, date(utc, 2024, 2, 1, 0, 0, 0), date(utc, 2024, 7, 1, 8, 0, 0)},
+		{"7 is sunday", "0 0 * * 7", utc, date(utc, 2024, 3, 2, 12, 0, 0), date(utc, 2024, 3, 3, 0, 0, 0)},
+		{"day of month or weekday", "0 0 13 * FRI", utc, date(utc, 2024, 3, 2, 0, 0, 0), date(utc, 2024, 3, 8, 0, 0, 0)},
+
+		{"31st skips short months", "0 0 31 * *", utc, date(utc, 2024, 3, 31, 0, 0, 0), date(utc, 2024, 5, 31, 0, 0, 0)},
+		{"30th skips february", "0 0 30 * *", utc, date(utc, 2024, 1, 30, 12, 0, 0), date(utc, 2024, 3, 30, 0, 0, 0)},
+		{"end of year", "59 23 31 12 *", utc, date(utc, 2024, 12, 31, 23, 59, 0), date(utc, 2025, 12, 31, 23, 59, 0)},
+		{"leap day", "0 0 29 2 *", utc, date(utc, 2023, 3, 1, 0, 0, 0), date(utc, 2024, 2, 29, 0, 0, 0)},
+		{"next leap day", "0 0 29 2 *", utc, date(utc, 2024, 2, 29, 0, 0, 0), date(utc, 2028, 2, 29, 0, 0, 0)},
+		{"2100 is not leap", "0 0 29 2 *", utc, date(utc, 2096, 3, 1, 0, 0, 0), date(utc, 2104, 2, 29, 0, 0, 0)},
+		{"leap day or monday", "0 0 29 2 MON", utc, date(utc, 2024, 3, 1, 0, 0, 0), date(utc, 2025, 2, 3, 0, 0, 0)},
+		{"february 30 never", "0 0 30 2 *", utc, date(utc, 2024, 1, 1, 0, 0, 0), time.Time{}},
+
+		{"@hourly", "@hourly", utc, date(utc, 2024, 3, 1, 10, 15, 0), date(utc, 2024, 3, 1, 11, 0, 0)},
+		{"@daily", "@daily", utc, date(utc, 2024, 2, 28, 10, 0, 0), date(utc, 2024, 2, 29, 0, 0, 0)},
+		{"@weekly", "@weekly", utc, date(utc, 2024, 3, 6, 0, 0, 0), date(utc, 2024, 3, 10, 0, 0, 0)},
+		{"@monthly over year end", "@monthly", utc, date(utc, 2024, 12, 15, 0, 0, 0), date(utc, 2025, 1, 1, 0, 0, 0)},
+		{"@yearly", "@YEARLY", utc, date(utc, 2024, 1, 1, 0, 0, 0), date(utc, 2025, 1, 1, 0, 0, 0)},
+
+		{"time zone", "0 9 * * *", msk, date(utc, 2024, 6, 1, 7, 0, 0), date(utc, 2024, 6, 2, 6, 0, 0)},
+		// 10 марта 2024 в Нью-Йорке часы переводят с 02:00 EST на 03:00 EDT.
+		{"missing time runs at transition", "30 2 * * *", ny, date(ny, 2024, 3, 10, 0, 0, 0), date(utc, 2024, 3, 10, 7, 0, 0)},
+		{"after missing time", "30 2 * * *", ny, date(utc, 2024, 3, 10, 7, 0, 0), date(ny, 2024, 3, 11, 2, 30, 0)},
+		{"hourly skips missing hour", "15 * * * *", ny, date(ny, 2024, 3, 10, 1, 30, 0), date(ny, 2024, 3, 10, 3, 15, 0)},
+		// 3 ноября 2024 часы переводят с 02:00 EDT на 01:00 EST: час 01:xx повторяется.
+		{"repeated time first pass", "30 1 * * *", ny, date(ny, 2024, 11, 3, 0, 0, 0), date(utc, 2024, 11, 3, 5, 30, 0)},
+		{"repeated time runs once", "30 1 * * *", ny, date(utc, 2024, 11, 3, 5, 30, 0), date(ny, 2024, 11, 4, 1, 30, 0)},
+		{"hourly runs in repeated hour", "30 * * * *", ny, date(utc, 2024, 11, 3, 5, 30, 0), date(utc, 2024, 11, 3, 6, 30, 0)},
+		{"hourly after repeated hour", "30 * * * *", ny, date(utc, 2024, 11, 3, 6, 30, 0), date(utc, 2024, 11, 3, 7, 30, 0)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewCronSchedule(tc.expr, tc.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Next(tc.after); !got.Equal(tc.want) {
+				t.Fatalf("Next(%v) = %v, want %v", tc.after, got.In(tc.loc), tc.want.In(tc.loc))
+			}
+		})
+	}
+}
+
+func TestCronScheduleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"0 0 0 * *",
+		"0 0 32 * *",
+		"* * * 13 *",
+		"* * * * MON-FOO",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"@every 5m",
+	} {
+		if _, err := NewCronSchedule(expr, time.UTC); err == nil {
+			t.Errorf("NewCronSchedule(%q) error = nil, want error", expr)
+		}
+	}
+}
+
+// waitFor ждёт, пока cond не станет истинным, но не дольше секунды.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func historyOf(t *testing.T, s Scheduler, id string) []RunRecord {
+	t.Helper()
+	h, err := s.History(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestFileJobStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	store := NewFileJobStore(path)
+	if jobs, err := store.Load(); err != nil || len(jobs) != 0 {
+		t.Fatalf("Load() on missing file = %v, %v; want no jobs", jobs, err)
+	}
+
+	next := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	jobs := []Job{
+		{ID: "b", Func: "report", Schedule: "30 9 * * MON-FRI", Location: "Europe/Moscow", NextRun: next,
+			Options: JobOptions{Misfire: MisfireCatchUp, Overlap: OverlapWait},
+			History: []RunRecord{{Scheduled: next, Started: next, Finished: next.Add(time.Second), Err: "boom"}}},
+		{ID: "a", Func: "tick", Schedule: "@every 1m0s"},
+		{ID: "c", Func: "tick"},
+	}
+	for _, job := range jobs {
+		if err := store.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewFileJobStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Fatalf("Load() = %+v, want jobs a and b sorted by ID", got)
+	}
+	b := got[1]
+	if !b.NextRun.Equal(next) || b.Options != jobs[0].Options || b.Location != "Europe/Moscow" ||
+		len(b.History) != 1 || b.History[0].Err != "boom" || !b.History[0].Finished.Equal(next.Add(time.Second)) {
+		t.Fatalf("job b after round trip = %+v", b)
+	}
+}
+
+func TestScheduleBeforeStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule func(s *SimpleScheduler, fn func() error) error
+	}{
+		{"once", func(s *SimpleScheduler, fn func() error) error {
+			_, err := s.ScheduleOnce(10*time.Millisecond, fn)
+			return err
+		}},
+		{"stored job", func(s *SimpleScheduler, _ func() error) error {
+			_, err := s.ScheduleJob("tick", &PeriodicSchedule{Interval: 40 * time.Millisecond}, JobOptions{})
+			return err
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var runs atomic.Int32
+			s := NewSimpleScheduler()
+			s.Register("tick", counter(&runs))
+			if err := tc.schedule(s, counter(&runs)); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if n := runs.Load(); n != 0 {
+				t.Fatalf("task ran %d times before Start", n)
+			}
+
+			// Задача из хранилища, поставленная до Start, не поднимается второй раз.
+			if err := s.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Stop()
+			waitFor(t, "missed run after Start", func() bool { return runs.Load() >= 1 })
+			time.Sleep(10 * time.Millisecond)
+			if n := runs.Load(); n != 1 {
+				t.Fatalf("task ran %d times after Start, want 1", n)
+			}
+		})
+	}
+}
+
+func TestStartStopMisuse(t *testing.T) {
+	store := NewMemoryJobStore()
+	store.Save(Job{ID: "job", Func: "tick", Schedule: "@every 1h0m0s", NextRun: time.Now().Add(20 * time.Millisecond)})
+	var runs atomic.Int32
+
+	s := NewPersistentScheduler(store)
+	if err := s.Start(); err == nil {
+		t.Fatal("Start with unregistered job function: error = nil")
+	}
+	s.Register("tick", counter(&runs))
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start after a failed Start: %v", err)
+	}
+	if err := s.Start(); !errors.Is(err, ErrStarted) {
+		t.Fatalf("second Start error = %v, want ErrStarted", err)
+	}
+	waitFor(t, "restored job to run", func() bool { return runs.Load() >= 1 })
+	time.Sleep(30 * time.Millisecond)
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("restored job ran %d times, want 1", n)
+	}
+	s.Stop()
+	s.Stop()
+	if err := s.Start(); !errors.Is(err, ErrStarted) {
+		t.Fatalf("Start after Stop error = %v, want ErrStarted", err)
+	}
+
+	// Stop без Start и нулевой планировщик не паникуют.
+	if err := NewSimpleScheduler().Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&SimpleScheduler{}).Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileJobStoreSharedFile(t *testing.T) {
+	// Два хранилища на одном файле: записи не должны портить друг другу файл.
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := range 2 {
+		store := NewFileJobStore(path)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				errs <- store.Save(Job{ID: strconv.Itoa(i*100 + j), Func: "tick"})
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if _, err := NewFileJobStore(path).Load(); err != nil {
+		t.Fatalf("Load after concurrent saves: %v", err)
+	}
+	if tmp, _ := filepath.Glob(path + ".*"); len(tmp) != 0 {
+		t.Fatalf("temporary files left behind: %v", tmp)
+	}
+}
+
+// countingStore считает записи в хранилище.
+type countingStore struct {
+	*MemoryJobStore
+	saves atomic.Int32
+}
+
+func (c *countingStore) Save(job Job) error {
+	c.saves.Add(1)
+	return c.MemoryJobStore.Save(job)
+}
+
+func TestStopWaitsForRuns(t *testing.T) {
+	store := &countingStore{MemoryJobStore: NewMemoryJobStore()}
+	s := NewPersistentScheduler(store)
+	s.Register("slow", func() error { time.Sleep(30 * time.Millisecond); return nil })
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ScheduleJob("slow", &PeriodicSchedule{Interval: 10 * time.Millisecond}, JobOptions{Overlap: OverlapAllow}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond) // запуски идут внахлёст
+
+	s.Stop()
+	n := store.saves.Load()
+	time.Sleep(60 * time.Millisecond)
+	if got := store.saves.Load(); got != n {
+		t.Fatalf("store written %d times after Stop returned", got-n)
+	}
+}
+
+func TestScheduleJobSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	var runs atomic.Int32
+
+	s1 := NewPersistentScheduler(NewFileJobStore(path))
+	s1.Register("tick", counter(&runs))
+	if err := s1.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s1.ScheduleJob("missing", &PeriodicSchedule{Interval: time.Minute}, JobOptions{}); err == nil {
+		t.Fatal("ScheduleJob with unregistered function: error = nil")
+	}
+	if _, err := s1.ScheduleJob("tick", &fixedSchedule{}, JobOptions{}); err == nil {
+		t.Fatal("ScheduleJob with schedule that cannot be stored: error = nil")
+	}
+	task, err := s1.ScheduleJob("tick", &PeriodicSchedule{Interval: 20 * time.Millisecond}, JobOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "two runs", func() bool { return len(historyOf(t, s1, task.ID())) >= 2 })
+	s1.Stop()
+	before := len(historyOf(t, s1, task.ID()))
+
+	// Без зарегистрированной функции задачу из хранилища не поднять.
+	if err := NewPersistentScheduler(NewFileJobStore(path)).Start(); err == nil {
+		t.Fatal("Start with unregistered job function: error = nil")
+	}
+
+	s2 := NewPersistentScheduler(NewFileJobStore(path))
+	s2.Register("tick", counter(&runs))
+	if err := s2.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Stop()
+	waitFor(t, "runs after restart", func() bool { return len(historyOf(t, s2, task.ID())) >= before+2 })
+
+	if err := s2.Cancel(task.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := NewFileJobStore(path).Load(); len(jobs) != 0 {
+		t.Fatalf("cancelled job is still stored: %+v", jobs)
+	}
+}
+
+func TestMisfirePolicies(t *testing.T) {
+	// Планировщик не работал три с половиной часа и пропустил 4 ежечасных запуска.
+	now := time.Now()
+	missed := now.Add(-3*time.Hour - 30*time.Minute)
+
+	tests := []struct {
+		policy      MisfirePolicy
+		wantRuns    int
+		wantSkipped int
+	}{
+		{MisfireFireOnce, 1, 0},
+		{MisfireSkip, 0, 1},
+		{MisfireCatchUp, 4, 0},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			store := NewMemoryJobStore()
+			store.Save(Job{ID: "job", Func: "tick", Schedule: "@every 1h0m0s", NextRun: missed,
+				Options: JobOptions{Misfire: tc.policy}})
+
+			var runs atomic.Int32
+			s := NewPersistentScheduler(store)
+			s.Register("tick", counter(&runs))
+			if err := s.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Stop()
+
+			waitFor(t, "next run moved to the future", func() bool {
+				jobs, _ := store.Load()
+				return jobs[0].NextRun.After(now)
+			})
+			waitFor(t, "history", func() bool { return len(historyOf(t, s, "job")) == tc.wantRuns+tc.wantSkipped })
+			if n := int(runs.Load()); n != tc.wantRuns {
+				t.Fatalf("job ran %d times, want %d", n, tc.wantRuns)
+			}
+
+			var skipped int
+			var prev time.Time
+			for _, rec := range historyOf(t, s, "job") {
+				if rec.Skipped != "" {
+					skipped++
+					continue
+				}
+				if rec.Scheduled.Before(prev) {
+					t.Fatalf("runs out of schedule order: %v after %v", rec.Scheduled, prev)
+				}
+				prev = rec.Scheduled
+			}
+			if skipped != tc.wantSkipped {
+				t.Fatalf("%d skipped runs in history, want %d", skipped, tc.wantSkipped)
+			}
+			if h := historyOf(t, s, "job"); !h[0].Scheduled.Equal(missed) {
+				t.Fatalf("first history record scheduled at %v, want first missed run %v", h[0].Scheduled, missed)
+			}
+		})
+	}
+}
+
+func TestNoNextRun(t *testing.T) {
+	impossible, err := NewCronSchedule("0 0 30 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		job   *Job // задача в хранилище до Start
+		sched Schedule
+	}{
+		{name: "impossible cron", sched: impossible},
+		{name: "restored job without next run", job: &Job{ID: "job", Func: "tick", Schedule: "@every 1h0m0s"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryJobStore()
+			if tc.job != nil {
+				store.Save(*tc.job)
+			}
+			var runs atomic.Int32
+			s := NewPersistentScheduler(store)
+			s.Register("tick", counter(&runs))
+			if err := s.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Stop()
+			if tc.sched != nil {
+				if _, err := s.ScheduleJob("tick", tc.sched, JobOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			if n := runs.Load(); n != 0 {
+				t.Fatalf("job without next run ran %d times", n)
+			}
+		})
+	}
+}
+
+func TestRestoredHistoryIsCapped(t *testing.T) {
+	missed := time.Now().Add(-time.Minute)
+	history := make([]RunRecord, MaxHistory+50)
+	for i := range history {
+		history[i].Scheduled = missed.Add(time.Duration(i-len(history)) * time.Hour)
+	}
+	store := NewMemoryJobStore()
+	store.Save(Job{ID: "job", Func: "tick", Schedule: "@every 1h0m0s", NextRun: missed,
+		Options: JobOptions{Misfire: MisfireSkip}, History: history})
+	store.Save(Job{ID: "later", Func: "tick", Schedule: "@every 1h0m0s", NextRun: time.Now().Add(time.Hour), History: history})
+
+	s := NewPersistentScheduler(store)
+	s.Register("tick", func() error { return nil })
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	waitFor(t, "misfire to be saved", func() bool {
+		jobs, _ := store.Load()
+		return jobs[0].NextRun.After(missed)
+	})
+
+	if h := historyOf(t, s, "later"); len(h) != MaxHistory || !h[0].Scheduled.Equal(history[50].Scheduled) {
+		t.Fatalf("job without runs restored with %d history records, want last %d", len(h), MaxHistory)
+	}
+	jobs, _ := store.Load()
+	for name, h := range map[string][]RunRecord{"History": historyOf(t, s, "job"), "stored": jobs[0].History} {
+		if len(h) != MaxHistory {
+			t.Fatalf("%s has %d records, want %d", name, len(h), MaxHistory)
+		}
+		if last := h[len(h)-1]; last.Skipped != "misfire" || !h[0].Scheduled.Equal(history[51].Scheduled) {
+			t.Fatalf("%s keeps wrong records: first %v, last %+v", name, h[0].Scheduled, last)
+		}
+	}
+}
+
+// blockingStore — хранилище, запись в которое висит, пока открыт hold.
+type blockingStore struct {
+	*MemoryJobStore
+	hold chan struct{}
+}
+
+func (b *blockingStore) Save(job Job) error {
+	<-b.hold
+	return b.MemoryJobStore.Save(job)
+}
+
+func TestSlowStoreDoesNotBlockTask(t *testing.T) {
+	store := &blockingStore{MemoryJobStore: NewMemoryJobStore(), hold: make(chan struct{}, 1)}
+	store.hold <- struct{}{} // первая запись — из ScheduleJob
+	var runs atomic.Int32
+	s := NewPersistentScheduler(store)
+	s.Register("tick", counter(&runs))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	task, err := s.ScheduleJob("tick", &PeriodicSchedule{Interval: 10 * time.Millisecond}, JobOptions{Overlap: OverlapAllow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		close(store.hold) // Stop дожидается записи, которая висит в хранилище
+		s.Stop()
+	}()
+
+	// Цикл задачи висит на записи следующего запуска, а History и завершение
+	// запуска не должны его ждать.
+	waitFor(t, "first run", func() bool { return runs.Load() >= 1 })
+	got := make(chan int)
+	go func() {
+		for {
+			if h, _ := s.History(task.ID()); len(h) > 0 {
+				got <- len(h)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	select {
+	case <-got:
+	case <-time.After(time.Second):
+		t.Fatal("run is not in history while the store is busy")
+	}
+}
+
+func TestOverlapPolicies(t *testing.T) {
+	tests := []struct {
+		policy       OverlapPolicy
+		wantParallel bool
+		wantSkipped  bool
+	}{
+		{"", false, false}, // по умолчанию — как wait
+		{OverlapSkip, false, true},
+		{OverlapWait, false, false},
+		{OverlapAllow, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(cmp.Or(string(tc.policy), "default"), func(t *testing.T) {
+			var (
+				mu                    sync.Mutex
+				inflight, maxInflight int
+			)
+			slow := func() error {
+				mu.Lock()
+				inflight++
+				maxInflight = max(maxInflight, inflight)
+				mu.Unlock()
+				time.Sleep(40 * time.Millisecond)
+				mu.Lock()
+				inflight--
+				mu.Unlock()
+				return nil
+			}
+
+			s := NewSimpleScheduler()
+			s.Register("slow", slow)
+			if err := s.Start(); err != nil {
+				t.Fatal(err)
+			}
+			task, err := s.ScheduleJob("slow", &PeriodicSchedule{Interval: 10 * time.Millisecond}, JobOptions{Overlap: tc.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(150 * time.Millisecond)
+			s.Stop()
+			waitFor(t, "running jobs to finish", func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return inflight == 0
+			})
+
+			if parallel := maxInflight > 1; parallel != tc.wantParallel {
+				t.Fatalf("max %d concurrent runs, want parallel = %v", maxInflight, tc.wantParallel)
+			}
+			var skipped bool
+			for _, rec := range historyOf(t, s, task.ID()) {
+				if rec.Skipped == "overlap" {
+					skipped = true
+				}
+			}
+			if skipped != tc.wantSkipped {
+				t.Fatalf("history has overlap skips = %v, want %v", skipped, tc.wantSkipped)
+			}
+		})
+	}
+}
@@ -60,6 +60,9 @@ func (c *SimpleContext) Set(key string, value interface{}) {
 }
 
 func (c *SimpleContext) Next() error {
+	if c.IsAborted() {
+		return c.Error()
+	}
 	c.index++
 	if c.index < len(c.chain) {
 		return c.chain[c.index].Handle(c)
@@ -67,9 +70,26 @@ func (c *SimpleContext) Next() error {
 	return nil
 }
 
-func (c *SimpleContext) Abort(err error) { c.aborted = true; c.err = err }
-func (c *SimpleContext) IsAborted() bool { return c.aborted }
-func (c *SimpleContext) Error() error    { return c.err }
+// Abort, IsAborted и Error берут мьютекс: TimeoutMiddleware прерывает
+// цепочку, пока обработчики ещё работают в своей горутине.
+func (c *SimpleContext) Abort(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.aborted = true
+	c.err = err
+}
+
+func (c *SimpleContext) IsAborted() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.aborted
+}
+
+func (c *SimpleContext) Error() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
 
 // --- Chain ---
 
@@ -105,10 +125,11 @@ func (m *LoggingMiddleware) Handle(ctx Context) error {
 
 type RecoveryMiddleware struct{}
 
-func (m *RecoveryMiddleware) Handle(ctx Context) error {
+func (m *RecoveryMiddleware) Handle(ctx Context) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			ctx.Abort(fmt.Errorf("panic recovered: %v", r))
+			err = ctx.Error() // иначе паника превратилась бы в успешный nil
 		}
 	}()
 	return ctx.Next()
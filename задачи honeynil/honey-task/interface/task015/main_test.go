@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// step записывает своё имя в трассу и вызывает следующий обработчик.
+type step struct {
+	name  string
+	trace *[]string
+}
+
+func (s *step) Handle(ctx Context) error {
+	*s.trace = append(*s.trace, s.name+":before")
+	err := ctx.Next()
+	*s.trace = append(*s.trace, s.name+":after")
+	return err
+}
+
+// terminal — последний обработчик цепочки.
+type terminal struct {
+	fn func(ctx Context) error
+}
+
+func (h *terminal) Handle(ctx Context) error { return h.fn(ctx) }
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	var chain MiddlewareChain = &Chain{}
+	chain.Use(&step{"a", &trace}).Use(&step{"b", &trace}).Use(&terminal{func(Context) error {
+		trace = append(trace, "handler")
+		return nil
+	}})
+
+	if err := chain.Execute(NewSimpleContext(context.Background())); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a:before", "b:before", "handler", "b:after", "a:after"}
+	if !slices.Equal(trace, want) {
+		t.Fatalf("trace = %v, want %v", trace, want)
+	}
+}
+
+func TestChainMiddlewares(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	tests := []struct {
+		name        string
+		middlewares []Middleware
+		token       string
+		handler     func(ctx Context) error
+		wantErr     string // подстрока ошибки, "" — без ошибки
+		wantAborted bool
+		wantCalled  bool
+	}{
+		{
+			name:        "auth without token",
+			middlewares: []Middleware{&AuthMiddleware{}},
+			wantErr:     "unauthorized",
+			wantAborted: true,
+		},
+		{
+			name:        "auth with token",
+			middlewares: []Middleware{&AuthMiddleware{}},
+			token:       "Bearer abc",
+			wantCalled:  true,
+		},
+		{
+			name:        "handler error propagates",
+			middlewares: []Middleware{&RecoveryMiddleware{}},
+			handler:     func(Context) error { return errHandler },
+			wantErr:     errHandler.Error(),
+			wantCalled:  true,
+		},
+		{
+			name:        "recovery turns panic into error",
+			middlewares: []Middleware{&RecoveryMiddleware{}},
+			handler:     func(Context) error { panic("boom") },
+			wantErr:     "boom",
+			wantAborted: true,
+			wantCalled:  true,
+		},
+		{
+			name:        "timeout aborts slow handler",
+			middlewares: []Middleware{&TimeoutMiddleware{Timeout: 10 * time.Millisecond}},
+			handler:     func(Context) error { time.Sleep(100 * time.Millisecond); return nil },
+			wantErr:     "timeout",
+			wantAborted: true,
+			wantCalled:  true,
+		},
+		{
+			name:        "timeout passes fast handler",
+			middlewares: []Middleware{&TimeoutMiddleware{Timeout: time.Second}},
+			wantCalled:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := make(chan struct{}, 1)
+			handler := tc.handler
+			if handler == nil {
+				handler = func(Context) error { return nil }
+			}
+			chain := &Chain{}
+			for _, m := range tc.middlewares {
+				chain.Use(m)
+			}
+			chain.Use(&terminal{func(ctx Context) error {
+				called <- struct{}{}
+				return handler(ctx)
+			}})
+
+			ctx := NewSimpleContext(context.Background())
+			if tc.token != "" {
+				ctx.Set("token", tc.token)
+			}
+			err := chain.Execute(ctx)
+
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("Execute() error = %v, want %q", err, tc.wantErr)
+			}
+			if ctx.IsAborted() != tc.wantAborted {
+				t.Fatalf("IsAborted() = %v, want %v", ctx.IsAborted(), tc.wantAborted)
+			}
+			if tc.wantAborted && ctx.Error() == nil {
+				t.Fatal("aborted context must keep its error")
+			}
+			if got := len(called) == 1; got != tc.wantCalled {
+				t.Fatalf("handler called = %v, want %v", got, tc.wantCalled)
+			}
+		})
+	}
+}
+
+func TestContextValues(t *testing.T) {
+	var ctx Context = NewSimpleContext(context.Background())
+	if _, ok := ctx.Get("user"); ok {
+		t.Fatal("Get on empty context must report missing key")
+	}
+	ctx.Set("user", "alice")
+	if v, ok := ctx.Get("user"); !ok || v != "alice" {
+		t.Fatalf("Get(user) = %v, %v", v, ok)
+	}
+}
+
+func TestAbortStopsChain(t *testing.T) {
+	errStop := errors.New("stop")
+	var trace []string
+	chain := &Chain{}
+	chain.Use(&terminal{func(ctx Context) error {
+		ctx.Abort(errStop)
+		return ctx.Next()
+	}}).Use(&step{"after-abort", &trace})
+
+	err := chain.Execute(NewSimpleContext(context.Background()))
+	if !errors.Is(err, errStop) {
+		t.Fatalf("Execute() error = %v, want %v", err, errStop)
+	}
+	if len(trace) != 0 {
+		t.Fatalf("middlewares after Abort ran: %v", trace)
+	}
+}
@@ -3,6 +3,7 @@ package main
 // Задача: Storage — файловая система, память, составное хранилище.
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -44,7 +45,7 @@ func (m *MemoryStorage) Put(key string, data []byte, meta Metadata) error {
 	meta.Size = int64(len(data))
 	meta.LastModified = time.Now()
 	m.mu.Lock()
-	m.data[key] = entry{data: data, metadata: meta}
+	m.data[key] = entry{data: bytes.Clone(data), metadata: meta} // копия: вызывающий может переиспользовать буфер
 	m.mu.Unlock()
 	return nil
 }
@@ -56,7 +57,7 @@ func (m *MemoryStorage) Get(key string) ([]byte, Metadata, error) {
 	if !ok {
 		return nil, Metadata{}, fmt.Errorf("key %q not found", key)
 	}
-	return e.data, e.metadata, nil
+	return bytes.Clone(e.data), e.metadata, nil
 }
 
 func (m *MemoryStorage) Delete(key string) error {
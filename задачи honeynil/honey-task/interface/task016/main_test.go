@@ -0,0 +1,125 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// storages возвращает все реализации Storage для общих тестов.
+func storages(t *testing.T) map[string]Storage {
+	t.Helper()
+	fs, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	slowFS, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]Storage{
+		"memory": NewMemoryStorage(),
+		"file":   fs,
+		"tiered": NewTieredStorage(NewMemoryStorage(), slowFS),
+	}
+}
+
+func TestStorage(t *testing.T) {
+	for name, s := range storages(t) {
+		t.Run(name, func(t *testing.T) {
+			if ok, err := s.Exists("a/1"); err != nil || ok {
+				t.Fatalf("Exists on empty storage = %v, %v", ok, err)
+			}
+			if _, _, err := s.Get("a/1"); err == nil {
+				t.Fatal("Get of missing key must fail")
+			}
+
+			for _, key := range []string{"a/1", "a/2", "b/1"} {
+				if err := s.Put(key, []byte("data-"+key), Metadata{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, meta, err := s.Get("a/1")
+			if err != nil || string(data) != "data-a/1" {
+				t.Fatalf("Get(a/1) = %q, %v", data, err)
+			}
+			if meta.Size != int64(len(data)) {
+				t.Fatalf("meta.Size = %d, want %d", meta.Size, len(data))
+			}
+			if meta.LastModified.IsZero() {
+				t.Fatal("meta.LastModified is not set")
+			}
+
+			keys, err := s.List("a/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(keys)
+			if !slices.Equal(keys, []string{"a/1", "a/2"}) {
+				t.Fatalf("List(a/) = %v, want [a/1 a/2]", keys)
+			}
+
+			if err := s.Delete("a/1"); err != nil {
+				t.Fatal(err)
+			}
+			if ok, _ := s.Exists("a/1"); ok {
+				t.Fatal("deleted key still exists")
+			}
+			if _, _, err := s.Get("a/1"); err == nil {
+				t.Fatal("Get of deleted key must fail")
+			}
+		})
+	}
+}
+
+func TestMemoryStorageCopiesData(t *testing.T) {
+	s := NewMemoryStorage()
+	buf := []byte("hello")
+	s.Put("k", buf, Metadata{ContentType: "text/plain"})
+	buf[0] = 'J'
+
+	data, meta, _ := s.Get("k")
+	if string(data) != "hello" {
+		t.Fatalf("stored data changed with caller's buffer: %q", data)
+	}
+	if meta.ContentType != "text/plain" {
+		t.Fatalf("ContentType = %q, want text/plain", meta.ContentType)
+	}
+	data[0] = 'J'
+	if again, _, _ := s.Get("k"); string(again) != "hello" {
+		t.Fatalf("stored data changed through Get result: %q", again)
+	}
+}
+
+func TestTieredStorage(t *testing.T) {
+	fast, slow := NewMemoryStorage(), NewMemoryStorage()
+	tiered := NewTieredStorage(fast, slow)
+
+	tiered.Put("both", []byte("1"), Metadata{})
+	if ok, _ := fast.Exists("both"); !ok {
+		t.Fatal("Put must write to the fast tier")
+	}
+	if ok, _ := slow.Exists("both"); !ok {
+		t.Fatal("Put must write to the slow tier")
+	}
+
+	slow.Put("cold", []byte("2"), Metadata{})
+	if data, _, err := tiered.Get("cold"); err != nil || string(data) != "2" {
+		t.Fatalf("Get(cold) = %q, %v", data, err)
+	}
+	if ok, _ := fast.Exists("cold"); !ok {
+		t.Fatal("Get from the slow tier must promote the key to the fast tier")
+	}
+
+	fast.Put("hot", []byte("3"), Metadata{})
+	keys, _ := tiered.List("")
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"both", "cold", "hot"}) {
+		t.Fatalf("List() = %v, want keys of both tiers without duplicates", keys)
+	}
+
+	tiered.Delete("both")
+	if ok, _ := tiered.Exists("both"); ok {
+		t.Fatal("Delete must remove the key from both tiers")
+	}
+}
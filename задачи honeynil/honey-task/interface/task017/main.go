@@ -78,8 +78,13 @@ type ConnectionPool struct {
 	totalOpen   int
 	closed      bool
 	maxLifetime time.Duration
+	// active — выданные соединения: Release возвращает в пул исходный
+	// poolConn, чтобы lifetime считался от создания, а не от последнего Release.
+	active map[Connection]*poolConn
 }
 
+var ErrPoolClosed = errors.New("pool is closed")
+
 func NewConnectionPool(factory ConnectionFactory, minSize, maxSize int) *ConnectionPool {
 	p := &ConnectionPool{
 		factory:     factory,
@@ -87,6 +92,7 @@ func NewConnectionPool(factory ConnectionFactory, minSize, maxSize int) *Connect
 		maxSize:     maxSize,
 		idle:        make(chan *poolConn, maxSize),
 		maxLifetime: 5 * time.Minute,
+		active:      make(map[Connection]*poolConn),
 	}
 	for i := 0; i < minSize; i++ {
 		if conn, err := factory.Create(); err == nil {
@@ -102,22 +108,21 @@ func NewConnectionPool(factory ConnectionFactory, minSize, maxSize int) *Connect
 func (p *ConnectionPool) Acquire(ctx context.Context) (Connection, error) {
 	for {
 		select {
-		case pc := <-p.idle:
-			if !pc.conn.IsValid() || time.Since(pc.createdAt) > p.maxLifetime {
-				_ = pc.conn.Close()
-				p.mu.Lock()
-				p.totalOpen--
-				p.mu.Unlock()
-				continue
+		case pc, ok := <-p.idle:
+			if !ok {
+				return nil, ErrPoolClosed
 			}
-			return pc.conn, nil
+			if conn, ok := p.checkout(pc); ok {
+				return conn, nil
+			}
+			continue
 		default:
 		}
 
 		p.mu.Lock()
 		if p.closed {
 			p.mu.Unlock()
-			return nil, errors.New("pool is closed")
+			return nil, ErrPoolClosed
 		}
 		if p.totalOpen < p.maxSize {
 			p.totalOpen++
@@ -129,6 +134,9 @@ func (p *ConnectionPool) Acquire(ctx context.Context) (Connection, error) {
 				p.mu.Unlock()
 				return nil, err
 			}
+			p.mu.Lock()
+			p.active[conn] = &poolConn{conn: conn, createdAt: time.Now()}
+			p.mu.Unlock()
 			return conn, nil
 		}
 		p.mu.Unlock()
@@ -136,33 +144,50 @@ func (p *ConnectionPool) Acquire(ctx context.Context) (Connection, error) {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case pc := <-p.idle:
-			if !pc.conn.IsValid() {
-				_ = pc.conn.Close()
-				p.mu.Lock()
-				p.totalOpen--
-				p.mu.Unlock()
-				continue
+		case pc, ok := <-p.idle:
+			if !ok {
+				return nil, ErrPoolClosed
+			}
+			if conn, ok := p.checkout(pc); ok {
+				return conn, nil
 			}
-			return pc.conn, nil
 		}
 	}
 }
 
+// checkout выдаёт соединение из idle или закрывает его, если оно
+// неисправно или прожило дольше maxLifetime.
+func (p *ConnectionPool) checkout(pc *poolConn) (Connection, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !pc.conn.IsValid() || time.Since(pc.createdAt) > p.maxLifetime {
+		_ = pc.conn.Close()
+		p.totalOpen--
+		return nil, false
+	}
+	p.active[pc.conn] = pc
+	return pc.conn, true
+}
+
 func (p *ConnectionPool) Release(conn Connection) error {
 	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc, ok := p.active[conn]
+	if !ok {
+		return errors.New("connection does not belong to the pool")
+	}
+	delete(p.active, conn)
 	if p.closed {
-		p.mu.Unlock()
+		p.totalOpen--
 		return conn.Close()
 	}
-	p.mu.Unlock()
+	// Отправка под мьютексом не блокирует (select с default) и не гонится
+	// с close(p.idle) в Close.
 	select {
-	case p.idle <- &poolConn{conn: conn, createdAt: time.Now()}:
+	case p.idle <- pc:
 	default:
 		_ = conn.Close()
-		p.mu.Lock()
 		p.totalOpen--
-		p.mu.Unlock()
 	}
 	return nil
 }
@@ -180,11 +205,15 @@ func (p *ConnectionPool) Stats() PoolStats {
 
 func (p *ConnectionPool) Close() error {
 	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
 	p.closed = true
-	p.mu.Unlock()
 	close(p.idle)
 	for pc := range p.idle {
 		_ = pc.conn.Close()
+		p.totalOpen--
 	}
 	return nil
 }
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolStats(t *testing.T) {
+	var pool Pool = NewConnectionPool(&MockFactory{}, 2, 3)
+	defer pool.Close()
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		do   func() Connection
+		want PoolStats
+	}{
+		{name: "warm up min size", do: func() Connection { return nil }, want: PoolStats{2, 2, 0}},
+		{name: "acquire idle", do: func() Connection { c, _ := pool.Acquire(ctx); return c }, want: PoolStats{2, 1, 1}},
+		{name: "acquire idle again", do: func() Connection { c, _ := pool.Acquire(ctx); return c }, want: PoolStats{2, 0, 2}},
+		{name: "grow to max", do: func() Connection { c, _ := pool.Acquire(ctx); return c }, want: PoolStats{3, 0, 3}},
+	}
+
+	var conns []Connection
+	for _, step := range steps {
+		if c := step.do(); c != nil {
+			conns = append(conns, c)
+		}
+		if got := pool.Stats(); got != step.want {
+			t.Fatalf("%s: Stats() = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+
+	for _, c := range conns {
+		if err := pool.Release(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := pool.Stats(), (PoolStats{3, 3, 0}); got != want {
+		t.Fatalf("after Release: Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPoolReusesConnections(t *testing.T) {
+	factory := &MockFactory{}
+	pool := NewConnectionPool(factory, 0, 2)
+	defer pool.Close()
+
+	for i := 0; i < 10; i++ {
+		c, err := pool.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Execute("SELECT 1"); err != nil {
+			t.Fatal(err)
+		}
+		pool.Release(c)
+	}
+	if factory.seq != 1 {
+		t.Fatalf("factory created %d connections, want 1", factory.seq)
+	}
+}
+
+func TestPoolAcquireWaits(t *testing.T) {
+	pool := NewConnectionPool(&MockFactory{}, 0, 1)
+	defer pool.Close()
+
+	c, _ := pool.Acquire(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire on exhausted pool = %v, want context.DeadlineExceeded", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pool.Release(c)
+	}()
+	got, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != c {
+		t.Fatal("waiting Acquire must receive the released connection")
+	}
+}
+
+func TestPoolDropsInvalidConnections(t *testing.T) {
+	factory := &MockFactory{}
+	pool := NewConnectionPool(factory, 0, 2)
+	defer pool.Close()
+
+	c, _ := pool.Acquire(context.Background())
+	pool.Release(c)
+	c.Close() // соединение сломалось, пока лежало в пуле
+
+	got, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsValid() {
+		t.Fatal("Acquire returned an invalid connection")
+	}
+	if got := pool.Stats().TotalConnections; got != 1 {
+		t.Fatalf("TotalConnections = %d, want 1", got)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	pool := NewConnectionPool(&MockFactory{}, 2, 4)
+	c, _ := pool.Acquire(context.Background())
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Acquire(context.Background()); err == nil {
+		t.Fatal("Acquire on closed pool must fail")
+	}
+	// Release после Close закрывает соединение, а не паникует.
+	pool.Release(c)
+	if c.IsValid() {
+		t.Fatal("connection released to a closed pool must be closed")
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	const maxSize = 3
+	pool := NewConnectionPool(&MockFactory{}, 1, maxSize)
+	defer pool.Close()
+
+	var inUse, peak atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				c, err := pool.Acquire(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				n := inUse.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(100 * time.Microsecond)
+				inUse.Add(-1)
+				pool.Release(c)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p > maxSize {
+		t.Fatalf("%d connections in use at once, max is %d", p, maxSize)
+	}
+	if st := pool.Stats(); st.TotalConnections > maxSize || st.ActiveConnections != 0 {
+		t.Fatalf("Stats() = %+v after all releases", st)
+	}
+}
@@ -119,6 +119,9 @@ func (f *FilteredSubject) Notify(event string, data interface{}) error {
 type asyncEntry struct {
 	observer Observer
 	ch       chan struct{ event string; data interface{} }
+	// done закрывается в Detach. Сам ch не закрываем: Notify отправляет
+	// без блокировки и мог бы попасть в уже закрытый канал.
+	done chan struct{}
 }
 
 type AsyncSubject struct {
@@ -130,13 +133,19 @@ func (a *AsyncSubject) Attach(o Observer) error {
 	e := &asyncEntry{
 		observer: o,
 		ch:       make(chan struct{ event string; data interface{} }, 64),
+		done:     make(chan struct{}),
 	}
 	a.mu.Lock()
 	a.entries = append(a.entries, e)
 	a.mu.Unlock()
 	go func() {
-		for msg := range e.ch {
-			e.observer.Update(msg.event, msg.data)
+		for {
+			select {
+			case <-e.done:
+				return
+			case msg := <-e.ch:
+				e.observer.Update(msg.event, msg.data)
+			}
 		}
 	}()
 	return nil
@@ -147,7 +156,7 @@ func (a *AsyncSubject) Detach(o Observer) error {
 	defer a.mu.Unlock()
 	for i, e := range a.entries {
 		if e.observer == o {
-			close(e.ch)
+			close(e.done)
 			a.entries = append(a.entries[:i], a.entries[i+1:]...)
 			return nil
 		}
@@ -160,8 +169,13 @@ func (a *AsyncSubject) Notify(event string, data interface{}) error {
 	entries := make([]*asyncEntry, len(a.entries))
 	copy(entries, a.entries)
 	a.mu.RUnlock()
+	// Отправляем без блокировки: обработчик может сам вызвать Attach или
+	// Detach, и удержание a.mu на время отправки привело бы к deadlock.
 	for _, e := range entries {
-		e.ch <- struct{ event string; data interface{} }{event, data}
+		select {
+		case <-e.done: // наблюдатель отписался после копирования списка
+		case e.ch <- struct{ event string; data interface{} }{event, data}:
+		}
 	}
 	return nil
 }
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder запоминает полученные события.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	err    error
+}
+
+func (r *recorder) Update(event string, _ interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// waitFor ждёт, пока асинхронный наблюдатель получит n событий.
+func (r *recorder) waitFor(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got := r.got(); len(got) >= n {
+			return got
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return r.got()
+}
+
+func TestSubjects(t *testing.T) {
+	subjects := map[string]func() Subject{
+		"simple":   func() Subject { return &SimpleSubject{} },
+		"filtered": func() Subject { return &FilteredSubject{} },
+		"async":    func() Subject { return &AsyncSubject{} },
+	}
+	for name, newSubject := range subjects {
+		t.Run(name, func(t *testing.T) {
+			s := newSubject()
+			a, b := &recorder{}, &recorder{}
+			s.Attach(a)
+			s.Attach(b)
+			s.Notify("e1", nil)
+			s.Notify("e2", nil)
+
+			want := []string{"e1", "e2"}
+			if got := a.waitFor(t, 2); !slices.Equal(got, want) {
+				t.Fatalf("observer a got %v, want %v", got, want)
+			}
+			if got := b.waitFor(t, 2); !slices.Equal(got, want) {
+				t.Fatalf("observer b got %v, want %v", got, want)
+			}
+
+			s.Detach(a)
+			s.Notify("e3", nil)
+			b.waitFor(t, 3)
+			if got := a.got(); !slices.Equal(got, want) {
+				t.Fatalf("detached observer got %v", got)
+			}
+		})
+	}
+}
+
+func TestSimpleSubjectStopsOnError(t *testing.T) {
+	errObserver := errors.New("observer failed")
+	s := &SimpleSubject{}
+	failing, next := &recorder{err: errObserver}, &recorder{}
+	s.Attach(failing)
+	s.Attach(next)
+
+	if err := s.Notify("e", nil); !errors.Is(err, errObserver) {
+		t.Fatalf("Notify() error = %v, want %v", err, errObserver)
+	}
+	if got := next.got(); len(got) != 0 {
+		t.Fatalf("observer after the failing one got %v", got)
+	}
+}
+
+func TestFilteredSubject(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter []string
+		want   []string
+	}{
+		{name: "no filter receives all", want: []string{"user.created", "user.deleted", "order.paid"}},
+		{name: "single event", filter: []string{"user.created"}, want: []string{"user.created"}},
+		{name: "several events", filter: []string{"user.deleted", "order.paid"}, want: []string{"user.deleted", "order.paid"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &FilteredSubject{}
+			r := &recorder{}
+			s.AttachFiltered(r, tc.filter...)
+			for _, e := range []string{"user.created", "user.deleted", "order.paid"} {
+				s.Notify(e, nil)
+			}
+			if got := r.got(); !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAsyncSubjectConcurrentDetach(t *testing.T) {
+	s := &AsyncSubject{}
+	observers := make([]*recorder, 5)
+	for i := range observers {
+		observers[i] = &recorder{}
+		s.Attach(observers[i])
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			s.Notify("e", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		// Detach во время Notify не должен приводить к отправке в закрытый канал.
+		for _, o := range observers {
+			s.Detach(o)
+		}
+	}()
+	wg.Wait()
+}
+
+// selfDetaching отписывается от subject при первом событии.
+type selfDetaching struct {
+	s    *AsyncSubject
+	once sync.Once
+}
+
+func (o *selfDetaching) Update(string, interface{}) error {
+	o.once.Do(func() {
+		o.s.Detach(o)
+		o.s.Attach(&recorder{})
+	})
+	return nil
+}
+
+func TestAsyncSubjectHandlerChangesObservers(t *testing.T) {
+	s := &AsyncSubject{}
+	s.Attach(&selfDetaching{s: s})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Больше, чем помещается в буфер канала наблюдателя.
+		for i := 0; i < 200; i++ {
+			s.Notify("e", i)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify deadlocked when a handler called Detach and Attach")
+	}
+}
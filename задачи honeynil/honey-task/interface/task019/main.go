@@ -4,6 +4,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -145,10 +146,14 @@ func (b *SQLInsertBuilder) Build() (string, []interface{}, error) {
 	}
 	var cols, placeholders []string
 	var args []interface{}
-	for col, val := range b.values {
+	// Порядок обхода map случаен — сортируем колонки, чтобы запрос был детерминирован.
+	for col := range b.values {
 		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	for _, col := range cols {
 		placeholders = append(placeholders, "?")
-		args = append(args, val)
+		args = append(args, b.values[col])
 	}
 	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
 		b.table, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func() QueryBuilder
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "all columns",
+			build:     func() QueryBuilder { return NewSelect().From("users") },
+			wantQuery: "SELECT * FROM users",
+		},
+		{
+			name:      "fields and where",
+			build:     func() QueryBuilder { return NewSelect().Select("id", "name").From("users").Where("age > ?", 18) },
+			wantQuery: "SELECT id, name FROM users WHERE age > ?",
+			wantArgs:  []interface{}{18},
+		},
+		{
+			name: "full query",
+			build: func() QueryBuilder {
+				return NewSelect().Select("id").From("users").
+					Join("orders", "users.id = orders.user_id").
+					Where("age > ?", 18).Where("active = ? AND role IN (?, ?)", true, "a", "b").
+					OrderBy("name", false).OrderBy("id", true).
+					Limit(10).Offset(20)
+			},
+			wantQuery: "SELECT id FROM users JOIN orders ON users.id = orders.user_id WHERE age > ? AND active = ? AND role IN (?, ?) ORDER BY name, id DESC LIMIT 10 OFFSET 20",
+			wantArgs:  []interface{}{18, true, "a", "b"},
+		},
+		{
+			name:      "limit zero is explicit",
+			build:     func() QueryBuilder { return NewSelect().From("t").Limit(0) },
+			wantQuery: "SELECT * FROM t LIMIT 0",
+		},
+		{
+			name:    "missing from",
+			build:   func() QueryBuilder { return NewSelect().Select("id") },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, args, err := tc.build().Build()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if q != tc.wantQuery {
+				t.Fatalf("query:\n got %s\nwant %s", q, tc.wantQuery)
+			}
+			if len(args) != 0 || len(tc.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tc.wantArgs) {
+					t.Fatalf("args = %v, want %v", args, tc.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestInsertBuilder(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func() InsertBuilder
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name: "columns in stable order",
+			build: func() InsertBuilder {
+				return NewInsert().Into("users").Values(map[string]interface{}{"name": "Alice", "age": 25, "email": "a@b"})
+			},
+			wantQuery: "INSERT INTO users (age, email, name) VALUES (?, ?, ?)",
+			wantArgs:  []interface{}{25, "a@b", "Alice"},
+		},
+		{
+			name:    "missing table",
+			build:   func() InsertBuilder { return NewInsert().Values(map[string]interface{}{"a": 1}) },
+			wantErr: true,
+		},
+		{
+			name:    "no values",
+			build:   func() InsertBuilder { return NewInsert().Into("users") },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Несколько прогонов: порядок колонок не должен зависеть от обхода map.
+			for i := 0; i < 20; i++ {
+				q, args, err := tc.build().Build()
+				if (err != nil) != tc.wantErr {
+					t.Fatalf("Build() error = %v, wantErr %v", err, tc.wantErr)
+				}
+				if tc.wantErr {
+					return
+				}
+				if q != tc.wantQuery || !reflect.DeepEqual(args, tc.wantArgs) {
+					t.Fatalf("Build() = %s %v, want %s %v", q, args, tc.wantQuery, tc.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateBuilder(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func() UpdateBuilder
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name: "set and where args in order",
+			build: func() UpdateBuilder {
+				return NewUpdate().Table("users").Set("name", "Bob").Set("age", 30).Where("id = ?", 1)
+			},
+			wantQuery: "UPDATE users SET name = ?, age = ? WHERE id = ?",
+			wantArgs:  []interface{}{"Bob", 30, 1},
+		},
+		{
+			name:      "without where",
+			build:     func() UpdateBuilder { return NewUpdate().Table("t").Set("x", 1) },
+			wantQuery: "UPDATE t SET x = ?",
+			wantArgs:  []interface{}{1},
+		},
+		{
+			name:    "no set",
+			build:   func() UpdateBuilder { return NewUpdate().Table("t").Where("id = ?", 1) },
+			wantErr: true,
+		},
+		{
+			name:    "no table",
+			build:   func() UpdateBuilder { return NewUpdate().Set("x", 1) },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, args, err := tc.build().Build()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if q != tc.wantQuery || !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Fatalf("Build() = %s %v, want %s %v", q, args, tc.wantQuery, tc.wantArgs)
+			}
+		})
+	}
+}
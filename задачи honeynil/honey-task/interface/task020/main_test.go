@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryLock(t *testing.T) {
+	ctx := context.Background()
+	var l Lock = NewInMemoryLock()
+
+	if ok, _ := l.Acquire(ctx, "r", time.Minute); !ok {
+		t.Fatal("first Acquire must succeed")
+	}
+	if ok, _ := l.Acquire(ctx, "r", time.Minute); ok {
+		t.Fatal("second Acquire of a held lock must fail")
+	}
+	if ok, _ := l.Acquire(ctx, "other", time.Minute); !ok {
+		t.Fatal("locks on different resources are independent")
+	}
+	if locked, _ := l.IsLocked(ctx, "r"); !locked {
+		t.Fatal("IsLocked = false for a held lock")
+	}
+
+	l.Release(ctx, "r")
+	if locked, _ := l.IsLocked(ctx, "r"); locked {
+		t.Fatal("IsLocked = true after Release")
+	}
+	if ok, _ := l.Acquire(ctx, "r", time.Minute); !ok {
+		t.Fatal("Acquire after Release must succeed")
+	}
+}
+
+func TestInMemoryLockTTL(t *testing.T) {
+	ctx := context.Background()
+	l := NewInMemoryLock()
+
+	l.Acquire(ctx, "r", 20*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if err := l.Refresh(ctx, "r", 40*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if locked, _ := l.IsLocked(ctx, "r"); !locked {
+		t.Fatal("Refresh must extend the lock")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if locked, _ := l.IsLocked(ctx, "r"); locked {
+		t.Fatal("lock must expire after ttl")
+	}
+	if ok, _ := l.Acquire(ctx, "r", time.Minute); !ok {
+		t.Fatal("expired lock must be acquirable")
+	}
+	if err := l.Refresh(ctx, "missing", time.Minute); err == nil {
+		t.Fatal("Refresh of a lock that is not held must fail")
+	}
+}
+
+func TestAcquireWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		heldFor   time.Duration // через сколько держатель отпустит блокировку
+		retries   int
+		ctxTimout time.Duration
+		wantErr   bool
+		wantCtx   bool
+	}{
+		{name: "free lock", retries: 0},
+		{name: "released while retrying", heldFor: 25 * time.Millisecond, retries: 10},
+		{name: "retries exhausted", heldFor: time.Hour, retries: 2, wantErr: true},
+		{name: "context cancelled", heldFor: time.Hour, retries: 100, ctxTimout: 25 * time.Millisecond, wantErr: true, wantCtx: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			base := NewInMemoryLock()
+			var l DistributedLock = NewRetryableLock(base, 10*time.Millisecond)
+			ctx := context.Background()
+			if tc.ctxTimout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimout)
+				defer cancel()
+			}
+			if tc.heldFor > 0 {
+				base.Acquire(context.Background(), "r", tc.heldFor)
+			}
+
+			err := l.AcquireWithRetry(ctx, "r", time.Minute, tc.retries)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("AcquireWithRetry() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantCtx && !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("AcquireWithRetry() error = %v, want context.DeadlineExceeded", err)
+			}
+		})
+	}
+}
+
+func TestWithLockIsExclusive(t *testing.T) {
+	l := NewRetryableLock(NewInMemoryLock(), time.Millisecond)
+	var inside, peak atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 5; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := l.WithLock(context.Background(), "r", time.Minute, func() error {
+				n := inside.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(2 * time.Millisecond)
+				inside.Add(-1)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p != 1 {
+		t.Fatalf("%d goroutines were inside WithLock at once", p)
+	}
+	if locked, _ := l.IsLocked(context.Background(), "r"); locked {
+		t.Fatal("WithLock must release the lock")
+	}
+}
+
+func TestWithLockReturnsError(t *testing.T) {
+	errFn := errors.New("fn failed")
+	l := NewRetryableLock(NewInMemoryLock(), time.Millisecond)
+	if err := l.WithLock(context.Background(), "r", time.Minute, func() error { return errFn }); !errors.Is(err, errFn) {
+		t.Fatalf("WithLock() error = %v, want %v", err, errFn)
+	}
+	if locked, _ := l.IsLocked(context.Background(), "r"); locked {
+		t.Fatal("WithLock must release the lock even if fn fails")
+	}
+}
+
+func TestAutoRefreshLock(t *testing.T) {
+	ctx := context.Background()
+	base := NewInMemoryLock()
+	l := NewAutoRefreshLock(base, 0)
+
+	cancel, err := l.AcquireAutoRefresh(ctx, "r", 30*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.AcquireAutoRefresh(ctx, "r", 30*time.Millisecond); err == nil {
+		t.Fatal("AcquireAutoRefresh of a held lock must fail")
+	}
+
+	time.Sleep(100 * time.Millisecond) // больше трёх ttl
+	if locked, _ := base.IsLocked(ctx, "r"); !locked {
+		t.Fatal("auto refresh must keep the lock past its ttl")
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		locked, _ := base.IsLocked(ctx, "r")
+		if !locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cancel must release the lock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
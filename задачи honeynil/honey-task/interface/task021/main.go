@@ -160,12 +160,9 @@ func (q *DistributedTaskQueue) Dequeue(ctx context.Context, timeout time.Duratio
 	deadline := time.Now().Add(timeout)
 	for {
 		q.mu.Lock()
-		for q.pending.Len() > 0 {
-			item := heap.Pop(&q.pending).(*taskItem)
-			if item.task.ScheduledAt.After(time.Now()) {
-				heap.Push(&q.pending, item)
-				break
-			}
+		item, nextAt := q.next(time.Now())
+		if item != nil {
+			heap.Remove(&q.pending, item.index)
 			q.processing[item.task.ID] = item
 			q.mu.Unlock()
 			return &item.task, nil
@@ -176,14 +173,39 @@ func (q *DistributedTaskQueue) Dequeue(ctx context.Context, timeout time.Duratio
 		if remaining <= 0 {
 			return nil, context.DeadlineExceeded
 		}
+		// Отложенная задача станет готовой без сигнала в notify — просыпаемся к её сроку.
+		wait := remaining
+		if !nextAt.IsZero() && time.Until(nextAt) < wait {
+			wait = time.Until(nextAt)
+		}
+		timer := time.NewTimer(wait)
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return nil, ctx.Err()
 		case <-q.notify:
-		case <-time.After(remaining):
-			return nil, context.DeadlineExceeded
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// next возвращает готовую задачу с наибольшим приоритетом и срок ближайшей
+// отложенной. Вершина кучи может быть отложена (retry с backoff), поэтому
+// готовые задачи ищутся по всей куче. Вызывается под q.mu.
+func (q *DistributedTaskQueue) next(now time.Time) (ready *taskItem, nextAt time.Time) {
+	for _, item := range q.pending {
+		if item.task.ScheduledAt.After(now) {
+			if nextAt.IsZero() || item.task.ScheduledAt.Before(nextAt) {
+				nextAt = item.task.ScheduledAt
+			}
+			continue
+		}
+		if ready == nil || q.pending.Less(item.index, ready.index) {
+			ready = item
 		}
 	}
+	return ready, nextAt
 }
 
 func (q *DistributedTaskQueue) Complete(_ context.Context, taskID string, result interface{}) error {
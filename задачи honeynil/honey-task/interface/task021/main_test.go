@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueuePriority(t *testing.T) {
+	ctx := context.Background()
+	var q TaskQueue = NewDistributedTaskQueue()
+	base := time.Now().Add(-time.Minute)
+	q.EnqueueBatch(ctx, []Task{
+		{ID: "low", Priority: PriorityLow, ScheduledAt: base},
+		{ID: "high-2", Priority: PriorityHigh, ScheduledAt: base.Add(2 * time.Second)},
+		{ID: "critical", Priority: PriorityCritical, ScheduledAt: base.Add(3 * time.Second)},
+		{ID: "high-1", Priority: PriorityHigh, ScheduledAt: base.Add(time.Second)},
+		{ID: "normal", Priority: PriorityNormal, ScheduledAt: base},
+	})
+
+	var got []string
+	for i := 0; i < 5; i++ {
+		task, err := q.Dequeue(ctx, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, task.ID)
+	}
+	want := []string{"critical", "high-1", "high-2", "normal", "low"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("dequeue order = %v, want %v", got, want)
+	}
+}
+
+func TestQueueDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	q.Enqueue(ctx, Task{ID: "t1"})
+	q.Enqueue(ctx, Task{ID: "t1"})
+	q.EnqueueBatch(ctx, []Task{{ID: "t1"}, {ID: "t2"}})
+
+	task, _ := q.Dequeue(ctx, time.Second)
+	q.Complete(ctx, task.ID, nil)
+	q.Enqueue(ctx, Task{ID: task.ID}) // повтор уже обработанной задачи
+
+	stats, _ := q.Stats(ctx)
+	if stats.PendingTasks != 1 || stats.CompletedTasks != 1 {
+		t.Fatalf("Stats() = %+v, want 1 pending and 1 completed", stats)
+	}
+}
+
+func TestQueueDequeueTimeout(t *testing.T) {
+	q := NewDistributedTaskQueue()
+	start := time.Now()
+	if _, err := q.Dequeue(context.Background(), 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Dequeue() on empty queue error = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Fatalf("Dequeue() returned after %v, want to wait for the timeout", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Dequeue(ctx, time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Dequeue() with cancelled ctx error = %v, want Canceled", err)
+	}
+}
+
+func TestQueueDequeueWakesOnEnqueue(t *testing.T) {
+	q := NewDistributedTaskQueue()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Enqueue(context.Background(), Task{ID: "late"})
+	}()
+	task, err := q.Dequeue(context.Background(), time.Second)
+	if err != nil || task.ID != "late" {
+		t.Fatalf("Dequeue() = %v, %v", task, err)
+	}
+}
+
+func TestQueueRetryAndDLQ(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	errTask := errors.New("task failed")
+	q.Enqueue(ctx, Task{ID: "flaky", MaxRetries: 2})
+
+	task, _ := q.Dequeue(ctx, time.Second)
+	if err := q.Fail(ctx, task.ID, errTask); err != nil {
+		t.Fatal(err)
+	}
+	stats, _ := q.Stats(ctx)
+	if stats.PendingTasks != 1 || stats.DLQTasks != 0 {
+		t.Fatalf("after first failure Stats() = %+v, want task re-queued", stats)
+	}
+
+	// Повтор откладывается с backoff, но Dequeue должен дождаться его сам.
+	task, err := q.Dequeue(ctx, 2*time.Second)
+	if err != nil {
+		t.Fatalf("retried task was not dequeued: %v", err)
+	}
+	q.Fail(ctx, task.ID, errTask)
+
+	stats, _ = q.Stats(ctx)
+	want := QueueStats{DLQTasks: 1, FailedTasks: 1}
+	if stats != want {
+		t.Fatalf("Stats() = %+v, want %+v", stats, want)
+	}
+	dlq, _ := q.GetDeadLetterQueue(ctx, 10)
+	if len(dlq) != 1 || dlq[0].ID != "flaky" {
+		t.Fatalf("GetDeadLetterQueue() = %v", dlq)
+	}
+}
+
+func TestQueueBackoffDoesNotBlockReadyTasks(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	q.Enqueue(ctx, Task{ID: "critical", Priority: PriorityCritical, MaxRetries: 5})
+	task, _ := q.Dequeue(ctx, time.Second)
+	q.Fail(ctx, task.ID, errors.New("fail")) // отложена на backoff
+
+	q.Enqueue(ctx, Task{ID: "low", Priority: PriorityLow})
+	task, err := q.Dequeue(ctx, 50*time.Millisecond)
+	if err != nil || task.ID != "low" {
+		t.Fatalf("Dequeue() = %v, %v; ready low-priority task must not wait for a delayed one", task, err)
+	}
+}
+
+func TestQueueCompleteUnknown(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	if err := q.Complete(ctx, "missing", nil); err == nil {
+		t.Fatal("Complete of a task that is not processing must fail")
+	}
+	if err := q.Fail(ctx, "missing", errors.New("x")); err == nil {
+		t.Fatal("Fail of a task that is not processing must fail")
+	}
+}
+
+func TestQueueConcurrentConsumers(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	const n = 100
+	for i := 0; i < n; i++ {
+		q.Enqueue(ctx, Task{ID: string(rune('a'+i%26)) + string(rune('0'+i/26))})
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				task, err := q.Dequeue(ctx, 10*time.Millisecond)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[task.ID]++
+				mu.Unlock()
+				q.Complete(ctx, task.ID, nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != n {
+		t.Fatalf("consumed %d distinct tasks, want %d", len(seen), n)
+	}
+	for id, c := range seen {
+		if c != 1 {
+			t.Fatalf("task %s dequeued %d times", id, c)
+		}
+	}
+}
+
+func TestWorkerCompletesTasks(t *testing.T) {
+	ctx := context.Background()
+	q := NewDistributedTaskQueue()
+	var w Worker = NewTaskWorker(q)
+	if err := w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		q.Enqueue(ctx, Task{ID: id})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		stats, _ := q.Stats(ctx)
+		if stats.CompletedTasks == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker did not complete tasks: %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := w.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -83,7 +83,8 @@ func (n *localNode) Get(_ context.Context, key string) (interface{}, bool, error
 	if !ok {
 		return nil, false, nil
 	}
-	if e.TTL > 0 && time.Since(e.CreatedAt) > e.TTL {
+	// Отрицательный TTL — метка удаления (в интерфейсе Node нет Delete).
+	if e.TTL < 0 || e.TTL > 0 && time.Since(e.CreatedAt) > e.TTL {
 		return nil, false, nil
 	}
 	return e.Value, true, nil
@@ -127,6 +128,9 @@ func hashKey(key string) uint64 {
 func (r *ConsistentHashRing) Add(node Node) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if _, ok := r.nodes[node.ID()]; ok {
+		return fmt.Errorf("node %q already in ring", node.ID())
+	}
 	r.nodes[node.ID()] = node
 	for i := 0; i < r.vncount; i++ {
 		vk := fmt.Sprintf("%s#%d", node.ID(), i)
@@ -186,10 +190,15 @@ func (r *ConsistentHashRing) GetNodes(key string, count int) ([]Node, error) {
 type ReplicatedCache struct {
 	ring     ConsistentHash
 	replicas int
+
+	// keys — записанные ключи: узлы не умеют перечислять свои ключи,
+	// а InvalidatePattern нужно знать, что удалять.
+	mu   sync.Mutex
+	keys map[string]struct{}
 }
 
 func NewReplicatedCache(ring ConsistentHash, replicas int) *ReplicatedCache {
-	return &ReplicatedCache{ring: ring, replicas: replicas}
+	return &ReplicatedCache{ring: ring, replicas: replicas, keys: make(map[string]struct{})}
 }
 
 func (c *ReplicatedCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
@@ -215,6 +224,9 @@ func (c *ReplicatedCache) Set(ctx context.Context, key string, value interface{}
 			return err
 		}
 	}
+	c.mu.Lock()
+	c.keys[key] = struct{}{}
+	c.mu.Unlock()
 	return nil
 }
 
@@ -226,6 +238,9 @@ func (c *ReplicatedCache) Delete(ctx context.Context, key string) error {
 	for _, n := range nodes {
 		n.Set(ctx, key, nil, -1) // mark as deleted via nil
 	}
+	c.mu.Lock()
+	delete(c.keys, key)
+	c.mu.Unlock()
 	return nil
 }
 
@@ -234,7 +249,19 @@ func (c *ReplicatedCache) InvalidatePattern(ctx context.Context, pattern string)
 	if err != nil {
 		return err
 	}
-	_ = re
+	c.mu.Lock()
+	var matched []string
+	for k := range c.keys {
+		if re.MatchString(k) {
+			matched = append(matched, k)
+		}
+	}
+	c.mu.Unlock()
+	for _, k := range matched {
+		if err := c.Delete(ctx, k); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// switchableNode — узел, который можно "выключить".
+type switchableNode struct {
+	*localNode
+	down atomic.Bool
+}
+
+func (n *switchableNode) IsAlive() bool { return !n.down.Load() }
+
+func newRing(t *testing.T, ids ...string) (*ConsistentHashRing, map[string]*switchableNode) {
+	t.Helper()
+	ring := NewConsistentHashRing(100)
+	nodes := make(map[string]*switchableNode)
+	for _, id := range ids {
+		n := &switchableNode{localNode: newLocalNode(id)}
+		nodes[id] = n
+		if err := ring.Add(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ring, nodes
+}
+
+func TestConsistentHashRing(t *testing.T) {
+	var ring ConsistentHash = NewConsistentHashRing(100)
+	if _, err := ring.GetNode("k"); err == nil {
+		t.Fatal("GetNode on empty ring must fail")
+	}
+
+	ring, _ = newRing(t, "a", "b", "c")
+	if err := ring.Add(newLocalNode("a")); err == nil {
+		t.Fatal("adding the same node twice must fail")
+	}
+
+	counts := make(map[string]int)
+	owner := make(map[string]string)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		n, err := ring.GetNode(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[n.ID()]++
+		owner[key] = n.ID()
+
+		again, _ := ring.GetNode(key)
+		if again.ID() != n.ID() {
+			t.Fatalf("GetNode(%s) is not stable", key)
+		}
+	}
+	for id, c := range counts {
+		if c < 600 {
+			t.Fatalf("node %s owns only %d of 3000 keys: distribution is too uneven (%v)", id, c, counts)
+		}
+	}
+
+	// При удалении узла переезжают только его ключи.
+	ring.Remove("b")
+	for key, was := range owner {
+		n, _ := ring.GetNode(key)
+		if n.ID() == "b" {
+			t.Fatalf("key %s still maps to removed node", key)
+		}
+		if was != "b" && n.ID() != was {
+			t.Fatalf("key %s moved from %s to %s though its node was not removed", key, was, n.ID())
+		}
+	}
+}
+
+func TestGetNodesDistinct(t *testing.T) {
+	ring, _ := newRing(t, "a", "b", "c")
+	tests := []struct {
+		count int
+		want  int
+	}{
+		{count: 1, want: 1},
+		{count: 2, want: 2},
+		{count: 3, want: 3},
+		{count: 5, want: 3}, // узлов меньше, чем запрошено
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.count), func(t *testing.T) {
+			nodes, err := ring.GetNodes("key", tc.count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(nodes) != tc.want {
+				t.Fatalf("GetNodes(key, %d) returned %d nodes, want %d", tc.count, len(nodes), tc.want)
+			}
+			seen := make(map[string]bool)
+			for _, n := range nodes {
+				if seen[n.ID()] {
+					t.Fatalf("node %s returned twice", n.ID())
+				}
+				seen[n.ID()] = true
+			}
+		})
+	}
+}
+
+func TestReplicatedCache(t *testing.T) {
+	ctx := context.Background()
+	ring, nodes := newRing(t, "a", "b", "c")
+	var cache DistributedCache = NewReplicatedCache(ring, 2)
+
+	if _, ok, _ := cache.Get(ctx, "user:1"); ok {
+		t.Fatal("Get on empty cache must miss")
+	}
+	cache.Set(ctx, "user:1", "Alice", time.Minute)
+
+	replicas := 0
+	for _, n := range nodes {
+		if _, ok, _ := n.localNode.Get(ctx, "user:1"); ok {
+			replicas++
+		}
+	}
+	if replicas != 2 {
+		t.Fatalf("value stored on %d nodes, want 2 replicas", replicas)
+	}
+
+	primary, _ := ring.GetNode("user:1")
+	nodes[primary.ID()].down.Store(true)
+	if v, ok, _ := cache.Get(ctx, "user:1"); !ok || v != "Alice" {
+		t.Fatalf("Get with primary down = %v, %v; want value from replica", v, ok)
+	}
+	nodes[primary.ID()].down.Store(false)
+
+	cache.Delete(ctx, "user:1")
+	if _, ok, _ := cache.Get(ctx, "user:1"); ok {
+		t.Fatal("deleted key must miss")
+	}
+}
+
+func TestReplicatedCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	ring, _ := newRing(t, "a", "b")
+	cache := NewReplicatedCache(ring, 2)
+
+	cache.Set(ctx, "short", 1, 10*time.Millisecond)
+	cache.Set(ctx, "forever", 2, 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := cache.Get(ctx, "short"); ok {
+		t.Fatal("expired key must miss")
+	}
+	if _, ok, _ := cache.Get(ctx, "forever"); !ok {
+		t.Fatal("key without ttl must not expire")
+	}
+}
+
+func TestReplicatedCacheMultiAndPattern(t *testing.T) {
+	ctx := context.Background()
+	ring, _ := newRing(t, "a", "b", "c")
+	cache := NewReplicatedCache(ring, 2)
+
+	cache.SetMulti(ctx, map[string]interface{}{"user:1": 1, "user:2": 2, "order:1": 3}, time.Minute)
+	got, _ := cache.GetMulti(ctx, []string{"user:1", "user:2", "order:1", "missing"})
+	if len(got) != 3 || got["user:2"] != 2 {
+		t.Fatalf("GetMulti() = %v", got)
+	}
+
+	if err := cache.InvalidatePattern(ctx, "^user:"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = cache.GetMulti(ctx, []string{"user:1", "user:2", "order:1"})
+	if len(got) != 1 || got["order:1"] != 3 {
+		t.Fatalf("after InvalidatePattern(^user:) GetMulti() = %v, want only order:1", got)
+	}
+	if err := cache.InvalidatePattern(ctx, "("); err == nil {
+		t.Fatal("invalid pattern must return an error")
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	errLoad := errors.New("db is down")
+	caches := map[string]func() DistributedCache{
+		"replicated": func() DistributedCache {
+			ring, _ := newRing(t, "a", "b")
+			return NewReplicatedCache(ring, 1)
+		},
+		"single-flight": func() DistributedCache {
+			ring, _ := newRing(t, "a", "b")
+			return NewSingleFlightCache(NewReplicatedCache(ring, 1))
+		},
+	}
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			cache := newCache()
+			if _, err := cache.GetOrLoad(ctx, "k", func() (interface{}, error) { return nil, errLoad }); !errors.Is(err, errLoad) {
+				t.Fatalf("GetOrLoad() error = %v, want %v", err, errLoad)
+			}
+			loads := 0
+			for i := 0; i < 3; i++ {
+				v, err := cache.GetOrLoad(ctx, "k", func() (interface{}, error) { loads++; return "v", nil })
+				if err != nil || v != "v" {
+					t.Fatalf("GetOrLoad() = %v, %v", v, err)
+				}
+			}
+			if loads != 1 {
+				t.Fatalf("loader called %d times, want 1", loads)
+			}
+		})
+	}
+}
+
+func TestSingleFlightDeduplicatesConcurrentLoads(t *testing.T) {
+	ctx := context.Background()
+	ring, _ := newRing(t, "a")
+	cache := NewSingleFlightCache(NewReplicatedCache(ring, 1))
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.GetOrLoad(ctx, "hot", func() (interface{}, error) {
+				loads.Add(1)
+				<-release
+				return "value", nil
+			})
+			if err != nil || v != "value" {
+				t.Errorf("GetOrLoad() = %v, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loader called %d times for concurrent requests, want 1", n)
+	}
+}
@@ -115,6 +115,23 @@ func (l *InMemoryTransactionLog) GetTransactionState(_ context.Context, txID str
 	return tx, nil
 }
 
+// completedSteps — сколько первых шагов транзакции завершились успешно по журналу.
+func (l *InMemoryTransactionLog) completedSteps(tx *Transaction) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	done := make(map[string]bool)
+	for _, ev := range l.events[tx.ID] {
+		if ev.EventType == "completed" {
+			done[ev.StepName] = true
+		}
+	}
+	n := 0
+	for n < len(tx.Steps) && done[tx.Steps[n].Name] {
+		n++
+	}
+	return n
+}
+
 func (l *InMemoryTransactionLog) SaveTx(tx *Transaction) {
 	l.mu.Lock()
 	l.states[tx.ID] = tx
@@ -132,34 +149,32 @@ func NewSagaOrchestrator(log *InMemoryTransactionLog) *SagaOrchestratorImpl {
 }
 
 func (o *SagaOrchestratorImpl) Execute(ctx context.Context, tx *Transaction) error {
-	tx.Status = StatusInProgress
+	if tx.Data == nil {
+		tx.Data = make(map[string]interface{})
+	}
 	tx.StartedAt = time.Now()
-	o.log.SaveTx(tx)
+	return o.run(ctx, tx, 0)
+}
 
-	completed := -1
-	for i, step := range tx.Steps {
-		stepCtx := ctx
-		if step.Timeout > 0 {
-			var cancel context.CancelFunc
-			stepCtx, cancel = context.WithTimeout(ctx, step.Timeout)
-			defer cancel()
-		}
-		o.log.LogStepStarted(stepCtx, tx.ID, step.Name, tx.Data)
+// run выполняет шаги транзакции, начиная с from. При ошибке компенсирует
+// все шаги, выполненные до неё, в обратном порядке.
+func (o *SagaOrchestratorImpl) run(ctx context.Context, tx *Transaction, from int) error {
+	tx.Status = StatusInProgress
+	o.log.SaveTx(tx)
 
-		result, err := step.Action(stepCtx, tx.Data)
+	for i := from; i < len(tx.Steps); i++ {
+		step := tx.Steps[i]
+		result, err := o.runStep(ctx, tx, step)
 		if err != nil {
-			o.log.LogStepFailed(stepCtx, tx.ID, step.Name, err)
+			o.log.LogStepFailed(ctx, tx.ID, step.Name, err)
 			tx.Status = StatusFailed
 			tx.Error = err
 			o.log.SaveTx(tx)
-
-			// compensate completed steps in reverse
-			o.compensateUpTo(ctx, tx, completed)
+			o.compensateUpTo(ctx, tx, i-1)
 			return err
 		}
 		tx.Data[step.Name+"_result"] = result
-		o.log.LogStepCompleted(stepCtx, tx.ID, step.Name, result)
-		completed = i
+		o.log.LogStepCompleted(ctx, tx.ID, step.Name, result)
 	}
 
 	now := time.Now()
@@ -169,6 +184,16 @@ func (o *SagaOrchestratorImpl) Execute(ctx context.Context, tx *Transaction) err
 	return nil
 }
 
+func (o *SagaOrchestratorImpl) runStep(ctx context.Context, tx *Transaction, step Step) (interface{}, error) {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+	o.log.LogStepStarted(ctx, tx.ID, step.Name, tx.Data)
+	return step.Action(ctx, tx.Data)
+}
+
 func (o *SagaOrchestratorImpl) compensateUpTo(ctx context.Context, tx *Transaction, upTo int) {
 	tx.Status = StatusCompensating
 	o.log.SaveTx(tx)
@@ -192,21 +217,34 @@ func (o *SagaOrchestratorImpl) GetStatus(_ context.Context, txID string) (Transa
 	return tx.Status, nil
 }
 
+// Compensate откатывает шаги, успешно выполненные по журналу. Уже
+// компенсированную транзакцию повторно не трогает.
 func (o *SagaOrchestratorImpl) Compensate(ctx context.Context, txID string) error {
 	tx, err := o.log.GetTransactionState(ctx, txID)
 	if err != nil {
 		return err
 	}
-	o.compensateUpTo(ctx, tx, len(tx.Steps)-1)
+	if tx.Status == StatusCompensated {
+		return nil
+	}
+	o.compensateUpTo(ctx, tx, o.log.completedSteps(tx)-1)
 	return nil
 }
 
+// Resume продолжает прерванную транзакцию с первого невыполненного шага:
+// уже выполненные шаги повторно не запускаются.
 func (o *SagaOrchestratorImpl) Resume(ctx context.Context, txID string) error {
 	tx, err := o.log.GetTransactionState(ctx, txID)
 	if err != nil {
 		return err
 	}
-	return o.Execute(ctx, tx)
+	switch tx.Status {
+	case StatusCompleted:
+		return nil
+	case StatusCompensating, StatusCompensated:
+		return fmt.Errorf("transaction %q is compensated and cannot be resumed", txID)
+	}
+	return o.run(ctx, tx, o.log.completedSteps(tx))
 }
 
 func main() {
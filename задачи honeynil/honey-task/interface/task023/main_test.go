@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// trace записывает вызовы действий и компенсаций.
+type trace struct{ calls []string }
+
+func (tr *trace) step(name string, fail error) Step {
+	return Step{
+		Name: name,
+		Action: func(context.Context, interface{}) (interface{}, error) {
+			tr.calls = append(tr.calls, name)
+			if fail != nil {
+				return nil, fail
+			}
+			return name + "-ok", nil
+		},
+		Compensate: func(context.Context, interface{}) error {
+			tr.calls = append(tr.calls, "undo-"+name)
+			return nil
+		},
+	}
+}
+
+func TestSagaExecute(t *testing.T) {
+	errStep := errors.New("step failed")
+	tests := []struct {
+		name       string
+		failAt     int // индекс падающего шага, -1 — без ошибок
+		wantCalls  []string
+		wantStatus TransactionStatus
+	}{
+		{
+			name:       "all steps succeed",
+			failAt:     -1,
+			wantCalls:  []string{"a", "b", "c"},
+			wantStatus: StatusCompleted,
+		},
+		{
+			name:       "first step fails",
+			failAt:     0,
+			wantCalls:  []string{"a"},
+			wantStatus: StatusCompensated,
+		},
+		{
+			name:       "last step fails",
+			failAt:     2,
+			wantCalls:  []string{"a", "b", "c", "undo-b", "undo-a"},
+			wantStatus: StatusCompensated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &trace{}
+			var steps []Step
+			for i, name := range []string{"a", "b", "c"} {
+				var fail error
+				if i == tc.failAt {
+					fail = errStep
+				}
+				steps = append(steps, tr.step(name, fail))
+			}
+			var orch SagaOrchestrator = NewSagaOrchestrator(NewInMemoryTransactionLog())
+			tx := &Transaction{ID: "tx", Steps: steps}
+
+			err := orch.Execute(context.Background(), tx)
+			if tc.failAt >= 0 && !errors.Is(err, errStep) {
+				t.Fatalf("Execute() error = %v, want %v", err, errStep)
+			}
+			if tc.failAt < 0 && err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(tr.calls, tc.wantCalls) {
+				t.Fatalf("calls = %v, want %v", tr.calls, tc.wantCalls)
+			}
+			if status, _ := orch.GetStatus(context.Background(), "tx"); status != tc.wantStatus {
+				t.Fatalf("GetStatus() = %s, want %s", status, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSagaStepResults(t *testing.T) {
+	tr := &trace{}
+	orch := NewSagaOrchestrator(NewInMemoryTransactionLog())
+	tx := &Transaction{ID: "tx", Steps: []Step{tr.step("reserve", nil)}}
+
+	if err := orch.Execute(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Data["reserve_result"] != "reserve-ok" {
+		t.Fatalf("Data = %v, want reserve_result", tx.Data)
+	}
+	if tx.CompletedAt == nil {
+		t.Fatal("CompletedAt is not set")
+	}
+}
+
+func TestSagaStepTimeout(t *testing.T) {
+	orch := NewSagaOrchestrator(NewInMemoryTransactionLog())
+	tx := &Transaction{ID: "tx", Steps: []Step{{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Action: func(ctx context.Context, _ interface{}) (interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return "late", nil
+			}
+		},
+	}}}
+
+	if err := orch.Execute(context.Background(), tx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Execute() error = %v, want step timeout", err)
+	}
+}
+
+func TestSagaCompensate(t *testing.T) {
+	ctx := context.Background()
+	tr := &trace{}
+	orch := NewSagaOrchestrator(NewInMemoryTransactionLog())
+	tx := &Transaction{ID: "tx", Steps: []Step{tr.step("a", nil), tr.step("b", nil)}}
+	orch.Execute(ctx, tx)
+
+	if err := orch.Compensate(ctx, "tx"); err != nil {
+		t.Fatal(err)
+	}
+	// Повторная компенсация ничего не делает.
+	orch.Compensate(ctx, "tx")
+
+	want := []string{"a", "b", "undo-b", "undo-a"}
+	if !slices.Equal(tr.calls, want) {
+		t.Fatalf("calls = %v, want %v", tr.calls, want)
+	}
+	if err := orch.Compensate(ctx, "missing"); err == nil {
+		t.Fatal("Compensate of unknown transaction must fail")
+	}
+}
+
+func TestSagaResume(t *testing.T) {
+	ctx := context.Background()
+	log := NewInMemoryTransactionLog()
+	orch := NewSagaOrchestrator(log)
+	tr := &trace{}
+	tx := &Transaction{ID: "tx", Data: map[string]interface{}{}, Steps: []Step{tr.step("a", nil), tr.step("b", nil), tr.step("c", nil)}}
+
+	// Имитируем сбой процесса после первого шага: шаг записан в журнал,
+	// транзакция осталась in_progress.
+	tx.Status = StatusInProgress
+	log.SaveTx(tx)
+	log.LogStepStarted(ctx, "tx", "a", nil)
+	log.LogStepCompleted(ctx, "tx", "a", "a-ok")
+
+	if err := orch.Resume(ctx, "tx"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b", "c"}; !slices.Equal(tr.calls, want) {
+		t.Fatalf("Resume ran %v, want only unfinished steps %v", tr.calls, want)
+	}
+	if status, _ := orch.GetStatus(ctx, "tx"); status != StatusCompleted {
+		t.Fatalf("GetStatus() = %s, want completed", status)
+	}
+
+	if err := orch.Resume(ctx, "tx"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.calls) != 2 {
+		t.Fatalf("Resume of completed transaction ran steps again: %v", tr.calls)
+	}
+}
@@ -199,6 +199,7 @@ type StreamingPipeline struct {
 	watermark   Watermark
 	maxQueue    int
 	checkpoints []pipelineCheckpoint
+	offsets     map[string]int64 // закоммиченные смещения источников
 	cancel      context.CancelFunc
 	wg          sync.WaitGroup
 }
@@ -207,6 +208,7 @@ func NewStreamingPipeline(maxQueue int) *StreamingPipeline {
 	return &StreamingPipeline{
 		sources:    make(map[string]StreamSource),
 		sinks:      make(map[string]StreamSink),
+		offsets:    make(map[string]int64),
 		bp:         &DropBackpressure{},
 		watermark:  NewWatermark(5 * time.Second),
 		maxQueue:   maxQueue,
@@ -258,6 +260,7 @@ func (p *StreamingPipeline) process(ctx context.Context, msg Message) []Message
 	return msgs
 }
 
+// flushWindows вызывается под p.mu: окна общие для всех источников.
 func (p *StreamingPipeline) flushWindows(ctx context.Context) {
 	now := time.Now()
 	for _, w := range p.windows {
@@ -265,6 +268,10 @@ func (p *StreamingPipeline) flushWindows(ctx context.Context) {
 			continue
 		}
 		win := Window{Start: w.lastFlush, End: now, Type: w.windowType}
+		if len(w.buf) == 0 {
+			w.lastFlush = now // пустые окна не агрегируем
+			continue
+		}
 		result, err := w.agg.Aggregate(ctx, win, w.buf)
 		if err == nil && result != nil {
 			outMsg := Message{Key: w.name, Value: result, Timestamp: now}
@@ -284,17 +291,27 @@ func (p *StreamingPipeline) Start(ctx context.Context) error {
 		p.wg.Add(1)
 		go func() {
 			defer p.wg.Done()
-			offset := int64(0)
 			for {
 				if ctx.Err() != nil {
 					return
 				}
+				p.mu.Lock()
+				offset := p.offsets[name]
+				p.mu.Unlock()
+
 				msgs, err := src.Read(ctx, 100)
 				if err != nil || len(msgs) == 0 {
-					time.Sleep(50 * time.Millisecond)
+					// Окно должно закрыться по времени, даже если новых сообщений нет.
+					p.mu.Lock()
+					p.flushWindows(ctx)
+					p.mu.Unlock()
+					select {
+					case <-ctx.Done():
+					case <-time.After(50 * time.Millisecond):
+					}
 					continue
 				}
-				_ = name
+				var accepted []Message
 				for _, msg := range msgs {
 					if p.watermark.IsLate(msg.Timestamp) {
 						continue
@@ -304,13 +321,22 @@ func (p *StreamingPipeline) Start(ctx context.Context) error {
 					for _, sink := range p.sinks {
 						sink.Write(ctx, processed)
 					}
-					for _, w := range p.windows {
-						w.buf = append(w.buf, msg)
-					}
-					offset++
+					accepted = append(accepted, msg)
+				}
+
+				p.mu.Lock()
+				for _, w := range p.windows {
+					w.buf = append(w.buf, accepted...)
+				}
+				// Restore мог перемотать источник, пока батч обрабатывался, —
+				// тогда не затираем восстановленное смещение.
+				if p.offsets[name] == offset {
+					offset += int64(len(msgs))
+					p.offsets[name] = offset
+					src.Commit(ctx, offset)
 				}
-				src.Commit(ctx, offset)
 				p.flushWindows(ctx)
+				p.mu.Unlock()
 			}
 		}()
 	}
@@ -328,14 +354,19 @@ func (p *StreamingPipeline) Stop(_ context.Context) error {
 	return nil
 }
 
+// Checkpoint запоминает закоммиченные смещения всех источников.
+// Идентификаторы чекпоинтов — cp-1, cp-2, … в порядке создания.
 func (p *StreamingPipeline) Checkpoint(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	cp := pipelineCheckpoint{
-		id:      fmt.Sprintf("cp-%d", time.Now().UnixNano()),
-		offsets: make(map[string]int64),
+		id:      fmt.Sprintf("cp-%d", len(p.checkpoints)+1),
+		offsets: make(map[string]int64, len(p.sources)),
+	}
+	for name := range p.sources {
+		cp.offsets[name] = p.offsets[name]
 	}
-	p.mu.Lock()
 	p.checkpoints = append(p.checkpoints, cp)
-	p.mu.Unlock()
 	return nil
 }
 
@@ -346,7 +377,10 @@ func (p *StreamingPipeline) Restore(_ context.Context, checkpointID string) erro
 		if cp.id == checkpointID {
 			for name, offset := range cp.offsets {
 				if src, ok := p.sources[name]; ok {
-					src.Seek(context.Background(), offset)
+					if err := src.Seek(context.Background(), offset); err != nil {
+						return err
+					}
+					p.offsets[name] = offset
 				}
 			}
 			return nil
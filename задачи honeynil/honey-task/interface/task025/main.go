@@ -139,9 +139,15 @@ func (db *InMemoryGraphDB) UpdateVertex(_ context.Context, id string, props map[
 	if !ok {
 		return fmt.Errorf("vertex %q not found", id)
 	}
+	// Новая map: старая может быть общей с копиями, выданными GetVertex.
+	merged := make(map[string]interface{}, len(v.Properties)+len(props))
+	for k, val := range v.Properties {
+		merged[k] = val
+	}
 	for k, val := range props {
-		v.Properties[k] = val
+		merged[k] = val
 	}
+	v.Properties = merged
 	v.UpdatedAt = time.Now()
 	db.state.vertices[id] = v
 	return nil
@@ -336,6 +342,9 @@ func (h *dijkstraHeap) Push(x interface{}) { item := x.(*dijkstraItem); item.idx
 func (h *dijkstraHeap) Pop() interface{}   { old := *h; n := len(old); x := old[n-1]; *h = old[:n-1]; return x }
 
 func (g *GraphAlgorithmsImpl) ShortestPath(ctx context.Context, fromID, toID string) (*Path, error) {
+	if _, err := g.db.GetVertex(ctx, fromID); err != nil {
+		return nil, err
+	}
 	dist := map[string]float64{fromID: 0}
 	prev := map[string]string{}
 	prevEdge := map[string]string{}
@@ -373,10 +382,16 @@ func (g *GraphAlgorithmsImpl) ShortestPath(ctx context.Context, fromID, toID str
 		cur = prev[cur]
 	}
 	path := &Path{Cost: dist[toID], Length: len(vids) - 1}
-	for _, vid := range vids {
+	for i, vid := range vids {
 		if v, err := g.db.GetVertex(ctx, vid); err == nil {
 			path.Vertices = append(path.Vertices, *v)
 		}
+		if i == 0 {
+			continue
+		}
+		if e, err := g.db.GetEdge(ctx, prevEdge[vid]); err == nil {
+			path.Edges = append(path.Edges, *e)
+		}
 	}
 	return path, nil
 }
@@ -441,14 +456,24 @@ func (g *GraphAlgorithmsImpl) DetectCycle(ctx context.Context) (bool, []string,
 	g.db.mu.RUnlock()
 
 	color := make(map[string]int) // 0=white, 1=gray, 2=black
-	var cycle []string
+	var (
+		cycle []string
+		stack []string // текущий путь обхода: серые вершины по порядку
+	)
 	var dfs func(id string) bool
 	dfs = func(id string) bool {
 		color[id] = 1
+		stack = append(stack, id)
 		edges, _ := g.db.GetEdges(ctx, id, DirectionOut)
 		for _, e := range edges {
 			if color[e.ToVertex] == 1 {
-				cycle = []string{e.ToVertex, id}
+				// Цикл — часть пути от вершины, в которую ведёт обратное ребро.
+				for i, v := range stack {
+					if v == e.ToVertex {
+						cycle = append([]string{}, stack[i:]...)
+						break
+					}
+				}
 				return true
 			}
 			if color[e.ToVertex] == 0 && dfs(e.ToVertex) {
@@ -456,6 +481,7 @@ func (g *GraphAlgorithmsImpl) DetectCycle(ctx context.Context) (bool, []string,
 			}
 		}
 		color[id] = 2
+		stack = stack[:len(stack)-1]
 		return false
 	}
 	for _, id := range vids {
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// buildGraph: A -1-> B -2-> C -1-> D, A -5-> D, E отдельно.
+func buildGraph(t *testing.T) (*InMemoryGraphDB, *GraphAlgorithmsImpl) {
+	t.Helper()
+	ctx := context.Background()
+	db := NewInMemoryGraphDB()
+	for _, id := range []string{"A", "B", "C", "D", "E"} {
+		db.AddVertex(ctx, Vertex{ID: id, Properties: map[string]interface{}{"name": id}})
+	}
+	for _, e := range []Edge{
+		{ID: "ab", FromVertex: "A", ToVertex: "B", Weight: 1},
+		{ID: "bc", FromVertex: "B", ToVertex: "C", Weight: 2},
+		{ID: "cd", FromVertex: "C", ToVertex: "D", Weight: 1},
+		{ID: "ad", FromVertex: "A", ToVertex: "D", Weight: 5},
+	} {
+		db.AddEdge(ctx, e)
+	}
+	return db, &GraphAlgorithmsImpl{db: db}
+}
+
+func ids(vs []Vertex) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.ID
+	}
+	return out
+}
+
+func TestGraphCRUD(t *testing.T) {
+	ctx := context.Background()
+	var db GraphDB = NewInMemoryGraphDB()
+
+	db.AddVertex(ctx, Vertex{ID: "v"})
+	if err := db.UpdateVertex(ctx, "v", map[string]interface{}{"age": 30}); err != nil {
+		t.Fatal(err)
+	}
+	v, err := db.GetVertex(ctx, "v")
+	if err != nil || v.Properties["age"] != 30 || v.UpdatedAt.IsZero() {
+		t.Fatalf("GetVertex() = %+v, %v", v, err)
+	}
+	if err := db.UpdateVertex(ctx, "missing", nil); err == nil {
+		t.Fatal("UpdateVertex of missing vertex must fail")
+	}
+
+	db.AddVertex(ctx, Vertex{ID: "w"})
+	db.AddEdge(ctx, Edge{ID: "vw", FromVertex: "v", ToVertex: "w"})
+	if e, err := db.GetEdge(ctx, "vw"); err != nil || e.ToVertex != "w" {
+		t.Fatalf("GetEdge() = %+v, %v", e, err)
+	}
+
+	db.DeleteVertex(ctx, "w")
+	if _, err := db.GetVertex(ctx, "w"); err == nil {
+		t.Fatal("deleted vertex must not be found")
+	}
+	if _, err := db.GetEdge(ctx, "vw"); err == nil {
+		t.Fatal("DeleteVertex must remove incident edges")
+	}
+	if edges, _ := db.GetEdges(ctx, "v", DirectionBoth); len(edges) != 0 {
+		t.Fatalf("GetEdges(v) = %v after neighbour was deleted", edges)
+	}
+}
+
+func TestGetVertexReturnsCopy(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryGraphDB()
+	db.AddVertex(ctx, Vertex{ID: "v", Properties: map[string]interface{}{"a": 1}})
+
+	before, _ := db.GetVertex(ctx, "v")
+	db.UpdateVertex(ctx, "v", map[string]interface{}{"a": 2})
+	if before.Properties["a"] != 1 {
+		t.Fatal("UpdateVertex changed a vertex previously returned by GetVertex")
+	}
+}
+
+func TestGetNeighbors(t *testing.T) {
+	db, _ := buildGraph(t)
+	tests := []struct {
+		vertex string
+		dir    Direction
+		want   []string
+	}{
+		{vertex: "A", dir: DirectionOut, want: []string{"B", "D"}},
+		{vertex: "A", dir: DirectionIn, want: []string{}},
+		{vertex: "D", dir: DirectionIn, want: []string{"A", "C"}},
+		{vertex: "B", dir: DirectionBoth, want: []string{"A", "C"}},
+		{vertex: "E", dir: DirectionBoth, want: []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.vertex+"/"+string(tc.dir), func(t *testing.T) {
+			vs, err := db.GetNeighbors(context.Background(), tc.vertex, tc.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(vs)
+			sort.Strings(got)
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("GetNeighbors() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryGraphDB()
+
+	tx, _ := db.BeginTx(ctx, TxOptions{})
+	tx.AddVertex(Vertex{ID: "a"})
+	tx.AddVertex(Vertex{ID: "b"})
+	tx.AddEdge(Edge{ID: "ab", FromVertex: "a", ToVertex: "b"})
+	if _, err := db.GetVertex(ctx, "a"); err == nil {
+		t.Fatal("uncommitted changes must not be visible")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetEdge(ctx, "ab"); err != nil {
+		t.Fatal("committed edge not found")
+	}
+
+	tx, _ = db.BeginTx(ctx, TxOptions{})
+	tx.DeleteVertex("a")
+	tx.Rollback()
+	tx.Commit()
+	if _, err := db.GetVertex(ctx, "a"); err != nil {
+		t.Fatal("rolled back delete was applied")
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	_, algo := buildGraph(t)
+	ctx := context.Background()
+
+	path, err := algo.ShortestPath(ctx, "A", "D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path.Cost != 4 || path.Length != 3 {
+		t.Fatalf("path cost=%v length=%d, want cost 4 via B and C", path.Cost, path.Length)
+	}
+	if got := ids(path.Vertices); !slices.Equal(got, []string{"A", "B", "C", "D"}) {
+		t.Fatalf("path vertices = %v", got)
+	}
+	var edgeIDs []string
+	for _, e := range path.Edges {
+		edgeIDs = append(edgeIDs, e.ID)
+	}
+	if !slices.Equal(edgeIDs, []string{"ab", "bc", "cd"}) {
+		t.Fatalf("path edges = %v, want [ab bc cd]", edgeIDs)
+	}
+
+	if _, err := algo.ShortestPath(ctx, "D", "A"); err == nil {
+		t.Fatal("ShortestPath against edge direction must fail")
+	}
+	if _, err := algo.ShortestPath(ctx, "A", "E"); err == nil {
+		t.Fatal("ShortestPath to unreachable vertex must fail")
+	}
+	if _, err := algo.ShortestPath(ctx, "missing", "A"); err == nil {
+		t.Fatal("ShortestPath from unknown vertex must fail")
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	_, algo := buildGraph(t)
+	ctx := context.Background()
+
+	var bfs []string
+	algo.BFS(ctx, "A", func(v Vertex) bool { bfs = append(bfs, v.ID); return true })
+	if len(bfs) != 4 || bfs[0] != "A" || bfs[3] != "C" {
+		t.Fatalf("BFS = %v, want A, then B and D, then C", bfs)
+	}
+
+	var dfs []string
+	algo.DFS(ctx, "A", func(v Vertex) bool { dfs = append(dfs, v.ID); return true })
+	if len(dfs) != 4 || dfs[0] != "A" {
+		t.Fatalf("DFS = %v, want 4 vertices starting from A", dfs)
+	}
+
+	var stopped []string
+	algo.BFS(ctx, "A", func(v Vertex) bool { stopped = append(stopped, v.ID); return len(stopped) < 2 })
+	if len(stopped) != 2 {
+		t.Fatalf("BFS visited %v after visitor returned false", stopped)
+	}
+}
+
+func TestDetectCycle(t *testing.T) {
+	ctx := context.Background()
+	db, algo := buildGraph(t)
+
+	if has, _, _ := algo.DetectCycle(ctx); has {
+		t.Fatal("DAG must have no cycle")
+	}
+
+	db.AddEdge(ctx, Edge{ID: "db", FromVertex: "D", ToVertex: "B"})
+	has, cycle, _ := algo.DetectCycle(ctx)
+	if !has {
+		t.Fatal("cycle B -> C -> D -> B not detected")
+	}
+	got := slices.Clone(cycle)
+	sort.Strings(got)
+	if !slices.Equal(got, []string{"B", "C", "D"}) {
+		t.Fatalf("cycle = %v, want all of B, C, D", cycle)
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	_, algo := buildGraph(t)
+	comps, _ := algo.ConnectedComponents(context.Background())
+
+	var sizes []int
+	for _, c := range comps {
+		sizes = append(sizes, len(c))
+	}
+	sort.Ints(sizes)
+	if !slices.Equal(sizes, []int{1, 4}) {
+		t.Fatalf("component sizes = %v, want [1 4]", sizes)
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	_, algo := buildGraph(t)
+	ranks, _ := algo.PageRank(context.Background(), 30, 0.85)
+
+	if len(ranks) != 5 {
+		t.Fatalf("PageRank returned %d ranks, want 5", len(ranks))
+	}
+	if ranks["D"] <= ranks["A"] || ranks["D"] <= ranks["E"] {
+		t.Fatalf("D has most incoming links but rank %v (A=%v, E=%v)", ranks["D"], ranks["A"], ranks["E"])
+	}
+	for id, r := range ranks {
+		if r <= 0 || math.IsNaN(r) {
+			t.Fatalf("rank of %s = %v", id, r)
+		}
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	_, algo := buildGraph(t)
+	ctx := context.Background()
+
+	paths, _ := algo.AllPaths(ctx, "A", "D", 5)
+	if len(paths) != 2 {
+		t.Fatalf("AllPaths(A, D) found %d paths, want 2", len(paths))
+	}
+	paths, _ = algo.AllPaths(ctx, "A", "D", 1)
+	if len(paths) != 1 || paths[0].Length != 1 {
+		t.Fatalf("AllPaths with maxDepth 1 = %v, want only direct edge", paths)
+	}
+}
+
+func TestHashIndex(t *testing.T) {
+	idx := NewHashIndex()
+	idx.Add("alice", "v1")
+	idx.Add("alice", "v2")
+	idx.Add("bob", "v3")
+	idx.Add("carol", "v4")
+
+	if got, _ := idx.Search("alice"); !slices.Equal(got, []string{"v1", "v2"}) {
+		t.Fatalf("Search(alice) = %v", got)
+	}
+	idx.Remove("alice", "v1")
+	if got, _ := idx.Search("alice"); !slices.Equal(got, []string{"v2"}) {
+		t.Fatalf("after Remove Search(alice) = %v", got)
+	}
+	got, _ := idx.RangeSearch("b", "c")
+	if !slices.Equal(got, []string{"v3"}) {
+		t.Fatalf("RangeSearch(b, c) = %v, want [v3]", got)
+	}
+}
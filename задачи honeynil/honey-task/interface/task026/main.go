@@ -125,27 +125,47 @@ func NewTokenBucketLimiter() *TokenBucketLimiter {
 	}
 }
 
-func (l *TokenBucketLimiter) keyFor(req Request) string {
-	return fmt.Sprintf("%s:%s:%s", req.UserID, req.IP, req.Resource)
+func quotaID(level QuotaLevel, id string) string {
+	return fmt.Sprintf("%s:%s", level, id)
 }
 
-func (l *TokenBucketLimiter) bucketFor(key string) *tokenBucket {
+// bucketFor выбирает бакет запроса: самая специфичная из настроенных квот
+// (per_key, per_user, per_ip, затем global "default") общая для всех её
+// запросов; без квоты — лимит по умолчанию на пару пользователь/IP/ресурс.
+func (l *TokenBucketLimiter) bucketFor(req Request) *tokenBucket {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	key := fmt.Sprintf("%s:%s:%s", req.UserID, req.IP, req.Resource)
+	limit := RateLimit{Rate: 100, Period: time.Second, Burst: 200}
+	candidates := []struct {
+		level QuotaLevel
+		id    string
+	}{
+		{QuotaPerKey, req.APIKey},
+		{QuotaPerUser, req.UserID},
+		{QuotaPerIP, req.IP},
+		{QuotaGlobal, "default"},
+	}
+	for _, c := range candidates {
+		if c.id == "" {
+			continue
+		}
+		if lim, ok := l.limits[quotaID(c.level, c.id)]; ok {
+			key, limit = quotaID(c.level, c.id), lim
+			break
+		}
+	}
+
 	if b, ok := l.buckets[key]; ok {
 		return b
 	}
-	limit, ok := l.limits[key]
-	if !ok {
-		limit = RateLimit{Rate: 100, Period: time.Second, Burst: 200}
-	}
 	b := newTokenBucket(limit)
 	l.buckets[key] = b
 	return b
 }
 
 func (l *TokenBucketLimiter) SetLimit(_ context.Context, level QuotaLevel, id string, limit RateLimit) error {
-	key := fmt.Sprintf("%s:%s", level, id)
+	key := quotaID(level, id)
 	l.mu.Lock()
 	l.limits[key] = limit
 	delete(l.buckets, key) // reset bucket
@@ -154,8 +174,7 @@ func (l *TokenBucketLimiter) SetLimit(_ context.Context, level QuotaLevel, id st
 }
 
 func (l *TokenBucketLimiter) Allow(_ context.Context, req Request) (RateLimitResult, error) {
-	key := l.keyFor(req)
-	b := l.bucketFor(key)
+	b := l.bucketFor(req)
 	allowed, remaining, retryAfter := b.take(1)
 	return RateLimitResult{
 		Allowed:    allowed,
@@ -166,8 +185,7 @@ func (l *TokenBucketLimiter) Allow(_ context.Context, req Request) (RateLimitRes
 }
 
 func (l *TokenBucketLimiter) Reserve(_ context.Context, req Request, count int) (RateLimitResult, error) {
-	key := l.keyFor(req)
-	b := l.bucketFor(key)
+	b := l.bucketFor(req)
 	allowed, remaining, retryAfter := b.take(float64(count))
 	return RateLimitResult{
 		Allowed:    allowed,
@@ -193,10 +211,10 @@ func (l *TokenBucketLimiter) Wait(ctx context.Context, req Request) error {
 	}
 }
 
-func (l *TokenBucketLimiter) GetUsage(_ context.Context, _ QuotaLevel, identifier string) (int64, error) {
+func (l *TokenBucketLimiter) GetUsage(_ context.Context, level QuotaLevel, identifier string) (int64, error) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	if b, ok := l.buckets[identifier]; ok {
+	if b, ok := l.buckets[quotaID(level, identifier)]; ok {
 		b.mu.Lock()
 		used := int64(b.maxTokens - b.tokens)
 		b.mu.Unlock()
@@ -205,10 +223,10 @@ func (l *TokenBucketLimiter) GetUsage(_ context.Context, _ QuotaLevel, identifie
 	return 0, nil
 }
 
-func (l *TokenBucketLimiter) ResetQuota(_ context.Context, _ QuotaLevel, identifier string) error {
+func (l *TokenBucketLimiter) ResetQuota(_ context.Context, level QuotaLevel, identifier string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	delete(l.buckets, identifier)
+	delete(l.buckets, quotaID(level, identifier))
 	return nil
 }
 
@@ -231,7 +249,11 @@ func NewSlidingWindowLimiter() *SlidingWindowLimiter {
 	}
 }
 
-func (l *SlidingWindowLimiter) Allow(_ context.Context, req Request) (RateLimitResult, error) {
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, req Request) (RateLimitResult, error) {
+	return l.Reserve(ctx, req, 1)
+}
+
+func (l *SlidingWindowLimiter) Reserve(_ context.Context, req Request, count int) (RateLimitResult, error) {
 	key := fmt.Sprintf("%s:%s", req.UserID, req.Resource)
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -254,8 +276,13 @@ func (l *SlidingWindowLimiter) Allow(_ context.Context, req Request) (RateLimitR
 	}
 	entries = entries[:n]
 
-	if len(entries) >= limit.Rate {
-		resetAt := entries[0].timestamp.Add(limit.Period)
+	l.windows[key] = entries
+	if len(entries)+count > limit.Rate {
+		resetAt := now.Add(limit.Period)
+		if free := limit.Rate - count; free >= 0 && free < len(entries) {
+			// Место освободится, когда из окна выйдет запись, мешающая резерву.
+			resetAt = entries[len(entries)-1-free].timestamp.Add(limit.Period)
+		}
 		return RateLimitResult{
 			Allowed:    false,
 			Remaining:  0,
@@ -264,7 +291,9 @@ func (l *SlidingWindowLimiter) Allow(_ context.Context, req Request) (RateLimitR
 			QuotaLimit: int64(limit.Rate),
 		}, nil
 	}
-	entries = append(entries, windowEntry{timestamp: now})
+	for i := 0; i < count; i++ {
+		entries = append(entries, windowEntry{timestamp: now})
+	}
 	l.windows[key] = entries
 	return RateLimitResult{
 		Allowed:      true,
@@ -274,9 +303,6 @@ func (l *SlidingWindowLimiter) Allow(_ context.Context, req Request) (RateLimitR
 	}, nil
 }
 
-func (l *SlidingWindowLimiter) Reserve(ctx context.Context, req Request, count int) (RateLimitResult, error) {
-	return l.Allow(ctx, req)
-}
 func (l *SlidingWindowLimiter) Wait(ctx context.Context, req Request) error {
 	for {
 		result, err := l.Allow(ctx, req)
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstThenDeny(t *testing.T) {
+	ctx := context.Background()
+	l := NewTokenBucketLimiter()
+	if err := l.SetLimit(ctx, QuotaPerUser, "u1", RateLimit{Rate: 1, Period: time.Hour, Burst: 3}); err != nil {
+		t.Fatalf("SetLimit: %v", err)
+	}
+	req := Request{UserID: "u1", IP: "1.1.1.1", Resource: "/a"}
+
+	for i := 0; i < 3; i++ {
+		res, err := l.Allow(ctx, req)
+		if err != nil || !res.Allowed {
+			t.Fatalf("request %d: allowed=%v err=%v, want allowed within burst", i+1, res.Allowed, err)
+		}
+		if res.Remaining != 2-i {
+			t.Fatalf("request %d: remaining = %d, want %d", i+1, res.Remaining, 2-i)
+		}
+	}
+	res, _ := l.Allow(ctx, req)
+	if res.Allowed {
+		t.Fatal("request over burst allowed")
+	}
+	if res.RetryAfter <= 0 {
+		t.Fatalf("RetryAfter = %v, want > 0", res.RetryAfter)
+	}
+}
+
+func TestTokenBucketQuotaSharedAcrossResources(t *testing.T) {
+	ctx := context.Background()
+	l := NewTokenBucketLimiter()
+	l.SetLimit(ctx, QuotaPerUser, "u1", RateLimit{Rate: 2, Period: time.Hour})
+
+	// Квота пользователя общая для всех его ресурсов и IP.
+	reqs := []Request{
+		{UserID: "u1", IP: "1.1.1.1", Resource: "/a"},
+		{UserID: "u1", IP: "2.2.2.2", Resource: "/b"},
+		{UserID: "u1", IP: "3.3.3.3", Resource: "/c"},
+	}
+	var allowed int
+	for _, req := range reqs {
+		if res, _ := l.Allow(ctx, req); res.Allowed {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Fatalf("allowed %d requests, want 2", allowed)
+	}
+
+	// Другой пользователь без квоты получает лимит по умолчанию.
+	if res, _ := l.Allow(ctx, Request{UserID: "u2", Resource: "/a"}); !res.Allowed {
+		t.Fatal("request of user without quota denied")
+	}
+}
+
+func TestTokenBucketQuotaPrecedence(t *testing.T) {
+	ctx := context.Background()
+	l := NewTokenBucketLimiter()
+	l.SetLimit(ctx, QuotaGlobal, "default", RateLimit{Rate: 1, Period: time.Hour})
+	l.SetLimit(ctx, QuotaPerKey, "k1", RateLimit{Rate: 5, Period: time.Hour})
+
+	req := Request{UserID: "u1", APIKey: "k1"}
+	for i := 0; i < 5; i++ {
+		if res, _ := l.Allow(ctx, req); !res.Allowed {
+			t.Fatalf("request %d with api key denied: per_key quota must win over global", i+1)
+		}
+	}
+
+	other := Request{UserID: "u2"}
+	if res, _ := l.Allow(ctx, other); !res.Allowed {
+		t.Fatal("first request under global quota denied")
+	}
+	if res, _ := l.Allow(ctx, other); res.Allowed {
+		t.Fatal("second request under global quota allowed")
+	}
+}
+
+func TestTokenBucketUsageAndReset(t *testing.T) {
+	ctx := context.Background()
+	l := NewTokenBucketLimiter()
+	l.SetLimit(ctx, QuotaPerIP, "9.9.9.9", RateLimit{Rate: 1, Period: time.Hour, Burst: 4})
+	req := Request{IP: "9.9.9.9"}
+
+	if res, _ := l.Reserve(ctx, req, 3); !res.Allowed {
+		t.Fatal("Reserve(3) within burst denied")
+	}
+	used, err := l.GetUsage(ctx, QuotaPerIP, "9.9.9.9")
+	if err != nil || used != 3 {
+		t.Fatalf("GetUsage = %d, %v, want 3", used, err)
+	}
+	if res, _ := l.Reserve(ctx, req, 2); res.Allowed {
+		t.Fatal("Reserve(2) with 1 token left allowed")
+	}
+
+	if err := l.ResetQuota(ctx, QuotaPerIP, "9.9.9.9"); err != nil {
+		t.Fatalf("ResetQuota: %v", err)
+	}
+	if used, _ := l.GetUsage(ctx, QuotaPerIP, "9.9.9.9"); used != 0 {
+		t.Fatalf("GetUsage after reset = %d, want 0", used)
+	}
+	if res, _ := l.Reserve(ctx, req, 4); !res.Allowed {
+		t.Fatal("Reserve(4) after reset denied")
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	req := Request{UserID: "u1", Resource: "/a"}
+
+	tests := []struct {
+		name     string
+		reserves []int
+		want     []bool
+	}{
+		{name: "allow up to rate", reserves: []int{1, 1, 1, 1}, want: []bool{true, true, true, false}},
+		{name: "reserve counts every request", reserves: []int{2, 2, 1}, want: []bool{true, false, true}},
+		{name: "reserve over rate", reserves: []int{4, 3}, want: []bool{false, true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewSlidingWindowLimiter()
+			l.limits["u1:/a"] = RateLimit{Rate: 3, Period: time.Hour}
+			for i, n := range tc.reserves {
+				res, err := l.Reserve(ctx, req, n)
+				if err != nil {
+					t.Fatalf("Reserve(%d): %v", n, err)
+				}
+				if res.Allowed != tc.want[i] {
+					t.Fatalf("Reserve #%d (%d) allowed=%v, want %v", i+1, n, res.Allowed, tc.want[i])
+				}
+				if !res.Allowed && res.RetryAfter <= 0 {
+					t.Fatalf("Reserve #%d: RetryAfter = %v, want > 0", i+1, res.RetryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestSlidingWindowExpires(t *testing.T) {
+	ctx := context.Background()
+	l := NewSlidingWindowLimiter()
+	l.limits["u1:/a"] = RateLimit{Rate: 2, Period: 50 * time.Millisecond}
+	req := Request{UserID: "u1", Resource: "/a"}
+
+	l.Allow(ctx, req)
+	l.Allow(ctx, req)
+	res, _ := l.Allow(ctx, req)
+	if res.Allowed {
+		t.Fatal("request over rate allowed")
+	}
+	if res.RetryAfter > 50*time.Millisecond {
+		t.Fatalf("RetryAfter = %v, want at most the window period", res.RetryAfter)
+	}
+
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+	if res, _ := l.Allow(ctx, req); !res.Allowed {
+		t.Fatal("request after window expired denied")
+	}
+}
+
+func TestWaitRespectsContext(t *testing.T) {
+	limiters := map[string]DistributedRateLimiter{
+		"token bucket":   NewTokenBucketLimiter(),
+		"sliding window": NewSlidingWindowLimiter(),
+	}
+	req := Request{UserID: "u1", Resource: "/a"}
+	limiters["token bucket"].(*TokenBucketLimiter).SetLimit(context.Background(), QuotaPerUser, "u1", RateLimit{Rate: 1, Period: time.Hour})
+	limiters["sliding window"].(*SlidingWindowLimiter).limits["u1:/a"] = RateLimit{Rate: 1, Period: time.Hour}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+			defer cancel()
+			if err := l.Wait(ctx, req); err != nil {
+				t.Fatalf("first Wait: %v", err)
+			}
+			if err := l.Wait(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Wait over limit = %v, want DeadlineExceeded", err)
+			}
+		})
+	}
+}
+
+func TestHierarchicalQuotaManager(t *testing.T) {
+	ctx := context.Background()
+	m := NewHierarchicalQuotaManager()
+
+	if _, err := m.GetLimit(ctx, QuotaPerUser, "u1"); err == nil {
+		t.Fatal("GetLimit without any limits returned no error")
+	}
+
+	global := RateLimit{Rate: 10, Period: time.Second}
+	user := RateLimit{Rate: 2, Period: time.Second}
+	m.SetLimit(ctx, QuotaGlobal, "default", global)
+	m.SetLimit(ctx, QuotaPerUser, "u1", user)
+
+	got, err := m.GetLimit(ctx, QuotaPerUser, "u1")
+	if err != nil || *got != user {
+		t.Fatalf("GetLimit(u1) = %v, %v, want %v", got, err, user)
+	}
+	got, err = m.GetLimit(ctx, QuotaPerUser, "u2")
+	if err != nil || *got != global {
+		t.Fatalf("GetLimit(u2) = %v, %v, want global %v", got, err, global)
+	}
+
+	// Изменение возвращённой копии не меняет хранимый лимит.
+	got.Rate = 1000
+	if again, _ := m.GetLimit(ctx, QuotaPerUser, "u2"); again.Rate != global.Rate {
+		t.Fatal("GetLimit returned shared limit instead of copy")
+	}
+
+	m.DeleteLimit(ctx, QuotaPerUser, "u1")
+	if got, _ := m.GetLimit(ctx, QuotaPerUser, "u1"); *got != global {
+		t.Fatalf("GetLimit after delete = %v, want global", got)
+	}
+
+	all, _ := m.ListLimits(ctx)
+	if len(all) != 1 || all["global:default"] != global {
+		t.Fatalf("ListLimits = %v, want only global:default", all)
+	}
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -85,6 +86,7 @@ func (s *VersionStore) add(id string, v Version) {
 	}
 	v.ID = atomic.AddInt64(&globalVersionSeq, 1)
 	v.ValidFrom = now
+	v.Data = maps.Clone(v.Data) // история не должна меняться вместе с картой вызывающего
 	s.versions[id] = append(vids, v)
 }
 
@@ -150,11 +152,17 @@ func (db *TemporalDatabase) Insert(_ context.Context, id string, data map[string
 }
 
 func (db *TemporalDatabase) Update(_ context.Context, id string, data map[string]interface{}) error {
+	if _, ok := db.store.current(id); !ok {
+		return fmt.Errorf("record %q not found", id)
+	}
 	db.store.add(id, Version{RecordID: id, Data: data, Operation: OperationUpdate})
 	return nil
 }
 
 func (db *TemporalDatabase) Delete(_ context.Context, id string) error {
+	if _, ok := db.store.current(id); !ok {
+		return fmt.Errorf("record %q not found", id)
+	}
 	db.store.add(id, Version{RecordID: id, Data: nil, Operation: OperationDelete})
 	return nil
 }
@@ -164,7 +172,7 @@ func (db *TemporalDatabase) Get(_ context.Context, id string) (map[string]interf
 	if !ok {
 		return nil, fmt.Errorf("record %q not found", id)
 	}
-	return v.Data, nil
+	return maps.Clone(v.Data), nil
 }
 
 func (db *TemporalDatabase) GetAsOf(_ context.Context, id string, ts time.Time) (map[string]interface{}, error) {
@@ -172,7 +180,7 @@ func (db *TemporalDatabase) GetAsOf(_ context.Context, id string, ts time.Time)
 	if !ok {
 		return nil, fmt.Errorf("record %q not found at %v", id, ts)
 	}
-	return v.Data, nil
+	return maps.Clone(v.Data), nil
 }
 
 func (db *TemporalDatabase) GetVersion(_ context.Context, id string, versionID int64) (*Version, error) {
@@ -216,7 +224,7 @@ func (db *TemporalDatabase) Query(_ context.Context, q TimeQuery, filter map[str
 			}
 		}
 		if match {
-			result = append(result, data)
+			result = append(result, maps.Clone(data))
 		}
 	}
 	return result, nil
@@ -290,14 +298,16 @@ func (gc *GarbageCollector) Compact(_ context.Context) (CompactionStats, error)
 	for id, vs := range gc.store.versions {
 		if gc.policy.KeepVersions > 0 && len(vs) > gc.policy.KeepVersions {
 			toRemove := len(vs) - gc.policy.KeepVersions
-			gc.store.versions[id] = vs[toRemove:]
+			vs = vs[toRemove:]
+			gc.store.versions[id] = vs
 			removed += int64(toRemove)
 		}
 		if gc.policy.KeepDuration > 0 {
 			cutoff := time.Now().Add(-gc.policy.KeepDuration)
 			filtered := vs[:0]
 			for _, v := range vs {
-				if v.ValidFrom.After(cutoff) || v.ValidTo == nil {
+				// Версия нужна, пока она была актуальна хоть в какой-то момент после cutoff.
+				if v.ValidTo == nil || v.ValidTo.After(cutoff) {
 					filtered = append(filtered, v)
 				} else {
 					removed++
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+// tick гарантирует, что следующая версия получит более поздний ValidFrom.
+func tick() time.Time {
+	time.Sleep(2 * time.Millisecond)
+	now := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	return now
+}
+
+func TestInsertUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	db := NewTemporalDatabase()
+
+	if err := db.Update(ctx, "r", map[string]interface{}{"v": 1}); err == nil {
+		t.Fatal("Update of missing record returned no error")
+	}
+	if err := db.Delete(ctx, "r"); err == nil {
+		t.Fatal("Delete of missing record returned no error")
+	}
+
+	data := map[string]interface{}{"v": 1}
+	if err := db.Insert(ctx, "r", data); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if err := db.Insert(ctx, "r", data); err == nil {
+		t.Fatal("second Insert of the same record returned no error")
+	}
+
+	// Изменение исходной карты не должно попадать в хранимую версию.
+	data["v"] = 100
+	got, err := db.Get(ctx, "r")
+	if err != nil || got["v"] != 1 {
+		t.Fatalf("Get = %v, %v, want v=1", got, err)
+	}
+	got["v"] = 200
+	if again, _ := db.Get(ctx, "r"); again["v"] != 1 {
+		t.Fatal("Get returned the stored map instead of a copy")
+	}
+
+	if err := db.Update(ctx, "r", map[string]interface{}{"v": 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := db.Get(ctx, "r"); got["v"] != 2 {
+		t.Fatalf("Get after update = %v, want v=2", got)
+	}
+
+	if err := db.Delete(ctx, "r"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := db.Get(ctx, "r"); err == nil {
+		t.Fatal("Get after delete returned no error")
+	}
+	if err := db.Update(ctx, "r", map[string]interface{}{"v": 3}); err == nil {
+		t.Fatal("Update of deleted record returned no error")
+	}
+	if err := db.Insert(ctx, "r", map[string]interface{}{"v": 4}); err != nil {
+		t.Fatalf("Insert after delete: %v", err)
+	}
+}
+
+func TestGetAsOf(t *testing.T) {
+	ctx := context.Background()
+	db := NewTemporalDatabase()
+
+	before := tick()
+	db.Insert(ctx, "r", map[string]interface{}{"v": 1})
+	t1 := tick()
+	db.Update(ctx, "r", map[string]interface{}{"v": 2})
+	t2 := tick()
+	db.Delete(ctx, "r")
+	t3 := tick()
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "before insert", at: before, wantErr: true},
+		{name: "after insert", at: t1, want: 1},
+		{name: "after update", at: t2, want: 2},
+		{name: "after delete", at: t3, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := db.GetAsOf(ctx, "r", tc.at)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("GetAsOf = %v, want error", got)
+				}
+				return
+			}
+			if err != nil || got["v"] != tc.want {
+				t.Fatalf("GetAsOf = %v, %v, want v=%v", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestHistoryVersionAndDiff(t *testing.T) {
+	ctx := context.Background()
+	db := NewTemporalDatabase()
+	db.Insert(ctx, "r", map[string]interface{}{"name": "a", "age": 1})
+	tick()
+	db.Update(ctx, "r", map[string]interface{}{"name": "a", "city": "x"})
+
+	history, _ := db.GetHistory(ctx, "r")
+	if len(history) != 2 {
+		t.Fatalf("history has %d versions, want 2", len(history))
+	}
+	if history[0].Operation != OperationInsert || history[1].Operation != OperationUpdate {
+		t.Fatalf("operations = %s, %s, want insert, update", history[0].Operation, history[1].Operation)
+	}
+	if history[0].ValidTo == nil || !history[0].ValidTo.Equal(history[1].ValidFrom) {
+		t.Fatal("previous version must be closed at the start of the next one")
+	}
+	if history[1].ValidTo != nil {
+		t.Fatal("current version must be open")
+	}
+
+	v, err := db.GetVersion(ctx, "r", history[0].ID)
+	if err != nil || v.Data["age"] != 1 {
+		t.Fatalf("GetVersion = %v, %v, want first version", v, err)
+	}
+	if _, err := db.GetVersion(ctx, "r", -1); err == nil {
+		t.Fatal("GetVersion of unknown id returned no error")
+	}
+
+	diff, err := db.Diff(ctx, "r", history[0].ID, history[1].ID)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	want := map[string][2]interface{}{
+		"age":  {1, nil},
+		"city": {nil, "x"},
+	}
+	if len(diff) != len(want) {
+		t.Fatalf("Diff = %v, want changes of %v", diff, want)
+	}
+	for k, w := range want {
+		change, ok := diff[k].(map[string]interface{})
+		if !ok || change["old"] != w[0] || change["new"] != w[1] {
+			t.Fatalf("Diff[%s] = %v, want old=%v new=%v", k, diff[k], w[0], w[1])
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	db := NewTemporalDatabase()
+	db.Insert(ctx, "1", map[string]interface{}{"id": 1, "role": "admin"})
+	db.Insert(ctx, "2", map[string]interface{}{"id": 2, "role": "user"})
+	t1 := tick()
+	db.Update(ctx, "2", map[string]interface{}{"id": 2, "role": "admin"})
+	db.Insert(ctx, "3", map[string]interface{}{"id": 3, "role": "admin"})
+	db.Delete(ctx, "1")
+
+	ids := func(rows []map[string]interface{}) []int {
+		var out []int
+		for _, r := range rows {
+			out = append(out, r["id"].(int))
+		}
+		sort.Ints(out)
+		return out
+	}
+
+	tests := []struct {
+		name string
+		q    TimeQuery
+		want []int
+	}{
+		{name: "current", want: []int{2, 3}},
+		{name: "as of t1", q: TimeQuery{AsOf: &t1}, want: []int{1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := db.Query(ctx, tc.q, map[string]interface{}{"role": "admin"})
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if got := ids(rows); !slices.Equal(got, tc.want) {
+				t.Fatalf("Query ids = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetChanges(t *testing.T) {
+	ctx := context.Background()
+	db := NewTemporalDatabase()
+	db.Insert(ctx, "old", map[string]interface{}{"v": 1})
+	from := tick()
+	db.Insert(ctx, "a", map[string]interface{}{"v": 1})
+	tick()
+	db.Update(ctx, "a", map[string]interface{}{"v": 2})
+	to := tick()
+	db.Insert(ctx, "late", map[string]interface{}{"v": 1})
+
+	changes, err := db.GetChanges(ctx, from, to)
+	if err != nil {
+		t.Fatalf("GetChanges: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("GetChanges returned %d versions, want 2", len(changes))
+	}
+	if changes[0].Operation != OperationInsert || changes[1].Operation != OperationUpdate {
+		t.Fatalf("changes = %s, %s, want insert, update in time order", changes[0].Operation, changes[1].Operation)
+	}
+}
+
+func TestGarbageCollector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keep versions", func(t *testing.T) {
+		db := NewTemporalDatabase()
+		db.Insert(ctx, "r", map[string]interface{}{"v": 0})
+		for i := 1; i < 5; i++ {
+			db.Update(ctx, "r", map[string]interface{}{"v": i})
+		}
+		gc := NewGarbageCollector(db.store, RetentionPolicy{KeepVersions: 2})
+		stats, err := gc.Compact(ctx)
+		if err != nil || stats.VersionsRemoved != 3 {
+			t.Fatalf("Compact = %+v, %v, want 3 versions removed", stats, err)
+		}
+		history, _ := db.GetHistory(ctx, "r")
+		if len(history) != 2 || history[1].Data["v"] != 4 {
+			t.Fatalf("history after compact = %v, want last 2 versions", history)
+		}
+	})
+
+	t.Run("keep versions and duration", func(t *testing.T) {
+		db := NewTemporalDatabase()
+		db.Insert(ctx, "r", map[string]interface{}{"v": 0})
+		for i := 1; i < 4; i++ {
+			db.Update(ctx, "r", map[string]interface{}{"v": i})
+		}
+		gc := NewGarbageCollector(db.store, RetentionPolicy{KeepVersions: 3, KeepDuration: time.Hour})
+		stats, _ := gc.Compact(ctx)
+		// Удалённая по KeepVersions версия не должна считаться второй раз.
+		if stats.VersionsRemoved != 1 {
+			t.Fatalf("VersionsRemoved = %d, want 1", stats.VersionsRemoved)
+		}
+		if history, _ := db.GetHistory(ctx, "r"); len(history) != 3 {
+			t.Fatalf("history has %d versions, want 3", len(history))
+		}
+	})
+
+	t.Run("keep duration", func(t *testing.T) {
+		db := NewTemporalDatabase()
+		db.Insert(ctx, "r", map[string]interface{}{"v": 0})
+		tick()
+		db.Update(ctx, "r", map[string]interface{}{"v": 1})
+		tick()
+		db.Update(ctx, "r", map[string]interface{}{"v": 2})
+		time.Sleep(20 * time.Millisecond)
+		mid := tick()
+		db.Update(ctx, "r", map[string]interface{}{"v": 3})
+
+		// Версия v=2 была актуальна внутри окна хранения — её нужно сохранить,
+		// чтобы GetAsOf(mid) продолжал работать.
+		gc := NewGarbageCollector(db.store, RetentionPolicy{KeepDuration: time.Since(mid) + 10*time.Millisecond})
+		stats, _ := gc.Compact(ctx)
+		if stats.VersionsRemoved != 2 {
+			t.Fatalf("VersionsRemoved = %d, want 2", stats.VersionsRemoved)
+		}
+		if got, err := db.GetAsOf(ctx, "r", mid); err != nil || got["v"] != 2 {
+			t.Fatalf("GetAsOf(mid) after compact = %v, %v, want v=2", got, err)
+		}
+	})
+}
@@ -156,10 +156,12 @@ func NewInMemoryTierCache(tier CacheTier) *InMemoryTierCache {
 	return &InMemoryTierCache{data: make(map[string]*cacheItem), tier: tier}
 }
 
+// get меняет счётчики и может удалить просроченный элемент, поэтому
+// работает под эксклюзивной блокировкой.
 func (c *InMemoryTierCache) get(key string) (*Content, CacheStatus) {
-	c.mu.RLock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	item, ok := c.data[key]
-	c.mu.RUnlock()
 	if !ok {
 		c.miss++
 		return nil, StatusMiss
@@ -170,17 +172,17 @@ func (c *InMemoryTierCache) get(key string) (*Content, CacheStatus) {
 		if swr > 0 && time.Since(item.storedAt) <= maxAge+swr {
 			c.hits++
 			item.hits++
-			return &item.content, StatusStale
+			content := item.content
+			return &content, StatusStale
 		}
-		c.mu.Lock()
 		delete(c.data, key)
-		c.mu.Unlock()
 		c.miss++
 		return nil, StatusMiss
 	}
 	c.hits++
 	item.hits++
-	return &item.content, StatusHit
+	content := item.content
+	return &content, StatusHit
 }
 
 func (c *InMemoryTierCache) put(content Content) {
@@ -195,11 +197,7 @@ func (c *InMemoryTierCache) invalidate(key string) {
 	c.mu.Unlock()
 }
 
-func (c *InMemoryTierCache) invalidatePattern(pattern string) {
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return
-	}
+func (c *InMemoryTierCache) invalidatePattern(re *regexp.Regexp) {
 	c.mu.Lock()
 	for k := range c.data {
 		if re.MatchString(k) {
@@ -211,8 +209,8 @@ func (c *InMemoryTierCache) invalidatePattern(pattern string) {
 
 func (c *InMemoryTierCache) stats() TierStats {
 	c.mu.RLock()
+	defer c.mu.RUnlock()
 	count := int64(len(c.data))
-	c.mu.RUnlock()
 	total := c.hits + c.miss
 	hitRate := 0.0
 	if total > 0 {
@@ -278,8 +276,12 @@ func (m *MultiTierEdgeCache) Invalidate(_ context.Context, key string) error {
 }
 
 func (m *MultiTierEdgeCache) InvalidatePattern(_ context.Context, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
 	for _, tier := range m.tiers {
-		tier.invalidatePattern(pattern)
+		tier.invalidatePattern(re)
 	}
 	m.broadcast(InvalidationMessage{Type: InvalidatePattern, Pattern: pattern, Timestamp: time.Now()})
 	return nil
@@ -290,7 +292,11 @@ func (m *MultiTierEdgeCache) Purge(ctx context.Context, key string) error {
 }
 
 func (m *MultiTierEdgeCache) GetFromTier(_ context.Context, tier CacheTier, key string) (*Content, error) {
-	c, _ := m.tiers[tier].get(key)
+	t, ok := m.tiers[tier]
+	if !ok {
+		return nil, fmt.Errorf("unknown tier %s", tier)
+	}
+	c, _ := t.get(key)
 	if c == nil {
 		return nil, fmt.Errorf("not found in tier %s", tier)
 	}
@@ -298,11 +304,15 @@ func (m *MultiTierEdgeCache) GetFromTier(_ context.Context, tier CacheTier, key
 }
 
 func (m *MultiTierEdgeCache) Promote(ctx context.Context, key string, fromTier, toTier CacheTier) error {
+	to, ok := m.tiers[toTier]
+	if !ok {
+		return fmt.Errorf("unknown tier %s", toTier)
+	}
 	c, err := m.GetFromTier(ctx, fromTier, key)
 	if err != nil {
 		return err
 	}
-	m.tiers[toTier].put(*c)
+	to.put(*c)
 	return nil
 }
 
@@ -387,9 +397,7 @@ func (r *GeoRouter) GetClosestNodes(_ context.Context, loc Location, count int)
 		dj := haversine(loc.Latitude, loc.Longitude, alive[j].Location.Latitude, alive[j].Location.Longitude)
 		return di < dj
 	})
-	if count > len(alive) {
-		count = len(alive)
-	}
+	count = max(0, min(count, len(alive)))
 	return alive[:count], nil
 }
 
@@ -452,7 +460,9 @@ func (c *CompressionMiddleware) Compress(_ context.Context, content []byte, enco
 	if _, err := w.Write(content); err != nil {
 		return nil, err
 	}
-	w.Close()
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
 	return buf.Bytes(), nil
 }
 
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMultiTierGet(t *testing.T) {
+	ctx := context.Background()
+	content := Content{Key: "/a", Data: []byte("a"), ETag: `"v1"`, CacheControl: CacheControl{MaxAge: time.Minute}}
+
+	tests := []struct {
+		name       string
+		stored     bool
+		opts       RequestOptions
+		wantStatus CacheStatus
+		wantBody   bool
+	}{
+		{name: "miss", wantStatus: StatusMiss},
+		{name: "hit", stored: true, wantStatus: StatusHit, wantBody: true},
+		{name: "etag matches", stored: true, opts: RequestOptions{IfNoneMatch: `"v1"`}, wantStatus: StatusRevalidated},
+		{name: "etag differs", stored: true, opts: RequestOptions{IfNoneMatch: `"v0"`}, wantStatus: StatusHit, wantBody: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cdn := NewMultiTierEdgeCache()
+			if tc.stored {
+				cdn.Put(ctx, content)
+			}
+			got, status, err := cdn.Get(ctx, "/a", tc.opts)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if status != tc.wantStatus {
+				t.Fatalf("status = %s, want %s", status, tc.wantStatus)
+			}
+			if (got != nil) != tc.wantBody {
+				t.Fatalf("content = %v, want body: %v", got, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestNoStoreNotCached(t *testing.T) {
+	ctx := context.Background()
+	cdn := NewMultiTierEdgeCache()
+	cdn.Put(ctx, Content{Key: "/secret", CacheControl: CacheControl{NoStore: true}})
+	if _, status, _ := cdn.Get(ctx, "/secret", RequestOptions{}); status != StatusMiss {
+		t.Fatalf("status = %s, want miss for no-store content", status)
+	}
+}
+
+func TestExpirationAndStaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	cdn := NewMultiTierEdgeCache()
+	cdn.Put(ctx, Content{Key: "/fresh", CacheControl: CacheControl{MaxAge: 20 * time.Millisecond}})
+	cdn.Put(ctx, Content{Key: "/swr", CacheControl: CacheControl{MaxAge: 20 * time.Millisecond, StaleWhileRevalidate: time.Minute}})
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, status, _ := cdn.Get(ctx, "/fresh", RequestOptions{}); status != StatusMiss {
+		t.Fatalf("expired content status = %s, want miss", status)
+	}
+	if c, status, _ := cdn.Get(ctx, "/swr", RequestOptions{}); status != StatusStale || c == nil {
+		t.Fatalf("stale-while-revalidate content status = %s, want stale", status)
+	}
+}
+
+func TestTiers(t *testing.T) {
+	ctx := context.Background()
+	cdn := NewMultiTierEdgeCache()
+	cdn.tiers[TierRegional].put(Content{Key: "/r", Data: []byte("r")})
+
+	if _, err := cdn.GetFromTier(ctx, TierEdge, "/r"); err == nil {
+		t.Fatal("content found in edge tier before promotion")
+	}
+	if _, status, _ := cdn.Get(ctx, "/r", RequestOptions{}); status != StatusHit {
+		t.Fatalf("status = %s, want hit from regional tier", status)
+	}
+	// Попадание в нижнем уровне поднимает контент на edge.
+	if _, err := cdn.GetFromTier(ctx, TierEdge, "/r"); err != nil {
+		t.Fatalf("content not promoted to edge: %v", err)
+	}
+
+	if err := cdn.Promote(ctx, "/r", TierRegional, TierOrigin); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if _, err := cdn.GetFromTier(ctx, TierOrigin, "/r"); err != nil {
+		t.Fatalf("content not copied by Promote: %v", err)
+	}
+	if err := cdn.Promote(ctx, "/missing", TierRegional, TierEdge); err == nil {
+		t.Fatal("Promote of missing key returned no error")
+	}
+	if _, err := cdn.GetFromTier(ctx, CacheTier("moon"), "/r"); err == nil {
+		t.Fatal("GetFromTier of unknown tier returned no error")
+	}
+	if err := cdn.Promote(ctx, "/r", TierRegional, CacheTier("moon")); err == nil {
+		t.Fatal("Promote to unknown tier returned no error")
+	}
+
+	stats, err := cdn.GetTierStats(ctx, TierEdge)
+	if err != nil {
+		t.Fatalf("GetTierStats: %v", err)
+	}
+	if stats.ItemCount != 1 || stats.HitRate <= 0 || stats.HitRate >= 1 {
+		t.Fatalf("edge stats = %+v, want 1 item and hit rate between 0 and 1", stats)
+	}
+}
+
+func TestInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cdn := NewMultiTierEdgeCache()
+	for _, key := range []string{"/img/a.png", "/img/b.png", "/css/site.css"} {
+		cdn.Put(ctx, Content{Key: key})
+	}
+
+	var msgs []InvalidationMessage
+	cdn.Subscribe(ctx, func(msg InvalidationMessage) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+
+	if err := cdn.InvalidatePattern(ctx, `^/img/`); err != nil {
+		t.Fatalf("InvalidatePattern: %v", err)
+	}
+	if err := cdn.Invalidate(ctx, "/css/site.css"); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	for _, key := range []string{"/img/a.png", "/img/b.png", "/css/site.css"} {
+		for _, tier := range cdn.order {
+			if _, err := cdn.GetFromTier(ctx, tier, key); err == nil {
+				t.Fatalf("%s still cached in tier %s", key, tier)
+			}
+		}
+	}
+	if len(msgs) != 2 || msgs[0].Type != InvalidatePattern || msgs[1].Type != InvalidateKey {
+		t.Fatalf("subscriber got %+v, want pattern and key invalidations", msgs)
+	}
+
+	if err := cdn.InvalidatePattern(ctx, `(`); err == nil {
+		t.Fatal("InvalidatePattern with invalid regexp returned no error")
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	cdn := NewMultiTierEdgeCache()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("/k%d", j%10)
+				cdn.Put(ctx, Content{Key: key})
+				cdn.Get(ctx, key, RequestOptions{})
+				cdn.GetTierStats(ctx, TierEdge)
+				if j%25 == 0 {
+					cdn.Invalidate(ctx, key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGeoRouter(t *testing.T) {
+	ctx := context.Background()
+	r := NewGeoRouter()
+	if _, err := r.Route(ctx, "/a", Location{}); err == nil {
+		t.Fatal("Route without nodes returned no error")
+	}
+
+	r.AddNode(NodeInfo{ID: "london", Status: NodeStatusHealthy, Location: Location{Latitude: 51.5, Longitude: -0.1}})
+	r.AddNode(NodeInfo{ID: "frankfurt", Status: NodeStatusUnhealthy, Location: Location{Latitude: 50.1, Longitude: 8.7}})
+	r.AddNode(NodeInfo{ID: "tokyo", Status: NodeStatusHealthy, Location: Location{Latitude: 35.7, Longitude: 139.7}})
+	r.AddNode(NodeInfo{ID: "sf", Status: NodeStatusHealthy, Location: Location{Latitude: 37.8, Longitude: -122.4}})
+
+	berlin := Location{Latitude: 52.5, Longitude: 13.4}
+	node, err := r.Route(ctx, "/a", berlin)
+	if err != nil || node.ID != "london" {
+		t.Fatalf("Route(berlin) = %v, %v, want london: unhealthy frankfurt must be skipped", node, err)
+	}
+
+	nodes, _ := r.GetClosestNodes(ctx, berlin, 10)
+	var ids []string
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	if fmt.Sprint(ids) != "[london tokyo sf]" {
+		t.Fatalf("GetClosestNodes = %v, want [london tokyo sf]", ids)
+	}
+	if nodes, err := r.GetClosestNodes(ctx, berlin, -1); err != nil || len(nodes) != 0 {
+		t.Fatalf("GetClosestNodes(-1) = %v, %v, want empty", nodes, err)
+	}
+}
+
+func TestOriginShieldCollapsesRequests(t *testing.T) {
+	s := NewOriginShieldLayer()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetcher := func() (*Content, error) {
+		calls.Add(1)
+		<-release
+		return &Content{Key: "/a"}, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]*Content, n)
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		results[0], _ = s.Shield(context.Background(), "/a", fetcher)
+	}()
+	<-started
+	// Ждём, пока первый запрос займёт ключ, и запускаем остальные.
+	for {
+		s.mu.Lock()
+		_, inflight := s.inflight["/a"]
+		s.mu.Unlock()
+		if inflight {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = s.Shield(context.Background(), "/a", fetcher)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("fetcher called %d times, want 1", calls.Load())
+	}
+	for i, c := range results {
+		if c == nil || c.Key != "/a" {
+			t.Fatalf("result %d = %v, want shared content", i, c)
+		}
+	}
+
+	// После завершения ключ освобождается: ошибка следующего запроса не кешируется.
+	errOrigin := errors.New("origin down")
+	if _, err := s.Shield(context.Background(), "/a", func() (*Content, error) { return nil, errOrigin }); !errors.Is(err, errOrigin) {
+		t.Fatalf("Shield error = %v, want %v", err, errOrigin)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	comp := &CompressionMiddleware{}
+	data := bytes.Repeat([]byte("edge cache "), 100)
+
+	gz, err := comp.Compress(ctx, data, "gzip")
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	if len(gz) >= len(data) {
+		t.Fatalf("compressed size %d, want less than %d", len(gz), len(data))
+	}
+	back, err := comp.Decompress(ctx, gz, "gzip")
+	if err != nil || !bytes.Equal(back, data) {
+		t.Fatalf("Decompress roundtrip failed: %v", err)
+	}
+	if out, _ := comp.Compress(ctx, data, "identity"); !bytes.Equal(out, data) {
+		t.Fatal("identity encoding changed content")
+	}
+	if _, err := comp.Decompress(ctx, []byte("not gzip"), "gzip"); err == nil {
+		t.Fatal("Decompress of invalid data returned no error")
+	}
+
+	tests := []struct {
+		accepted []string
+		want     string
+	}{
+		{accepted: []string{"br", "gzip, deflate"}, want: "gzip"},
+		{accepted: []string{"br"}, want: "identity"},
+		{accepted: nil, want: "identity"},
+	}
+	for _, tc := range tests {
+		if got := comp.NegotiateEncoding(tc.accepted); got != tc.want {
+			t.Fatalf("NegotiateEncoding(%v) = %s, want %s", tc.accepted, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func wordCountJob(id string, lines ...string) Job {
+	var splits []InputSplit
+	for i, line := range lines {
+		splits = append(splits, InputSplit{
+			ID:   fmt.Sprintf("s%d", i),
+			Data: []KeyValue{{Key: i, Value: line}},
+		})
+	}
+	return Job{
+		ID:          id,
+		NumReducers: 3,
+		Input:       splits,
+		MapFunc: func(_, value interface{}) ([]KeyValue, error) {
+			var out []KeyValue
+			for _, w := range strings.Fields(value.(string)) {
+				out = append(out, KeyValue{Key: w, Value: 1})
+			}
+			return out, nil
+		},
+		ReduceFunc: func(_ interface{}, values []interface{}) (interface{}, error) {
+			sum := 0
+			for _, v := range values {
+				sum += v.(int)
+			}
+			return sum, nil
+		},
+	}
+}
+
+func counts(output []KeyValue) map[string]int {
+	m := make(map[string]int)
+	for _, kv := range output {
+		if _, dup := m[kv.Key.(string)]; dup {
+			panic("duplicate key in output: " + kv.Key.(string))
+		}
+		m[kv.Key.(string)] = kv.Value.(int)
+	}
+	return m
+}
+
+func run(t *testing.T, job Job) (*JobResult, error) {
+	t.Helper()
+	m := NewMapReduceMaster()
+	id, err := m.SubmitJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("SubmitJob: %v", err)
+	}
+	return m.WaitForCompletion(context.Background(), id)
+}
+
+func TestWordCount(t *testing.T) {
+	res, err := run(t, wordCountJob("wc", "a b a", "b c", "a"))
+	if err != nil || !res.Success {
+		t.Fatalf("WaitForCompletion = %+v, %v", res, err)
+	}
+	got := counts(res.Output)
+	want := map[string]int{"a": 3, "b": 2, "c": 1}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("output = %v, want %v", got, want)
+	}
+}
+
+func TestCombiner(t *testing.T) {
+	job := wordCountJob("wc", "x x", "x")
+	var calls int
+	var mu sync.Mutex
+	job.CombineFunc = func(_ interface{}, values []interface{}) ([]interface{}, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		sum := 0
+		for _, v := range values {
+			sum += v.(int)
+		}
+		return []interface{}{sum}, nil
+	}
+
+	res, err := run(t, job)
+	if err != nil {
+		t.Fatalf("WaitForCompletion: %v", err)
+	}
+	if got := counts(res.Output)["x"]; got != 3 {
+		t.Fatalf("count of x = %d, want 3", got)
+	}
+	if calls != 2 {
+		t.Fatalf("combiner called %d times, want once per input record", calls)
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	ctx := context.Background()
+	m := NewMapReduceMaster()
+	id, _ := m.SubmitJob(ctx, wordCountJob("wc", "a", "b"))
+
+	st, err := m.GetJobStatus(ctx, id)
+	if err != nil || st.Status != TaskStatusPending || st.TotalTasks != 5 || st.Progress != 0 {
+		t.Fatalf("status before run = %+v, %v", st, err)
+	}
+
+	if _, err := m.WaitForCompletion(ctx, id); err != nil {
+		t.Fatalf("WaitForCompletion: %v", err)
+	}
+	st, _ = m.GetJobStatus(ctx, id)
+	if st.Status != TaskStatusCompleted || st.CompletedAt == nil || st.CompletedTasks < 2 {
+		t.Fatalf("status after run = %+v", st)
+	}
+
+	if _, err := m.GetJobStatus(ctx, "missing"); err == nil {
+		t.Fatal("GetJobStatus of unknown job returned no error")
+	}
+	if _, err := m.WaitForCompletion(ctx, "missing"); err == nil {
+		t.Fatal("WaitForCompletion of unknown job returned no error")
+	}
+}
+
+func TestPartitioner(t *testing.T) {
+	p := &HashPartitioner{}
+	for i := 0; i < 1000; i++ {
+		key := strings.Repeat("z", i)
+		part := p.Partition(key, 7)
+		if part < 0 || part >= 7 {
+			t.Fatalf("Partition(%d chars) = %d, want in [0, 7)", i, part)
+		}
+		if p.Partition(key, 7) != part {
+			t.Fatal("Partition is not deterministic")
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	s := NewInMemoryShuffler()
+	in := []KeyValue{{Key: "a", Value: 1}, {Key: "b", Value: 1}, {Key: "a", Value: 2}, {Key: "c", Value: 1}}
+	parts, err := s.Shuffle(context.Background(), in, 2)
+	if err != nil {
+		t.Fatalf("Shuffle: %v", err)
+	}
+
+	p := &HashPartitioner{}
+	total := 0
+	for part, kvs := range parts {
+		for _, kv := range kvs {
+			if p.Partition(kv.Key, 2) != part {
+				t.Fatalf("partition %d holds key %v of another partition", part, kv.Key)
+			}
+		}
+		total += len(kvs)
+	}
+	if total != len(in) {
+		t.Fatalf("partitions hold %d pairs, want %d", total, len(in))
+	}
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+	w := NewMapReduceWorker("w1")
+	sum := func(_ interface{}, values []interface{}) (interface{}, error) { return len(values), nil }
+
+	out, err := w.ExecuteReduce(ctx, Task{ID: "r0"}, sum, []KeyValue{{Key: "a", Value: 1}, {Key: "a", Value: 1}})
+	if err != nil || out != 2 {
+		t.Fatalf("ExecuteReduce = %v, %v, want 2", out, err)
+	}
+	if _, err := w.ExecuteMap(ctx, Task{ID: "m0"}, nil); err != nil {
+		t.Fatalf("ExecuteMap: %v", err)
+	}
+	w.Heartbeat(ctx)
+
+	st := w.GetStatus()
+	if st.ID != "w1" || !st.IsAlive || st.TasksCompleted != 2 || len(st.CurrentTasks) != 1 {
+		t.Fatalf("status = %+v, want alive with 2 completed tasks", st)
+	}
+}
@@ -371,7 +371,13 @@ func (n *RaftNode) randomElectionTimeout() time.Duration {
 
 func (n *RaftNode) Start(ctx context.Context) error {
 	ctx, n.cancel = context.WithCancel(ctx)
-	go n.runElectionTimer(ctx)
+	// Таймер создаём под n.mu: resetElectionTimer читает его из обработчиков RPC,
+	// которые другие узлы могут вызвать ещё до завершения Start.
+	timer := time.NewTimer(n.randomElectionTimeout())
+	n.mu.Lock()
+	n.electionTimer = timer
+	n.mu.Unlock()
+	go n.runElectionTimer(ctx, timer)
 	return nil
 }
 
@@ -382,22 +388,20 @@ func (n *RaftNode) Stop(_ context.Context) error {
 	return nil
 }
 
-func (n *RaftNode) runElectionTimer(ctx context.Context) {
-	timeout := n.randomElectionTimeout()
-	n.electionTimer = time.NewTimer(timeout)
-	defer n.electionTimer.Stop()
+func (n *RaftNode) runElectionTimer(ctx context.Context, timer *time.Timer) {
+	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-n.electionTimer.C:
+		case <-timer.C:
 			n.mu.RLock()
 			state := n.state
 			n.mu.RUnlock()
 			if state != StateLeader {
 				n.startElection(ctx)
 			}
-			n.electionTimer.Reset(n.randomElectionTimeout())
+			timer.Reset(n.randomElectionTimeout())
 		}
 	}
 }
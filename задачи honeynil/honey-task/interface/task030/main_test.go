@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func entries(term int64, from, to int64) []LogEntry {
+	var out []LogEntry
+	for i := from; i <= to; i++ {
+		out = append(out, LogEntry{Index: i, Term: term, Command: fmt.Sprintf("cmd%d", i)})
+	}
+	return out
+}
+
+func TestLogStore(t *testing.T) {
+	s := &InMemoryLogStore{}
+	if s.GetLastIndex() != 0 || s.GetLastTerm() != 0 {
+		t.Fatal("empty log must have index 0 and term 0")
+	}
+	s.Append(entries(1, 1, 3))
+	s.Append(entries(2, 4, 5))
+
+	if e, err := s.Get(4); err != nil || e.Command != "cmd4" || e.Term != 2 {
+		t.Fatalf("Get(4) = %v, %v", e, err)
+	}
+	for _, idx := range []int64{0, 6} {
+		if _, err := s.Get(idx); err == nil {
+			t.Fatalf("Get(%d) returned no error", idx)
+		}
+	}
+	if r, _ := s.GetRange(2, 10); len(r) != 4 || r[0].Index != 2 {
+		t.Fatalf("GetRange(2, 10) = %v, want entries 2..5", r)
+	}
+	if r, err := s.GetRange(4, 3); err != nil || len(r) != 0 {
+		t.Fatalf("GetRange(4, 3) = %v, %v, want empty", r, err)
+	}
+
+	s.DeleteRange(4)
+	if s.GetLastIndex() != 3 || s.GetLastTerm() != 1 {
+		t.Fatalf("after DeleteRange(4): last = %d/%d, want 3/1", s.GetLastIndex(), s.GetLastTerm())
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	s := &InMemorySnapshotStore{}
+	if snap, err := s.Load(); snap != nil || err != nil {
+		t.Fatalf("Load of empty store = %v, %v", snap, err)
+	}
+	s.Save(Snapshot{LastIncludedIndex: 5})
+	s.Save(Snapshot{LastIncludedIndex: 10})
+	if snap, _ := s.Load(); snap.LastIncludedIndex != 10 {
+		t.Fatalf("Load = %d, want latest snapshot 10", snap.LastIncludedIndex)
+	}
+	s.Delete(10)
+	if list, _ := s.List(); len(list) != 1 || list[0].LastIncludedIndex != 5 {
+		t.Fatalf("List after delete = %v", list)
+	}
+}
+
+func TestMembershipQuorum(t *testing.T) {
+	m := NewMembershipManager()
+	tests := []struct {
+		member Member
+		want   int
+	}{
+		{Member{ID: "a", Role: MemberRoleVoter, Status: MemberStatusAlive}, 1},
+		{Member{ID: "b", Role: MemberRoleVoter, Status: MemberStatusAlive}, 2},
+		{Member{ID: "c", Role: MemberRoleVoter, Status: MemberStatusAlive}, 2},
+		{Member{ID: "d", Role: MemberRoleLearner, Status: MemberStatusAlive}, 2},
+		{Member{ID: "e", Role: MemberRoleVoter, Status: MemberStatusDead}, 2},
+		{Member{ID: "f", Role: MemberRoleVoter, Status: MemberStatusAlive}, 3},
+	}
+	for _, tc := range tests {
+		m.AddMember(tc.member)
+		if got := m.GetQuorum(); got != tc.want {
+			t.Fatalf("after adding %s: quorum = %d, want %d", tc.member.ID, got, tc.want)
+		}
+	}
+	m.RemoveMember("f")
+	if _, err := m.GetMember("f"); err == nil {
+		t.Fatal("removed member still present")
+	}
+	if got := m.GetQuorum(); got != 2 {
+		t.Fatalf("quorum after remove = %d, want 2", got)
+	}
+}
+
+func TestKVStateMachine(t *testing.T) {
+	kv := NewKVStateMachine()
+	kv.Apply(LogEntry{Command: map[string]string{"a": "1", "b": "2"}})
+	kv.Apply(LogEntry{Command: map[string]string{"a": "3"}})
+	kv.Apply(LogEntry{Command: "not a map"})
+	if got := fmt.Sprint(kv.GetState()); got != "map[a:3 b:2]" {
+		t.Fatalf("state = %s, want map[a:3 b:2]", got)
+	}
+
+	// GetState возвращает копию.
+	kv.GetState().(map[string]string)["a"] = "changed"
+	if got := kv.GetState().(map[string]string)["a"]; got != "3" {
+		t.Fatalf("GetState copy leaked into state machine: a = %s", got)
+	}
+}
+
+func newNode(id string) *RaftNode {
+	return NewRaftNode(ConsensusConfig{
+		NodeID:             id,
+		ElectionTimeoutMin: 100 * time.Millisecond,
+		ElectionTimeoutMax: 200 * time.Millisecond,
+		HeartbeatInterval:  20 * time.Millisecond,
+	}, NewKVStateMachine())
+}
+
+func set(k, v string) map[string]string { return map[string]string{k: v} }
+
+func TestRequestVote(t *testing.T) {
+	ctx := context.Background()
+	n := newNode("n1")
+	n.log.Append([]LogEntry{{Index: 1, Term: 2}})
+	n.currentTerm = 2
+
+	tests := []struct {
+		name string
+		req  VoteRequest
+		want bool
+	}{
+		{name: "stale term", req: VoteRequest{Term: 1, CandidateID: "a", LastLogIndex: 5, LastLogTerm: 2}, want: false},
+		{name: "log behind", req: VoteRequest{Term: 3, CandidateID: "a", LastLogIndex: 1, LastLogTerm: 1}, want: false},
+		{name: "log up to date", req: VoteRequest{Term: 3, CandidateID: "b", LastLogIndex: 1, LastLogTerm: 2}, want: true},
+		{name: "already voted in term", req: VoteRequest{Term: 3, CandidateID: "c", LastLogIndex: 9, LastLogTerm: 3}, want: false},
+		{name: "repeat vote for same candidate", req: VoteRequest{Term: 3, CandidateID: "b", LastLogIndex: 1, LastLogTerm: 2}, want: true},
+		{name: "new term resets vote", req: VoteRequest{Term: 4, CandidateID: "c", LastLogIndex: 1, LastLogTerm: 2}, want: true},
+	}
+	for _, tc := range tests {
+		resp, err := n.RequestVote(ctx, tc.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if resp.VoteGranted != tc.want {
+			t.Fatalf("%s: VoteGranted = %v, want %v", tc.name, resp.VoteGranted, tc.want)
+		}
+	}
+}
+
+func TestAppendEntries(t *testing.T) {
+	ctx := context.Background()
+	n := newNode("f")
+
+	resp, _ := n.AppendEntries(ctx, AppendEntriesRequest{Term: 1, LeaderID: "l", Entries: entries(1, 1, 3)})
+	if !resp.Success || n.log.GetLastIndex() != 3 {
+		t.Fatalf("append: success=%v last=%d", resp.Success, n.log.GetLastIndex())
+	}
+	if st := n.GetState(); st.CommitIndex != 0 || st.Leader != "l" || st.State != StateFollower {
+		t.Fatalf("state after append = %+v, want follower of l with nothing committed", st)
+	}
+
+	// Коммит лидера применяется не дальше конца собственного лога.
+	n.AppendEntries(ctx, AppendEntriesRequest{Term: 1, LeaderID: "l", LeaderCommit: 10})
+	if st := n.GetState(); st.CommitIndex != 3 || st.LastApplied != 3 {
+		t.Fatalf("state after commit = %+v, want commit 3 applied 3", st)
+	}
+
+	// Запрос из старого терма отвергается.
+	n.AppendEntries(ctx, AppendEntriesRequest{Term: 2, LeaderID: "l2"})
+	if resp, _ := n.AppendEntries(ctx, AppendEntriesRequest{Term: 1, LeaderID: "l"}); resp.Success || resp.Term != 2 {
+		t.Fatalf("stale leader: success=%v term=%d", resp.Success, resp.Term)
+	}
+}
+
+func TestInstallSnapshot(t *testing.T) {
+	ctx := context.Background()
+	n := newNode("f")
+	n.currentTerm = 2
+
+	n.InstallSnapshot(ctx, SnapshotRequest{Term: 1, LeaderID: "l", LastIncludedIndex: 3})
+	if snap, _ := n.snapshots.Load(); snap != nil {
+		t.Fatalf("snapshot from stale term saved: %+v", snap)
+	}
+
+	resp, err := n.InstallSnapshot(ctx, SnapshotRequest{Term: 2, LeaderID: "l", LastIncludedIndex: 5, LastIncludedTerm: 2, Done: true})
+	if err != nil || resp.Term != 2 {
+		t.Fatalf("InstallSnapshot = %+v, %v", resp, err)
+	}
+	if snap, _ := n.snapshots.Load(); snap == nil || snap.LastIncludedIndex != 5 || snap.LastIncludedTerm != 2 {
+		t.Fatalf("snapshot not saved: %v", snap)
+	}
+}
+
+func TestPropose(t *testing.T) {
+	ctx := context.Background()
+	n := newNode("n1")
+	if err := n.Propose(ctx, set("a", "1")); err == nil {
+		t.Fatal("Propose on follower returned no error")
+	}
+
+	// Единственный голосующий узел сам себе кворум.
+	n.state, n.currentTerm = StateLeader, 1
+	if err := n.Propose(ctx, set("a", "1")); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if st := n.GetState(); st.CommitIndex != 1 || st.LastApplied != 1 {
+		t.Fatalf("state = %+v, want entry 1 committed and applied", st)
+	}
+	if got := fmt.Sprint(n.stateMachine.GetState()); got != "map[a:1]" {
+		t.Fatalf("state machine = %s, want map[a:1]", got)
+	}
+}
+
+func TestClusterElectsLeaderAndReplicates(t *testing.T) {
+	nodes := []*RaftNode{newNode("n1"), newNode("n2"), newNode("n3")}
+	for _, n := range nodes {
+		for _, peer := range nodes {
+			if peer != n {
+				n.AddPeer(peer)
+				n.AddNode(context.Background(), peer.config.NodeID, "")
+			}
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, n := range nodes {
+		n.Start(ctx)
+	}
+
+	leader := waitFor(t, 3*time.Second, func() *RaftNode {
+		var found *RaftNode
+		for _, n := range nodes {
+			if n.IsLeader() {
+				if found != nil {
+					return nil
+				}
+				found = n
+			}
+		}
+		return found
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := leader.Propose(ctx, set(fmt.Sprintf("k%d", i), "v")); err != nil {
+			t.Fatalf("Propose %d: %v", i, err)
+		}
+	}
+
+	want := "map[k0:v k1:v k2:v k3:v k4:v]"
+	if got := fmt.Sprint(leader.stateMachine.GetState()); got != want {
+		t.Fatalf("leader state = %s, want %s", got, want)
+	}
+	// Followers применяют записи, узнав commitIndex из heartbeat'ов.
+	for _, n := range nodes {
+		waitFor(t, 2*time.Second, func() *RaftNode {
+			if fmt.Sprint(n.stateMachine.GetState()) == want {
+				return n
+			}
+			return nil
+		})
+		if st := n.GetState(); st.LastLogIndex != 5 {
+			t.Fatalf("%s last log index = %d, want 5", st.ID, st.LastLogIndex)
+		}
+	}
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() *RaftNode) *RaftNode {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if n := cond(); n != nil {
+			return n
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met before timeout")
+	return nil
+}
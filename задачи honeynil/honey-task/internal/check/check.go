@@ -0,0 +1,181 @@
+// Package check сверяет фактический вывод задач "Что выведет?" с ответом,
+// записанным в комментарии задачи.
+package check
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Result — результат проверки одной задачи.
+type Result struct {
+	Topic     string
+	Num       int
+	Expected  []string
+	Actual    []string
+	Unordered bool
+	Pass      bool
+	Skipped   string // причина, по которой задача не проверялась
+	Err       error  // ошибка запуска (паника, ненулевой код выхода)
+	Stderr    string // stderr задачи, если она запускалась отдельным процессом
+	Duration  time.Duration
+}
+
+// Task проверяет задачу num темы topic.
+func Task(reg *registry.Registry, topic registry.Topic, num int) Result {
+	res := Result{Topic: topic.Name, Num: num}
+
+	doc, err := taskdoc.Parse(topic, num)
+	if err != nil {
+		res.Skipped = err.Error()
+		return res
+	}
+	want, ok := doc.Expected(reg.GoMinor)
+	if !ok {
+		res.Skipped = i18n.T("нет блока OUTPUT/ОТВЕТ с ожидаемым выводом")
+		return res
+	}
+	if want.Unchecked {
+		res.Skipped = i18n.T("вывод недетерминирован: ") + want.Label
+		return res
+	}
+	res.Expected, res.Unordered = want.Lines, want.Unordered
+
+	var out string
+	start := time.Now()
+	if fn, ok := topic.Tasks[num]; ok {
+		out, err = Capture(fn)
+	} else {
+		out, res.Stderr, err = runProcess(reg.Root, topic, num)
+	}
+	res.Duration = time.Since(start)
+	res.Actual, res.Err = splitLines(out), err
+	res.Pass = Equal(res.Expected, res.Actual, res.Unordered)
+	return res
+}
+
+// Capture вызывает fn, перехватывая всё, что она пишет в os.Stdout.
+// Паника внутри fn возвращается как ошибка.
+func Capture(fn func()) (out string, err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		r.Close()
+		done <- buf.String()
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+		os.Stdout = stdout
+		w.Close()
+		out = <-done
+	}()
+	fn()
+	return "", nil
+}
+
+func runProcess(root string, topic registry.Topic, num int) (stdout, stderr string, err error) {
+	cmd, cleanup, err := runner.Command(root, topic, num)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	res := runner.Exec(cmd, runner.Timeout, nil, nil)
+	switch res.Status {
+	case runner.StatusOK:
+		return res.Stdout, res.Stderr, nil
+	case runner.StatusTimeout:
+		return res.Stdout, res.Stderr, fmt.Errorf(i18n.T("задача не завершилась за %s"), runner.Timeout)
+	}
+	return res.Stdout, res.Stderr, fmt.Errorf("%s: %s", res.Status, res.Message)
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return lines
+}
+
+// Equal сравнивает ожидаемый и фактический вывод. При unordered порядок
+// строк не учитывается: порядок итерации по map случаен. Если ответ —
+// одна строка, без учёта порядка сравниваются слова в ней: задача могла
+// печатать ключи map через пробел.
+func Equal(want, got []string, unordered bool) bool {
+	switch {
+	case !unordered:
+		return slices.Equal(want, got)
+	case len(want) == 1 && len(got) == 1:
+		return slices.Equal(sorted(strings.Fields(want[0])), sorted(strings.Fields(got[0])))
+	}
+	return slices.Equal(sorted(want), sorted(got))
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
+
+// Diff возвращает построчный diff: "-" — строка есть только в ожидаемом
+// выводе, "+" — только в фактическом.
+func Diff(want, got []string) string {
+	// LCS по строкам: выводы задач короткие, квадратичной памяти хватает.
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			fmt.Fprintf(&b, "  %s\n", want[i])
+			i++
+			j++
+		case j < len(got) && (i == len(want) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&b, "+ %s\n", got[j])
+			j++
+		default:
+			fmt.Fprintf(&b, "- %s\n", want[i])
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got []string
+		unordered bool
+		equal     bool
+	}{
+		{"same lines", []string{"a", "b"}, []string{"a", "b"}, false, true},
+		{"both empty", nil, nil, false, true},
+		{"swapped lines", []string{"a", "b"}, []string{"b", "a"}, false, false},
+		{"missing line", []string{"a", "b"}, []string{"a"}, false, false},
+		{"unordered swapped lines", []string{"a 1", "b 2"}, []string{"b 2", "a 1"}, true, true},
+		{"unordered swapped values", []string{"a 1", "b 2"}, []string{"a 2", "b 1"}, true, false},
+		{"unordered duplicate lines count", []string{"x", "x", "y"}, []string{"x", "y", "y"}, true, false},
+		{"unordered words in single line", []string{"b a c"}, []string{"a c b"}, true, true},
+		{"unordered single line vs two", []string{"a b"}, []string{"a", "b"}, true, false},
+		{"unordered different words", []string{"a b"}, []string{"a c"}, true, false},
+		{"ordered single line keeps word order", []string{"b a"}, []string{"a b"}, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Equal(tc.want, tc.got, tc.unordered); got != tc.equal {
+				t.Fatalf("Equal(%q, %q, %v) = %v, want %v", tc.want, tc.got, tc.unordered, got, tc.equal)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got []string
+		diff      string
+	}{
+		{"equal", []string{"a", "b"}, []string{"a", "b"}, "  a\n  b\n"},
+		{"changed line", []string{"a", "b", "c"}, []string{"a", "x", "c"}, "  a\n+ x\n- b\n  c\n"},
+		{"extra line", []string{"a"}, []string{"a", "b"}, "  a\n+ b\n"},
+		{"missing line", []string{"a", "b"}, []string{"b"}, "- a\n  b\n"},
+		{"empty actual", []string{"a"}, nil, "- a\n"},
+		{"both empty", nil, nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Diff(tc.want, tc.got); got != tc.diff {
+				t.Fatalf("Diff(%q, %q) =\n%s\nwant\n%s", tc.want, tc.got, got, tc.diff)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"\n", nil},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a  \t\n\nb", []string{"a", "", "b"}},
+	}
+	for _, tc := range tests {
+		got := splitLines(tc.in)
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tc.want) {
+			t.Errorf("splitLines(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	out, err := Capture(func() { fmt.Println("hello") })
+	if err != nil || out != "hello\n" {
+		t.Fatalf("Capture = %q, %v; want %q, nil", out, err, "hello\n")
+	}
+
+	out, err = Capture(func() {
+		fmt.Println("before")
+		panic(errors.New("boom"))
+	})
+	if err == nil || err.Error() != "panic: boom" || out != "before\n" {
+		t.Fatalf("Capture with panic = %q, %v; want output before panic and panic error", out, err)
+	}
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Deck — колода, в которую Anki импортирует карточки.
+const Deck = "honey-task"
+
+// ankiHeader — директивы текстового импорта Anki (2.1.55+): поля разделены
+// табуляцией и содержат HTML, первая колонка — GUID, по которому повторный
+// импорт обновляет карточки, а не создаёт дубли.
+var ankiHeader = []string{
+	"#separator:tab",
+	"#html:true",
+	"#deck:" + Deck,
+	"#guid column:1",
+	"#tags column:4",
+}
+
+// WriteAnki пишет колоду Anki из задач "Что выведет?": на лицевой стороне
+// условие и код, на обороте ответ с объяснением. Возвращает число карточек.
+func WriteAnki(w io.Writer, topics []Topic) (int, error) {
+	var b strings.Builder
+	for _, h := range ankiHeader {
+		b.WriteString(h + "\n")
+	}
+	n := 0
+	for _, topic := range topics {
+		for _, t := range topic.Tasks {
+			if t.Answer == "" {
+				continue
+			}
+			front := fmt.Sprintf("<b>%s/%d</b>", t.Topic, t.Num)
+			if t.Question != "" {
+				front += "<br>" + ankiText(t.Question)
+			}
+			front += "<pre>" + ankiText(t.Code) + "</pre>"
+			back := "<pre>" + ankiText(t.Answer) + "</pre>"
+			fields := []string{Deck + "/" + t.Key(), front, back, ankiTags(t)}
+			b.WriteString(strings.Join(fields, "\t") + "\n")
+			n++
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return n, err
+}
+
+// ankiText экранирует текст для HTML-поля: перевод строки и табуляция
+// разделяют записи и поля, поэтому заменяются на <br> и пробелы.
+func ankiText(s string) string {
+	s = html.EscapeString(strings.TrimRight(s, "\n"))
+	s = strings.ReplaceAll(s, "\t", "    ")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// ankiTags — теги карточки через пробел: тема, сложность и теги задачи.
+func ankiTags(t Task) string {
+	tags := []string{t.Topic}
+	if t.Difficulty != "" {
+		tags = append(tags, t.Difficulty)
+	}
+	return strings.Join(append(tags, t.Tags...), " ")
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/web"
+)
+
+// BookTitle — заголовок книги.
+const BookTitle = "honey-task — практические задачи по Go"
+
+// WriteMarkdown пишет книгу в Markdown: оглавление и все задачи тем,
+// ответы, решения и подсказки — под спойлерами <details>. Якоря заданы
+// явно: автоматические якоря заголовков с кириллицей у рендеров разные.
+func WriteMarkdown(w io.Writer, topics []Topic) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Содержание\n\n", BookTitle)
+	for _, topic := range topics {
+		fmt.Fprintf(&b, "- [%s](#%s) — %s\n", topic.Name, topic.Name, topic.Description)
+		for _, t := range topic.Tasks {
+			fmt.Fprintf(&b, "  - [%d. %s](#%s)\n", t.Num, title(t), t.Anchor())
+		}
+	}
+
+	for _, topic := range topics {
+		fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n## %s\n\n%s\n", topic.Name, topic.Name, topic.Description)
+		for _, t := range topic.Tasks {
+			fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n### %s/%d. %s\n\n", t.Anchor(), t.Topic, t.Num, title(t))
+			if info := taskInfo(t); info != "" {
+				fmt.Fprintf(&b, "*%s*\n\n", info)
+			}
+			if t.Question != "" {
+				b.WriteString(fence("text", t.Question))
+			}
+			if t.Code != "" {
+				b.WriteString(fence("go", t.Code))
+			}
+			for i, h := range t.Hints {
+				b.WriteString(details(fmt.Sprintf("Подсказка %d", i+1), fence("text", h)))
+			}
+			if t.Answer != "" {
+				b.WriteString(details("Ответ", fence("text", t.Answer)))
+			}
+			if t.Solution != "" {
+				b.WriteString(details("Эталонное решение", fence("go", t.Solution)))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fence оформляет блок кода; забор длиннее любой серии ` внутри.
+func fence(lang, s string) string {
+	f := "```"
+	for strings.Contains(s, f) {
+		f += "`"
+	}
+	return f + lang + "\n" + strings.TrimRight(s, "\n") + "\n" + f + "\n\n"
+}
+
+func details(summary, body string) string {
+	return "<details>\n<summary>" + summary + "</summary>\n\n" + body + "</details>\n\n"
+}
+
+func title(t Task) string {
+	if t.Title == "" {
+		return fmt.Sprintf("Задача %d", t.Num)
+	}
+	return t.Title
+}
+
+// taskInfo — строка метаданных: сложность, время, источник, теги.
+func taskInfo(t Task) string {
+	var parts []string
+	if t.Difficulty != "" {
+		parts = append(parts, "сложность: "+t.Difficulty)
+	}
+	if t.Estimate > 0 {
+		parts = append(parts, fmt.Sprintf("~%d мин", int(t.Estimate.Minutes())))
+	}
+	if t.Source != "" {
+		parts = append(parts, "источник: "+t.Source)
+	}
+	if len(t.Tags) > 0 {
+		parts = append(parts, "теги: "+strings.Join(t.Tags, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+//go:embed book.html
+var bookHTML string
+
+var bookTmpl = template.Must(template.New("book").Funcs(template.FuncMap{
+	"highlight": web.Highlight,
+	"title":     title,
+	"info":      taskInfo,
+	"inc":       func(i int) int { return i + 1 },
+}).Parse(bookHTML))
+
+// WriteHTML пишет книгу одной статической страницей: стили и подсветка
+// встроены, поэтому файл открывается без сервера, в том числе на телефоне.
+func WriteHTML(w io.Writer, topics []Topic) error {
+	return bookTmpl.Execute(w, struct {
+		Title  string
+		Style  template.CSS
+		Topics []Topic
+	}{BookTitle, web.Stylesheet(), topics})
+}
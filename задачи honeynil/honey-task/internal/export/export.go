@@ -0,0 +1,96 @@
+// Package export выгружает задачи для занятий без тренажёра: колоду Anki
+// из задач "Что выведет?" и книгу по всем темам в Markdown или HTML.
+// Условия, ответы и метаданные берутся теми же разборщиками, что у раннера
+// и веб-интерфейса: taskdoc и meta.
+package export
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/honeynil/honey-task/internal/meta"
+	"github.com/honeynil/honey-task/internal/practice"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Topic — тема с задачами в порядке номеров.
+type Topic struct {
+	Name        string
+	Description string
+	Tasks       []Task
+}
+
+// Task — задача в том виде, в каком её показывает веб-интерфейс.
+type Task struct {
+	meta.Meta
+	Question string // условие
+	Code     string // исходник, который показывается сразу
+	Solution string // эталонное решение — под спойлером
+	Answer   string // ответ с объяснением; есть только у задач "Что выведет?"
+}
+
+// Anchor — идентификатор задачи для ссылок из оглавления.
+func (t Task) Anchor() string {
+	return t.Topic + "-" + strconv.Itoa(t.Num)
+}
+
+// Load собирает задачи тем.
+func Load(topics []registry.Topic) ([]Topic, error) {
+	var out []Topic
+	for _, topic := range topics {
+		ms, err := meta.Load(topic)
+		if err != nil {
+			return nil, err
+		}
+		t := Topic{Name: topic.Name, Description: topic.Description}
+		for _, m := range ms {
+			task, err := load(topic, m)
+			if err != nil {
+				return nil, err
+			}
+			t.Tasks = append(t.Tasks, task)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// load раскладывает задачу так же, как страница задачи в web: у "Что
+// выведет?" код без комментариев открыт, а ответ спрятан; у задач с тестами
+// спрятан эталонный main.go; код-ревью и прочее показываются целиком.
+func load(topic registry.Topic, m meta.Meta) (Task, error) {
+	task := Task{Meta: m}
+	doc, docErr := taskdoc.Parse(topic, m.Num)
+	if docErr == nil && len(doc.Answers) > 0 {
+		src, err := taskdoc.Source(topic, m.Num)
+		if err != nil {
+			return Task{}, err
+		}
+		task.Question, task.Code, task.Answer = doc.Question(), src, doc.Solution()
+		return task, nil
+	}
+
+	src, err := source(topic, m.Num)
+	if err != nil {
+		return Task{}, err
+	}
+	if docErr == nil {
+		task.Question = doc.Text
+	}
+	if topic.IsFileBased && practice.CheckMode(topic, m.Num) != practice.ModeRun {
+		task.Solution = src
+	} else {
+		task.Code = src
+	}
+	return task, nil
+}
+
+// source — исходник с комментариями, но без подсказок: они выводятся отдельно.
+func source(topic registry.Topic, num int) (string, error) {
+	if !topic.IsFileBased {
+		return taskdoc.Source(topic, num)
+	}
+	data, err := os.ReadFile(topic.TaskFile(num))
+	return string(taskdoc.StripHidden(data)), err
+}
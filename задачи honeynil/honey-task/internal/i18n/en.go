@@ -0,0 +1,240 @@
+package i18n
+
+// english — английский каталог. Ключи сгруппированы по командам и пакетам,
+// в которых печатаются; новая строка раннера без перевода выводится по-русски.
+var english = map[string]string{
+	// Общее.
+	"неизвестный язык %q: ожидается ru или en":                      "unknown language %q: expected ru or en",
+	"Не удалось найти задачи: %v\n":                                 "Could not find tasks: %v\n",
+	"Команда %s поддерживает только --format=text\n":                "Command %s supports only --format=text\n",
+	"Неизвестная тема: %s\n\n":                                      "Unknown topic: %s\n\n",
+	"Неизвестная тема: %s\n":                                        "Unknown topic: %s\n",
+	"Использование: go run main.go %s <тема> <номер>\n":             "Usage: go run main.go %s <topic> <number>\n",
+	"Неверный номер задачи: %s (доступны %s)\n":                     "Invalid task number: %s (available: %s)\n",
+	"Не удалось записать отчёт: %v\n":                               "Could not write the report: %v\n",
+	"Команда %s не поддерживает --format=junit, используйте json\n": "Command %s does not support --format=junit, use json\n",
+	"Не удалось записать вывод: %v\n":                               "Could not write the output: %v\n",
+	"Не удалось загрузить прогресс: %v\n":                           "Could not load progress: %v\n",
+	"Не удалось сохранить прогресс: %v\n":                           "Could not save progress: %v\n",
+
+	// Описания тем.
+	"Maps в go":      "Maps in Go",
+	"Указатели в go": "Pointers in Go",
+	"Слайсы в go":    "Slices in Go",
+	"Структуры в go": "Structs in Go",
+	"Интерфейсы в go (реализация паттернов и систем)":  "Interfaces in Go (implementing patterns and systems)",
+	"Конкурентность в go (goroutines, channels, sync)": "Concurrency in Go (goroutines, channels, sync)",
+	"Алгоритмические задачи":                           "Algorithm tasks",
+	"Ревью кода: найти и исправить проблемы":           "Code review: find and fix the problems",
+
+	// Флаги.
+	"ограничение времени на задачу в отдельном процессе (0 — без ограничения)": "time limit for a task run in a separate process (0 — no limit)",
+	"запускать задачи с race detector'ом (go run -race)":                       "run tasks with the race detector (go run -race)",
+	"длительность собеседования (interview)":                                   "interview duration (interview)",
+	"состав собеседования: тема:число через запятую (interview)":               "interview mix: comma-separated topic:count (interview)",
+	"адрес веб-интерфейса (serve)":                                             "web UI address (serve)",
+	"формат вывода: text, json или junit":                                      "output format: text, json or junit",
+	"выгрузить колоду Anki из задач «Что выведет?» (export)":                   "export an Anki deck of \"what will it print?\" tasks (export)",
+	"выгрузить книгу по всем темам (export)":                                   "export a book covering all topics (export)",
+	"файл выгрузки; .html — книга в HTML, иначе Markdown (export)":             "output file; .html — HTML book, otherwise Markdown (export)",
+	"язык сообщений: ru или en (по умолчанию из LANG)":                         "message language: ru or en (defaults to LANG)",
+	"вид новой задачи: predict, implement, review или algo (new)":              "kind of the new task: predict, implement, review or algo (new)",
+
+	// Справка.
+	"Go Practice Tasks - Практические задачи по Go":                                                               "Go Practice Tasks - hands-on Go exercises",
+	"\nИспользование:":                                                                                            "\nUsage:",
+	"  go run main.go <тема> <задачи>":                                                                            "  go run main.go <topic> <tasks>",
+	"\nКоманды:":                                                                                                  "\nCommands:",
+	"  go run main.go list                    - список доступных тем":                                             "  go run main.go list                    - list available topics",
+	"  go run main.go <тема>                  - справка по теме":                                                  "  go run main.go <topic>                 - topic help",
+	"  go run main.go <тема> <номер>          - запустить задачу":                                                 "  go run main.go <topic> <number>        - run a task",
+	"  go run main.go <тема> <номера>         - запустить несколько задач":                                        "  go run main.go <topic> <numbers>       - run several tasks",
+	"  go run main.go <тема> all              - запустить все задачи":                                             "  go run main.go <topic> all             - run all tasks",
+	"  go run main.go check <тема> [номера]   - сверить вывод с блоками // OUTPUT:":                               "  go run main.go check <topic> [numbers] - compare output with // OUTPUT: blocks",
+	"  go run main.go quiz <тема> [номера]    - угадать вывод до запуска задачи":                                  "  go run main.go quiz <topic> [numbers]  - guess the output before running a task",
+	"  go run main.go review                  - повторить задачи, назначенные на сегодня":                         "  go run main.go review                  - review tasks scheduled for today",
+	"  go run main.go stats                   - прогресс по темам":                                                "  go run main.go stats                   - progress by topic",
+	"  go run main.go racecheck [темы]        - убедиться, что эталонные решения без гонок":                       "  go run main.go racecheck [topics]      - make sure reference solutions are race-free",
+	"  go run main.go practice <тема> <номер> - заготовка задачи для самостоятельного решения":                    "  go run main.go practice <topic> <n>    - task stub to solve on your own",
+	"  go run main.go verify <тема> <номер>   - проверить своё решение":                                           "  go run main.go verify <topic> <n>      - check your solution",
+	"  go run main.go search <запрос>         - поиск задач: слова, tag:x, topic:x, difficulty:x":                 "  go run main.go search <query>          - search tasks: words, tag:x, topic:x, difficulty:x",
+	"  go run main.go interview               - тренировочное собеседование с таймером и отчётом":                 "  go run main.go interview               - mock interview with a timer and a report",
+	"  go run main.go serve                   - веб-интерфейс: задачи, ответы и запуск в браузере":                "  go run main.go serve                   - web UI: tasks, answers and runs in the browser",
+	"  go run main.go new <тема>              - заготовка следующей задачи темы (или новой темы)":                 "  go run main.go new <topic>             - scaffold the next task of a topic (or a new topic)",
+	"  go run main.go lint                    - проверить оформление задач: заголовки, ответы, нумерацию, сборку": "  go run main.go lint                    - check task conventions: headers, answers, numbering, build",
+	"  go run main.go watch <тема> <номер>    - перезапускать задачу и её тесты при сохранении":                   "  go run main.go watch <topic> <n>       - re-run a task and its tests on save",
+	"  go run main.go hint <тема> <номер>     - открыть следующую подсказку к задаче":                             "  go run main.go hint <topic> <n>        - reveal the next hint for a task",
+	"  go run main.go export --anki [темы]    - колода Anki (TSV) из задач «Что выведет?»":                        "  go run main.go export --anki [topics]  - Anki deck (TSV) of \"what will it print?\" tasks",
+	"  go run main.go export --book [темы]    - книга по темам: Markdown или HTML (--out book.html)":              "  go run main.go export --book [topics]  - study book: Markdown or HTML (--out book.html)",
+	"\nФлаги:": "\nFlags:",
+	"  --timeout=30s                          - ограничение времени на задачу (0 — без ограничения)":   "  --timeout=30s                          - time limit per task (0 — no limit)",
+	"  --race                                 - запускать задачи с race detector'ом":                   "  --race                                 - run tasks with the race detector",
+	"  --duration=60m                         - длительность собеседования":                            "  --duration=60m                         - interview duration",
+	"  --mix=algo:1,concurrency:2,maps:3      - состав собеседования (по умолчанию по задаче из темы)": "  --mix=algo:1,concurrency:2,maps:3      - interview mix (default: one task per topic)",
+	"  --addr=localhost:8080                  - адрес веб-интерфейса":                                  "  --addr=localhost:8080                  - web UI address",
+	"  --format=text|json|junit               - формат вывода для CI (кроме интерактивных команд)":     "  --format=text|json|junit               - output format for CI (except interactive commands)",
+	"  --kind=predict|implement|review|algo   - вид новой задачи (по умолчанию по теме)":               "  --kind=predict|implement|review|algo   - kind of the new task (default depends on the topic)",
+	"  --lang=ru|en                           - язык сообщений (по умолчанию из LANG)":                 "  --lang=ru|en                           - message language (defaults to LANG)",
+	"\nПримеры:": "\nExamples:",
+	"  go run main.go slices 1                - запустить задачу 1 по слайсам": "  go run main.go slices 1                - run slices task 1",
+	"  go run main.go slices 1 5 10           - запустить задачи 1, 5 и 10":    "  go run main.go slices 1 5 10           - run tasks 1, 5 and 10",
+	"  go run main.go slices all              - все задачи по слайсам":         "  go run main.go slices all              - all slices tasks",
+	"\nДоступные темы:":         "\nAvailable topics:",
+	"  %-12s - %s (%d задач)\n": "  %-12s - %s (%d tasks)\n",
+
+	// list и справка по теме.
+	"Доступные темы:\n": "Available topics:\n",
+	"\nИспользуйте: go run main.go <тема> для подробностей":                   "\nUse: go run main.go <topic> for details",
+	"Доступно задач: %d\n\n":                                                  "Tasks available: %d\n\n",
+	"Каждая задача — отдельный каталог taskNNN с main.go.":                    "Each task is a separate taskNNN directory with main.go.",
+	"  %-36s - запустить задачу %d\n":                                         "  %-36s - run task %d\n",
+	"\nИли используйте:":                                                      "\nOr use:",
+	"  go run main.go %s <номер>      - автоматически запустит файл задачи\n": "  go run main.go %s <number>     - runs the task file for you\n",
+	"\nСмотрите %s/README.md для подробного списка задач.\n":                  "\nSee %s/README.md for the full list of tasks.\n",
+	"Использование:":                                                          "Usage:",
+	"  go run main.go %s <номер>          - запустить одну задачу\n":          "  go run main.go %s <number>         - run one task\n",
+	"  go run main.go %s 1 5 10           - запустить несколько задач\n":      "  go run main.go %s 1 5 10           - run several tasks\n",
+	"  go run main.go %s all              - запустить все задачи\n":           "  go run main.go %s all              - run all tasks\n",
+
+	// Запуск задач.
+	"ЗАДАЧА %d\n":                     "TASK %d\n",
+	"ЗАДАЧА %d - %s/main.go\n":        "TASK %d - %s/main.go\n",
+	"Ошибка при запуске задачи: %v\n": "Error running the task: %v\n",
+	"задача не завершилась за %s и была остановлена": "the task did not finish within %s and was stopped",
+	"ИТОГО: %s\n": "SUMMARY: %s\n",
+	"задача не завершилась за %s": "the task did not finish within %s",
+	"найдено гонок: %d":           "races found: %d",
+
+	// racecheck.
+	"\nЗадач с гонками: %d\n": "\nTasks with races: %d\n",
+	"\nГонок не найдено.":     "\nNo races found.",
+
+	// practice и verify.
+	"Рабочее место уже есть: %s\n":                              "The workspace already exists: %s\n",
+	"Удалите каталог, чтобы начать заново.":                     "Delete the directory to start over.",
+	"Не удалось создать рабочее место: %v\n":                    "Could not create the workspace: %v\n",
+	"Заготовка задачи %s/%d: %s/main.go\n":                      "Stub for task %s/%d: %s/main.go\n",
+	"Тела функций заменены на panic(\"TODO\") — реализуйте их.": "Function bodies are replaced with panic(\"TODO\") — implement them.",
+	"Проверить решение: go run main.go verify %s %d\n":          "Check your solution: go run main.go verify %s %d\n",
+	"Не удалось проверить решение: %v\n":                        "Could not check the solution: %v\n",
+	"OK   %s/%d: задача выполнилась, но автоматических проверок у неё нет — сверьте вывод сами\n": "OK   %s/%d: the task ran, but it has no automated checks — compare the output yourself\n",
+	"Задача %s/%d — на чтение кода, реализовывать в ней нечего.\n":                                "Task %s/%d is a code-reading task, there is nothing to implement.\n",
+	"Проверьте себя в квизе: go run main.go quiz %s %d\n":                                         "Test yourself in the quiz: go run main.go quiz %s %d\n",
+	"Проверок у задачи нет, поэтому main тоже заглушён — напишите программу по условию.":          "The task has no checks, so main is stubbed too — write the program from the description.",
+
+	// watch.
+	"Слежу за %s (Ctrl+C — выход)\n":      "Watching %s (Ctrl+C to exit)\n",
+	"Не удалось следить за задачей: %v\n": "Could not watch the task: %v\n",
+	"    вывод не изменился":              "    output unchanged",
+	"    ... ещё %d строк\n":              "    ... %d more lines\n",
+
+	// hint.
+	"Не удалось прочитать подсказки: %v\n":       "Could not read hints: %v\n",
+	"У задачи %s %d нет подсказок.\n":            "Task %s %d has no hints.\n",
+	"Подсказка %d/%d: %s\n":                      "Hint %d/%d: %s\n",
+	"\nПодсказок больше нет. Эталонное решение:": "\nNo more hints. Reference solution:",
+	"\nСледующая: go run main.go hint %s %d\n":   "\nNext: go run main.go hint %s %d\n",
+
+	// search.
+	"Использование: go run main.go search <слова|tag:x|topic:x|difficulty:x>...": "Usage: go run main.go search <words|tag:x|topic:x|difficulty:x>...",
+	"\nТеги:":             "\nTags:",
+	"Найдено задач: %d\n": "Tasks found: %d\n",
+	" ~%d мин":            " ~%d min",
+
+	// new.
+	"Использование: go run main.go new <тема> [--kind=predict|implement|review|algo]": "Usage: go run main.go new <topic> [--kind=predict|implement|review|algo]",
+	"Не удалось создать задачу: %v\n":                                                 "Could not create the task: %v\n",
+	"Новая тема: %s\n": "New topic: %s\n",
+	"Задача %s/%d:\n":  "Task %s/%d:\n",
+	"Заполните места с TODO и проверьте задачу:":                                           "Fill in the TODOs and check the task:",
+	"Чтобы задачи темы запускались без go run, добавьте её GetTasks в compiled в main.go.": "To run the topic's tasks without go run, add its GetTasks to compiled in main.go.",
+
+	// lint.
+	"Замечаний нет.":                         "No issues.",
+	"\nЗамечаний: %d\n":                      "\nIssues: %d\n",
+	"не разбирается: %s":                     "does not parse: %s",
+	"не собирается: %s":                      "does not build: %s",
+	"нет комментария с заголовком задачи %d": "no comment with a header for task %d",
+	"заголовок в старом формате %q, используйте «ЗАДАЧА %d: ...»":                         "header %q uses the old format, use «ЗАДАЧА %d: ...»",
+	"заголовок не распознан: %q (ожидается «ЗАДАЧА N: ...», «Задача: ...» или «ТЗ: ...»)": "unrecognized header: %q (expected «ЗАДАЧА N: ...», «Задача: ...» or «ТЗ: ...»)",
+	"у задачи func-based темы заголовок должен быть «ЗАДАЧА %d: ...»":                     "a task in a func-based topic must have the header «ЗАДАЧА %d: ...»",
+	"в заголовке номер %s, а задача %d":                                                   "the header says %s, but the task is %d",
+	"нет блока OUTPUT с ожидаемым выводом":                                                "no OUTPUT block with the expected output",
+	"пустой блок ответа: после OUTPUT/ОТВЕТ нет ни строк вывода, ни (ничего)":             "empty answer block: OUTPUT/ОТВЕТ is followed by neither output lines nor (ничего)",
+	"нет перевода на %s: добавьте %s":                                                     "no %s translation: add %s",
+	"нет комментария «ТЗ: ...» с условием код-ревью":                                      "no «ТЗ: ...» comment with the code review spec",
+	"ссылка на несуществующий файл %s":                                                    "link to a missing file %s",
+
+	// export.
+	"Использование: go run main.go export --anki|--book [--out файл] [темы]": "Usage: go run main.go export --anki|--book [--out file] [topics]",
+	"Не удалось прочитать задачи: %v\n":                                      "Could not read tasks: %v\n",
+	"Не удалось создать файл: %v\n":                                          "Could not create the file: %v\n",
+	"Карточек: %d. Импорт в Anki: Файл → Импорт, колода %s.":                 "Cards: %d. Import into Anki: File → Import, deck %s.",
+	"Книга: %d тем.":            "Book: %d topics.",
+	"Не удалось записать: %v\n": "Could not write: %v\n",
+	"%s Записано в %s\n":        "%s Written to %s\n",
+
+	// serve.
+	"Веб-интерфейс: http://%s/\n": "Web UI: http://%s/\n",
+	"Сервер остановлен: %v\n":     "Server stopped: %v\n",
+
+	// interview.
+	"Не удалось собрать собеседование: %v\n": "Could not assemble the interview: %v\n",
+	"\nРешено: %d из %d за %s\n":             "\nSolved: %d of %d in %s\n",
+	"Не удалось сохранить отчёт: %v\n":       "Could not save the report: %v\n",
+	"Отчёт: %s\n": "Report: %s\n",
+	"Собеседование: %d задач, %s. На каждом шаге: skip — пропустить задачу, quit — завершить.\n": "Interview: %d tasks, %s. At every step: skip — skip the task, quit — finish.\n",
+	"\n%s\nЗадача %d из %d: %s — осталось %s":                  "\n%s\nTask %d of %d: %s — %s left",
+	" (~%s на задачу)":                                         " (~%s per task)",
+	"\nВремя вышло, оставшиеся задачи не начаты.":              "\nTime is up, the remaining tasks were not started.",
+	"Рабочее место уже было создано раньше: %s\n":              "The workspace was created earlier: %s\n",
+	"Реализуйте решение в %s.\n":                               "Implement the solution in %s.\n",
+	"Enter — проверить, skip — пропустить, quit — завершить: ": "Enter — check, skip — skip, quit — finish: ",
+	"Напишите ответ построчно и завершите строкой \".\" (skip — пропустить, quit — завершить)": "Write your answer line by line and finish with \".\" (skip — skip, quit — finish)",
+	"\nЭталон: %s\nЗасчитать ответ? [y/N] ":                             "\nReference: %s\nCount the answer as correct? [y/N] ",
+	"\n[осталось 5 минут]":                                              "\n[5 minutes left]",
+	"\n[время вышло — завершите текущую задачу]":                        "\n[time is up — finish the current task]",
+	"# Собеседование %s\n\n":                                            "# Interview %s\n\n",
+	"- Состав: `%s`\n":                                                  "- Mix: `%s`\n",
+	"- Время: %s из %s\n":                                               "- Time: %s of %s\n",
+	"- Решено: %d из %d, неверно: %d, пропущено: %d, не начато: %d\n\n": "- Solved: %d of %d, wrong: %d, skipped: %d, not started: %d\n\n",
+	"| # | Задача | Формат | Результат | Время |\n":                     "| # | Task | Format | Result | Time |\n",
+	"- Результат: %s\n":                                                 "- Result: %s\n",
+	"- Формат: %s, время: %s\n":                                         "- Format: %s, time: %s\n",
+	"- Запусков проверки: %d\n":                                         "- Check runs: %d\n",
+	"- Эталон: %s\n":                                                    "- Reference: %s\n",
+	"- Решение: [%s](%s)\n":                                             "- Solution: [%s](%s)\n",
+	"\nОтвет:\n\n```text\n":                                             "\nAnswer:\n\n```text\n",
+	"решено":                                                            "solved",
+	"неверно":                                                           "wrong",
+	"пропущено":                                                         "skipped",
+	"не начато":                                                         "not started",
+
+	// check.
+	"Использование: go run main.go check <тема> [номера...]": "Usage: go run main.go check <topic> [numbers...]",
+	"\nИтого: %d прошло, %d упало, %d пропущено\n":           "\nTotal: %d passed, %d failed, %d skipped\n",
+	" (порядок не учитывается)":                              " (order ignored)",
+	"     ошибка: %v\n":                                      "     error: %v\n",
+	"ошибка: %v\n%s":                                         "error: %v\n%s",
+	"нет блока OUTPUT/ОТВЕТ с ожидаемым выводом":             "no OUTPUT/ОТВЕТ block with the expected output",
+	"вывод недетерминирован: ":                               "the output is nondeterministic: ",
+
+	// quiz и review.
+	"Использование: go run main.go quiz <тема> [номера...]":              "Usage: go run main.go quiz <topic> [numbers...]",
+	"\nРезультат: %d из %d\n":                                            "\nScore: %d of %d\n",
+	"Введите ожидаемый вывод построчно и завершите строкой \".\"":        "Type the expected output line by line and finish with \".\"",
+	"(или одно слово: panic, deadlock; skip — пропустить, quit — выйти)": "(or a single word: panic, deadlock; skip — skip, quit — exit)",
+	"\nПропущено. Ответ из комментария:":                                 "\nSkipped. The answer from the comment:",
+	"\nФактический результат (%s):\n%s\n":                                "\nActual result (%s):\n%s\n",
+	"\nВерно!":            "\nCorrect!",
+	"\nНеверно.":          "\nWrong.",
+	"\nОбъяснение:\n%s\n": "\nExplanation:\n%s\n",
+	"\nОтвет из комментария: %s\nЗасчитать? [y/N] ":    "\nThe answer from the comment: %s\nCount it as correct? [y/N] ",
+	"На сегодня повторений нет.":                       "Nothing to review today.",
+	"К повторению: %d задач\n":                         "To review: %d tasks\n",
+	"\n%s: вспомните решение (go run main.go %s %d)\n": "\n%s: recall the solution (go run main.go %s %d)\n",
+	"Насколько хорошо помните, 0-5 (пусто — выйти)? ":  "How well do you remember it, 0-5 (empty — exit)? ",
+
+	// stats: заголовок выровнен под колонки "%-12s %8d %8d %8d %8d %9s %10d".
+	"Тема            Задач   Начато  Освоено  Сегодня  Точность  Подсказки": "Topic           Tasks  Started Mastered      Due  Accuracy      Hints",
+}
@@ -0,0 +1,101 @@
+// Package i18n переводит сообщения раннера. Ключ сообщения — сама русская
+// строка (для форматов — вместе с глаголами %s, %d): русский язык остаётся
+// языком по умолчанию и исходников, каталог нужен только для переводов,
+// а строка без перевода выводится как есть.
+//
+// Язык выбирается флагом --lang или переменными окружения LC_ALL,
+// LC_MESSAGES и LANG — в порядке приоритета POSIX.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Lang — код языка ISO 639-1.
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+)
+
+// Default — язык исходных строк и язык по умолчанию.
+const Default = Russian
+
+// catalogs — переводы по языкам; у русского каталога нет.
+var catalogs = map[Lang]map[string]string{
+	English: english,
+}
+
+var current = Default
+
+// Parse разбирает значение --lang или локаль вида "en_US.UTF-8".
+func Parse(s string) (Lang, error) {
+	code := strings.ToLower(s)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	switch l := Lang(code); l {
+	case Russian, English:
+		return l, nil
+	}
+	return "", fmt.Errorf(T("неизвестный язык %q: ожидается ru или en"), s)
+}
+
+// FromEnv — язык из локали окружения; пустая, "C", "POSIX" и неизвестные
+// локали дают язык по умолчанию.
+func FromEnv() Lang {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		if l, err := Parse(v); err == nil {
+			return l
+		}
+		return Default
+	}
+	return Default
+}
+
+// Set выбирает язык сообщений.
+func Set(l Lang) { current = l }
+
+// Current — выбранный язык.
+func Current() Lang { return current }
+
+// T переводит сообщение на выбранный язык.
+func T(msg string) string {
+	if tr, ok := catalogs[current][msg]; ok {
+		return tr
+	}
+	return msg
+}
+
+// Sprintf форматирует переведённый формат.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf печатает переведённый формат в stdout.
+func Printf(format string, args ...any) {
+	fmt.Printf(T(format), args...)
+}
+
+// Println печатает переведённое сообщение и перевод строки в stdout.
+func Println(msg string) {
+	fmt.Println(T(msg))
+}
+
+// Fprintf печатает переведённый формат в w.
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprintf(w, T(format), args...)
+}
+
+// Fprintln печатает переведённое сообщение и перевод строки в w.
+func Fprintln(w io.Writer, msg string) {
+	fmt.Fprintln(w, T(msg))
+}
@@ -0,0 +1,112 @@
+// Package interview собирает тренировочное собеседование: случайный набор
+// задач из нескольких тем, прохождение по одной с обратным отсчётом
+// и отчёт в Markdown.
+package interview
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// Pick — сколько задач взять из темы.
+type Pick struct {
+	Topic string
+	Count int
+}
+
+// ParseMix разбирает состав сессии вида "algo:1,concurrency:2,maps:3".
+// Тема без числа означает одну задачу.
+func ParseMix(s string) ([]Pick, error) {
+	var mix []Pick
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, count, ok := strings.Cut(part, ":")
+		p := Pick{Topic: strings.TrimSpace(name), Count: 1}
+		if ok {
+			n, err := strconv.Atoi(strings.TrimSpace(count))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("неверное число задач в %q", part)
+			}
+			p.Count = n
+		}
+		mix = append(mix, p)
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("пустой состав сессии")
+	}
+	return mix, nil
+}
+
+// DefaultMix — по одной задаче из каждой темы.
+func DefaultMix(reg *registry.Registry) []Pick {
+	var mix []Pick
+	for _, t := range reg.Topics() {
+		if t.Count > 0 {
+			mix = append(mix, Pick{Topic: t.Name, Count: 1})
+		}
+	}
+	return mix
+}
+
+// FormatMix печатает состав в том же виде, в каком его принимает ParseMix.
+func FormatMix(mix []Pick) string {
+	parts := make([]string, len(mix))
+	for i, p := range mix {
+		parts[i] = fmt.Sprintf("%s:%d", p.Topic, p.Count)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Item — задача сессии.
+type Item struct {
+	Topic registry.Topic
+	Num   int
+}
+
+// Key — "topic/num".
+func (it Item) Key() string {
+	return fmt.Sprintf("%s/%d", it.Topic.Name, it.Num)
+}
+
+// Plan выбирает для каждой темы из mix случайные задачи без повторов.
+// Темы идут в порядке mix, задачи внутри темы — в случайном.
+func Plan(reg *registry.Registry, mix []Pick, rng *rand.Rand) ([]Item, error) {
+	var items []Item
+	used := make(map[string]bool)
+	for _, p := range mix {
+		topic, ok := reg.Topic(p.Topic)
+		if !ok {
+			return nil, fmt.Errorf("неизвестная тема: %s", p.Topic)
+		}
+		var free []int
+		for _, num := range topic.Numbers {
+			if !used[Item{topic, num}.Key()] {
+				free = append(free, num)
+			}
+		}
+		if p.Count > len(free) {
+			return nil, fmt.Errorf("в теме %s только %d задач, запрошено %d", topic.Name, len(free), p.Count)
+		}
+		rng.Shuffle(len(free), func(i, j int) { free[i], free[j] = free[j], free[i] })
+		for _, num := range free[:p.Count] {
+			it := Item{Topic: topic, Num: num}
+			used[it.Key()] = true
+			items = append(items, it)
+		}
+	}
+	return items, nil
+}
+
+// NewRand возвращает генератор, засеянный текущим временем.
+func NewRand() *rand.Rand {
+	now := uint64(time.Now().UnixNano())
+	return rand.New(rand.NewPCG(now, now>>32))
+}
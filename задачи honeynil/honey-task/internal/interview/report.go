@@ -0,0 +1,132 @@
+package interview
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// ReportDir — каталог отчётов в корне модуля; как и _practice, он скрыт от поиска тем.
+const ReportDir = "_interviews"
+
+// Report — итоги сессии.
+type Report struct {
+	Started  time.Time
+	Duration time.Duration // отведённое время
+	Spent    time.Duration // фактическое
+	Mix      []Pick
+	Entries  []Entry
+}
+
+// Count возвращает число задач с исходом o.
+func (r *Report) Count(o Outcome) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// Save пишет отчёт в <root>/_interviews/<время начала>.md и возвращает путь.
+func (r *Report) Save(root string) (string, error) {
+	dir := filepath.Join(root, ReportDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, r.Started.Format("2006-01-02-150405")+".md")
+	return path, os.WriteFile(path, r.Markdown(dir), 0o644)
+}
+
+// Markdown форматирует отчёт. Ссылки на эталоны и решения строятся
+// относительно dir — каталога, в котором будет лежать отчёт.
+func (r *Report) Markdown(dir string) []byte {
+	var b strings.Builder
+	i18n.Fprintf(&b, "# Собеседование %s\n\n", r.Started.Format("2006-01-02 15:04"))
+	i18n.Fprintf(&b, "- Состав: `%s`\n", FormatMix(r.Mix))
+	i18n.Fprintf(&b, "- Время: %s из %s\n", r.Spent.Round(time.Second), r.Duration)
+	i18n.Fprintf(&b, "- Решено: %d из %d, неверно: %d, пропущено: %d, не начато: %d\n\n",
+		r.Count(OutcomePassed), len(r.Entries), r.Count(OutcomeFailed), r.Count(OutcomeSkipped), r.Count(OutcomeNotStarted))
+
+	b.WriteString(i18n.T("| # | Задача | Формат | Результат | Время |\n"))
+	b.WriteString("|---|--------|--------|-----------|-------|\n")
+	for i, e := range r.Entries {
+		fmt.Fprintf(&b, "| %d | [%s](#%d) %s | %s | %s | %s |\n",
+			i+1, e.Key(), i+1, escapeCell(e.Title), e.Format, outcomeText(e), spent(e))
+	}
+
+	for i, e := range r.Entries {
+		fmt.Fprintf(&b, "\n<a id=\"%d\"></a>\n## %d. %s", i+1, i+1, e.Key())
+		if e.Title != "" {
+			fmt.Fprintf(&b, " — %s", e.Title)
+		}
+		b.WriteString("\n\n")
+		i18n.Fprintf(&b, "- Результат: %s\n", outcomeText(e))
+		if e.Outcome != OutcomeNotStarted {
+			i18n.Fprintf(&b, "- Формат: %s, время: %s\n", e.Format, spent(e))
+		}
+		if e.Attempts > 0 {
+			i18n.Fprintf(&b, "- Запусков проверки: %d\n", e.Attempts)
+		}
+		i18n.Fprintf(&b, "- Эталон: %s\n", reference(dir, e.Item))
+		if e.Solution != "" {
+			i18n.Fprintf(&b, "- Решение: [%s](%s)\n", filepath.Base(e.Solution), link(dir, filepath.Join(e.Solution, "main.go")))
+		}
+		if len(e.Answer) > 0 {
+			b.WriteString(i18n.T("\nОтвет:\n\n```text\n"))
+			b.WriteString(strings.Join(e.Answer, "\n"))
+			b.WriteString("\n```\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func outcomeText(e Entry) string {
+	text := i18n.T(map[Outcome]string{
+		OutcomePassed:     "решено",
+		OutcomeFailed:     "неверно",
+		OutcomeSkipped:    "пропущено",
+		OutcomeNotStarted: "не начато",
+	}[e.Outcome])
+	if e.Details != "" {
+		text += fmt.Sprintf(" (%s)", e.Details)
+	}
+	return text
+}
+
+func spent(e Entry) string {
+	if e.Outcome == OutcomeNotStarted {
+		return "-"
+	}
+	return e.Spent.Round(time.Second).String()
+}
+
+// reference — ссылка на эталонное решение; для func-based тем — на строку функции.
+func reference(dir string, it Item) string {
+	file := it.Topic.TaskFile(it.Num)
+	target := link(dir, file)
+	if !it.Topic.IsFileBased {
+		if doc, err := taskdoc.Parse(it.Topic, it.Num); err == nil {
+			target += fmt.Sprintf("#L%d", doc.Pos.Line)
+		}
+	}
+	return fmt.Sprintf("[%s](%s)", filepath.Base(filepath.Dir(file))+"/"+filepath.Base(file), target)
+}
+
+func link(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		rel = path
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), " ", "%20")
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
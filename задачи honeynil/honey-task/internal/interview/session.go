@@ -0,0 +1,292 @@
+package interview
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/meta"
+	"github.com/honeynil/honey-task/internal/practice"
+	"github.com/honeynil/honey-task/internal/quiz"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Format — как задача предлагается на собеседовании.
+type Format string
+
+const (
+	FormatQuiz    Format = "quiz"    // угадать вывод, ответ есть в комментарии
+	FormatVerify  Format = "verify"  // решить в рабочем месте practice и проверить
+	FormatDiscuss Format = "discuss" // ответить текстом: код-ревью, задачи без проверок
+)
+
+// Outcome — итог задачи.
+type Outcome string
+
+const (
+	OutcomePassed     Outcome = "passed"
+	OutcomeFailed     Outcome = "failed"
+	OutcomeSkipped    Outcome = "skipped"
+	OutcomeNotStarted Outcome = "not-started" // сессию завершили раньше
+)
+
+// Entry — результат одной задачи сессии.
+type Entry struct {
+	Item
+	Title    string
+	Format   Format
+	Outcome  Outcome
+	Details  string   // режим или статус проверки
+	Answer   []string // ответ кандидата: предсказанный вывод или текст
+	Solution string   // каталог рабочего места для FormatVerify
+	Attempts int      // запусков verify
+	Spent    time.Duration
+}
+
+// Session проводит собеседование, читая ответы из In и печатая в Out.
+type Session struct {
+	Reg      *registry.Registry
+	In       *bufio.Reader
+	Out      io.Writer
+	Duration time.Duration
+}
+
+// NewSession создаёт сессию поверх in/out.
+func NewSession(reg *registry.Registry, in io.Reader, out io.Writer, d time.Duration) *Session {
+	return &Session{Reg: reg, In: bufio.NewReader(in), Out: out, Duration: d}
+}
+
+// errQuit — пользователь завершил сессию.
+var errQuit = errors.New("quit")
+
+// Run проходит задачи по очереди и возвращает отчёт. Когда время выходит,
+// текущую задачу можно доделать, остальные попадают в отчёт как не начатые.
+func (s *Session) Run(mix []Pick, items []Item) *Report {
+	rep := &Report{Started: time.Now(), Duration: s.Duration, Mix: mix}
+	deadline := rep.Started.Add(s.Duration)
+	stop := s.startTimer(deadline)
+	defer stop()
+
+	i18n.Fprintf(s.Out, "Собеседование: %d задач, %s. На каждом шаге: skip — пропустить задачу, quit — завершить.\n", len(items), s.Duration)
+
+	done := false
+	for i, it := range items {
+		e := Entry{Item: it, Outcome: OutcomeNotStarted}
+		if doc, err := taskdoc.Parse(it.Topic, it.Num); err == nil {
+			e.Title = meta.CleanTitle(doc.Title)
+		}
+		if done {
+			rep.Entries = append(rep.Entries, e)
+			continue
+		}
+
+		left := time.Until(deadline)
+		i18n.Fprintf(s.Out, "\n%s\nЗадача %d из %d: %s — осталось %s", rule, i+1, len(items), it.Key(), clock(left))
+		if per := left / time.Duration(len(items)-i); per > 0 {
+			i18n.Fprintf(s.Out, " (~%s на задачу)", clock(per))
+		}
+		fmt.Fprintf(s.Out, "\n%s\n", rule)
+
+		start := time.Now()
+		err := s.present(&e)
+		e.Spent = time.Since(start)
+		rep.Entries = append(rep.Entries, e)
+
+		switch {
+		case errors.Is(err, errQuit):
+			done = true
+		case err != nil:
+			fmt.Fprintf(s.Out, "%s: %v\n", it.Key(), err)
+		}
+		if !done && time.Now().After(deadline) && i < len(items)-1 {
+			i18n.Fprintln(s.Out, "\nВремя вышло, оставшиеся задачи не начаты.")
+			done = true
+		}
+	}
+	rep.Spent = time.Since(rep.Started)
+	return rep
+}
+
+// present выбирает формат задачи и проводит её. Угадывание вывода
+// предпочтительнее, затем решение с проверкой, затем ответ текстом.
+func (s *Session) present(e *Entry) error {
+	if _, err := quiz.Prepare(s.Reg, e.Topic, e.Num); err == nil {
+		e.Format = FormatQuiz
+		return s.runQuiz(e)
+	}
+	// Без тестов и самопроверок "решить" задачу нечем проверить — её обсуждают.
+	if e.Topic.IsFileBased && practice.CheckMode(e.Topic, e.Num) != practice.ModeRun {
+		dir, err := practice.Create(s.Reg.Root, e.Topic, e.Num)
+		if err == nil || errors.Is(err, practice.ErrExists) {
+			e.Format, e.Solution = FormatVerify, dir
+			return s.runVerify(e, err != nil)
+		}
+	}
+	e.Format = FormatDiscuss
+	return s.runDiscuss(e)
+}
+
+func (s *Session) runQuiz(e *Entry) error {
+	q := &quiz.Session{Reg: s.Reg, In: s.In, Out: s.Out}
+	res, quit, err := q.Ask(e.Topic, e.Num)
+	if err != nil {
+		return err
+	}
+	switch {
+	case quit:
+		e.Outcome = OutcomeSkipped
+		return errQuit
+	case res.Skipped:
+		e.Outcome = OutcomeSkipped
+		return nil
+	}
+	e.Answer = res.Prediction
+	switch {
+	case res.Correct:
+		e.Outcome = OutcomePassed
+	default:
+		e.Outcome = OutcomeFailed
+	}
+	return nil
+}
+
+func (s *Session) runVerify(e *Entry, existed bool) error {
+	rel, _ := filepath.Rel(s.Reg.Root, e.Solution)
+	if existed {
+		i18n.Fprintf(s.Out, "Рабочее место уже было создано раньше: %s\n", rel)
+	}
+	if doc, err := taskdoc.Parse(e.Topic, e.Num); err == nil {
+		fmt.Fprintln(s.Out, doc.Text)
+	}
+	i18n.Fprintf(s.Out, "Реализуйте решение в %s.\n", filepath.Join(rel, "main.go"))
+
+	e.Outcome = OutcomeSkipped
+	for {
+		fmt.Fprint(s.Out, i18n.T("Enter — проверить, skip — пропустить, quit — завершить: "))
+		line, eof := s.readLine()
+		switch {
+		case line == "quit", eof:
+			return errQuit
+		case line == "skip":
+			return nil
+		}
+
+		e.Attempts++
+		v, err := practice.Verify(s.Reg.Root, e.Topic, e.Num, s.Out, s.Out)
+		if err != nil {
+			return err
+		}
+		e.Details = string(v.Mode)
+		if v.Passed() {
+			e.Outcome = OutcomePassed
+			fmt.Fprintf(s.Out, "\nPASS (%s)\n", v.Mode)
+			return nil
+		}
+		e.Outcome = OutcomeFailed
+		e.Details = fmt.Sprintf("%s: %s", v.Mode, v.Result.Status)
+		fmt.Fprintf(s.Out, "\nFAIL [%s] %s\n", v.Result.Status, v.Result.Message)
+	}
+}
+
+func (s *Session) runDiscuss(e *Entry) error {
+	if e.Topic.IsFileBased {
+		src, err := os.ReadFile(e.Topic.TaskFile(e.Num))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(s.Out, string(src))
+	} else {
+		if doc, err := taskdoc.Parse(e.Topic, e.Num); err == nil {
+			fmt.Fprintln(s.Out, doc.Text)
+		}
+		src, err := taskdoc.Source(e.Topic, e.Num)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(s.Out, src)
+	}
+	i18n.Fprintln(s.Out, "Напишите ответ построчно и завершите строкой \".\" (skip — пропустить, quit — завершить)")
+
+	e.Outcome = OutcomeSkipped
+	var eof bool
+	for {
+		var line string
+		line, eof = s.readLine()
+		if len(e.Answer) == 0 {
+			switch line {
+			case "quit":
+				return errQuit
+			case "skip":
+				return nil
+			}
+		}
+		if line == "." || eof {
+			if line != "." && line != "" {
+				e.Answer = append(e.Answer, line)
+			}
+			break
+		}
+		e.Answer = append(e.Answer, line)
+	}
+	switch {
+	case len(e.Answer) == 0 && eof:
+		return errQuit
+	case len(e.Answer) == 0:
+		return nil
+	}
+
+	// Автоматической проверки нет — кандидат оценивает себя по эталону.
+	i18n.Fprintf(s.Out, "\nЭталон: %s\nЗасчитать ответ? [y/N] ", e.Topic.TaskFile(e.Num))
+	line, _ := s.readLine()
+	e.Outcome = OutcomeFailed
+	if strings.EqualFold(line, "y") {
+		e.Outcome = OutcomePassed
+	}
+	return nil
+}
+
+// readLine читает строку без перевода строки; eof — ввод закончился.
+func (s *Session) readLine() (string, bool) {
+	line, err := s.In.ReadString('\n')
+	return strings.TrimSpace(line), err != nil
+}
+
+// startTimer предупреждает за пять минут до конца и по истечении времени.
+func (s *Session) startTimer(deadline time.Time) (stop func()) {
+	var timers []*time.Timer
+	if warn := time.Until(deadline) - 5*time.Minute; warn > 0 {
+		timers = append(timers, time.AfterFunc(warn, func() {
+			i18n.Fprintln(s.Out, "\n[осталось 5 минут]")
+		}))
+	}
+	timers = append(timers, time.AfterFunc(time.Until(deadline), func() {
+		i18n.Fprintln(s.Out, "\n[время вышло — завершите текущую задачу]")
+	}))
+	return func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}
+}
+
+const rule = "=================================================="
+
+// clock форматирует длительность как мм:сс (или ч:мм:сс).
+func clock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%02d:%02d", m, sec)
+}
@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/scaffold"
+)
+
+// buildErrRe — строка ошибки компилятора: file.go:line:col: сообщение.
+var buildErrRe = regexp.MustCompile(`^(.+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// build собирает каждую file-based задачу как отдельную программу. Задачи
+// код-ревью не собираются: их код — материал для ревью, часто с внешними
+// зависимостями и намеренными ошибками.
+func (l *linter) build() {
+	var pkgs []string
+	for _, topic := range l.reg.Topics() {
+		if !topic.IsFileBased || scaffold.DefaultKind(topic) == scaffold.Review {
+			continue
+		}
+		for _, num := range topic.Numbers {
+			rel, err := filepath.Rel(l.reg.Root, topic.TaskDir(num))
+			if err != nil {
+				continue
+			}
+			pkgs = append(pkgs, "./"+filepath.ToSlash(rel))
+		}
+	}
+	if len(pkgs) == 0 {
+		return
+	}
+
+	// Несколько пакетов за один вызов go build компилируются по отдельности,
+	// а результат сборки отбрасывается.
+	cmd := exec.Command("go", append([]string{"build"}, pkgs...)...)
+	cmd.Dir = l.reg.Root
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return
+	}
+
+	reported := false
+	for _, line := range strings.Split(string(out), "\n") {
+		m := buildErrRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		pos := token.Position{Filename: filepath.Join(l.reg.Root, m[1])}
+		pos.Line, _ = strconv.Atoi(m[2])
+		pos.Column, _ = strconv.Atoi(m[3])
+		l.report(pos, "не собирается: %s", m[4])
+		reported = true
+	}
+	if !reported {
+		l.report(token.Position{Filename: l.reg.Root}, "go build: %s", strings.TrimSpace(string(out)))
+	}
+}
@@ -0,0 +1,201 @@
+// Package lint проверяет соглашения оформления задач: заголовки, блоки
+// ответов, нумерацию, ссылки на README, сборку file-based задач и, если
+// выбран не русский язык, переводы.
+// Замечания выдаются с позицией file:line, как у компилятора.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/scaffold"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Diagnostic — замечание линтера.
+type Diagnostic struct {
+	Pos token.Position // Filename относительно корня модуля
+	Msg string
+}
+
+func (d Diagnostic) String() string {
+	return d.Pos.String() + ": " + d.Msg
+}
+
+var (
+	// headerRe — допустимые заголовки: "ЗАДАЧА N: ...", "Задача: ...", "ТЗ: ...".
+	headerRe = regexp.MustCompile(`^(?:ЗАДАЧА\s+(\d+)\s*:|Задача\s*:|ТЗ\s*:)\s*\S`)
+	// legacyRe — старый формат "Задача N – ...", который заменён на "ЗАДАЧА N: ...".
+	legacyRe = regexp.MustCompile(`^Задача\s+\d+\s*[–—-]`)
+	// specRe — строка ТЗ в комментарии код-ревью.
+	specRe = regexp.MustCompile(`(?m)^(?://|/\*)?\s*ТЗ\s*:`)
+)
+
+type linter struct {
+	reg   *registry.Registry
+	diags []Diagnostic
+}
+
+func (l *linter) report(pos token.Position, format string, args ...any) {
+	l.diags = append(l.diags, Diagnostic{Pos: pos, Msg: i18n.Sprintf(format, args...)})
+}
+
+// Run проверяет все темы реестра и возвращает замечания, отсортированные
+// по файлу и строке.
+func Run(reg *registry.Registry) []Diagnostic {
+	l := &linter{reg: reg}
+	for _, err := range reg.ParseErrors {
+		if !l.unparsed(err) {
+			l.report(token.Position{}, "%v", err)
+		}
+	}
+	for _, topic := range reg.Topics() {
+		if pos, err := scaffold.CheckNumbering(topic); err != nil {
+			l.report(pos, "%v", err)
+		}
+		for _, num := range topic.Numbers {
+			if scaffold.DefaultKind(topic) == scaffold.Review {
+				l.review(topic, num)
+			} else {
+				l.task(topic, num)
+			}
+		}
+	}
+	l.readmes()
+	l.build()
+
+	for i := range l.diags {
+		pos := &l.diags[i].Pos
+		if rel, err := filepath.Rel(reg.Root, pos.Filename); err == nil && filepath.IsAbs(pos.Filename) {
+			pos.Filename = rel
+		}
+	}
+	sort.SliceStable(l.diags, func(i, j int) bool {
+		a, b := l.diags[i].Pos, l.diags[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return l.diags
+}
+
+// task проверяет заголовок задачи и, для "Что выведет?", блок ответа.
+func (l *linter) task(topic registry.Topic, num int) {
+	file := topic.TaskFile(num)
+	doc, err := taskdoc.Parse(topic, num)
+	if err != nil {
+		if !l.unparsed(err) {
+			l.report(token.Position{Filename: file}, "нет комментария с заголовком задачи %d", num)
+		}
+		return
+	}
+
+	switch m := headerRe.FindStringSubmatch(doc.Title); {
+	case legacyRe.MatchString(doc.Title):
+		l.report(doc.Pos, "заголовок в старом формате %q, используйте «ЗАДАЧА %d: ...»", doc.Title, num)
+	case m == nil:
+		l.report(doc.Pos, "заголовок не распознан: %q (ожидается «ЗАДАЧА N: ...», «Задача: ...» или «ТЗ: ...»)", doc.Title)
+	case m[1] == "" && !topic.IsFileBased:
+		l.report(doc.Pos, "у задачи func-based темы заголовок должен быть «ЗАДАЧА %d: ...»", num)
+	case m[1] != "" && m[1] != strconv.Itoa(num):
+		l.report(doc.Pos, "в заголовке номер %s, а задача %d", m[1], num)
+	}
+
+	l.translation(doc)
+
+	// "Что выведет?": все задачи func-based тем и file-based задачи с блоком ответа.
+	if topic.IsFileBased && len(doc.Answers) == 0 {
+		return
+	}
+	if len(doc.Answers) == 0 {
+		l.report(doc.Pos, "нет блока OUTPUT с ожидаемым выводом")
+	}
+	for _, a := range doc.Answers {
+		if a.Blank() {
+			l.report(doc.Pos, "пустой блок ответа: после OUTPUT/ОТВЕТ нет ни строк вывода, ни (ничего)")
+		}
+	}
+}
+
+// unparsed сообщает об ошибке разбора исходника с позицией первой ошибки.
+// false — err не ошибка разбора.
+func (l *linter) unparsed(err error) bool {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return false
+	}
+	l.report(list[0].Pos, "не разбирается: %s", list[0].Msg)
+	return true
+}
+
+// translation проверяет, что у задачи есть перевод на язык сообщений,
+// если это не русский: заголовок, а у "Что выведет?" и объяснение.
+func (l *linter) translation(doc *taskdoc.Doc) {
+	lang := i18n.Current()
+	if lang == i18n.Default {
+		return
+	}
+	tr := doc.Translations[lang]
+	var missing []string
+	if tr.Title == "" {
+		missing = append(missing, fmt.Sprintf("TITLE (%s)", lang))
+	}
+	if len(doc.Answers) > 0 && tr.Explanation == "" {
+		missing = append(missing, fmt.Sprintf("EXPLANATION (%s)", lang))
+	}
+	if len(missing) > 0 {
+		l.report(doc.Pos, "нет перевода на %s: добавьте %s", lang, strings.Join(missing, ", "))
+	}
+}
+
+// review проверяет задачу код-ревью. Её код намеренно с ошибками и может
+// даже не разбираться, поэтому ТЗ ищется сканером по комментариям файла.
+func (l *linter) review(topic registry.Topic, num int) {
+	file := topic.TaskFile(num)
+	src, err := os.ReadFile(file)
+	if err != nil {
+		l.report(token.Position{Filename: file}, "%v", err)
+		return
+	}
+	found := false
+	scanTokens(file, src, func(pos token.Position, tok token.Token, lit string) {
+		if tok == token.COMMENT && specRe.MatchString(lit) {
+			found = true
+		}
+	})
+	if !found {
+		l.report(token.Position{Filename: file}, "нет комментария «ТЗ: ...» с условием код-ревью")
+	}
+}
+
+// scanTokens проходит по токенам файла, не требуя, чтобы он разбирался
+// парсером; ошибки сканирования пропускаются.
+func scanTokens(file string, src []byte, fn func(token.Position, token.Token, string)) {
+	fset := token.NewFileSet()
+	f := fset.AddFile(file, fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(f, src, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return
+		}
+		fn(fset.Position(pos), tok, lit)
+	}
+}
+
+// skipDir — каталоги, которые не относятся к задачам и раннеру.
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata"
+}
@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// readmeRe — ссылка на README-файл, с каталогом или без.
+var readmeRe = regexp.MustCompile(`(?:[\p{L}\w.-]+/)*README\.md`)
+
+// readmes ищет в комментариях и строках .go-файлов ссылки на README
+// и проверяет, что файлы существуют. Путь считается от каталога файла.
+// В строках учитываются только пути с каталогом и без шаблонов %s: голое
+// имя файла в коде — это имя, а не ссылка.
+func (l *linter) readmes() {
+	filepath.WalkDir(l.reg.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != l.reg.Root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		scanTokens(path, src, func(pos token.Position, tok token.Token, lit string) {
+			if tok != token.COMMENT && tok != token.STRING {
+				return
+			}
+			for _, loc := range readmeRe.FindAllStringIndex(lit, -1) {
+				ref := lit[loc[0]:loc[1]]
+				if tok == token.STRING && (!strings.Contains(ref, "/") || strings.Contains(lit, "%")) {
+					continue
+				}
+				if _, err := os.Stat(filepath.Join(filepath.Dir(path), ref)); err == nil {
+					continue
+				}
+				at := pos
+				at.Line += strings.Count(lit[:loc[0]], "\n")
+				l.report(at, "ссылка на несуществующий файл %s", ref)
+			}
+		})
+		return nil
+	})
+}
@@ -0,0 +1,243 @@
+// Package meta собирает метаданные задач: заголовок, сложность, теги,
+// источник и оценку времени. Они берутся из нормализованных строк в
+// комментарии задачи, из файла meta.json темы и из анализа исходника.
+//
+// Формат строк в комментарии (ключи можно писать и по-английски):
+//
+//	// Сложность: средняя
+//	// Теги: channels, context, nil-interface
+//	// Источник: собеседование в Авито
+//	// Время: 15m
+//
+// meta.json лежит в каталоге темы и описывает задачи по номерам:
+//
+//	{"3": {"difficulty": "hard", "tags": ["escape-analysis"], "time": "20m"}}
+//
+// Подсказки пишутся в комментарии задачи строками "// HINT 1: ..." или
+// списком "hints" в meta.json; список из meta.json заменяет подсказки из кода.
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Уровни сложности.
+const (
+	Easy   = "easy"
+	Medium = "medium"
+	Hard   = "hard"
+)
+
+// SidecarFile — файл с метаданными задач в каталоге темы.
+const SidecarFile = "meta.json"
+
+// Meta — метаданные задачи.
+type Meta struct {
+	Topic      string
+	Num        int
+	Title      string
+	Difficulty string        // easy, medium, hard или ""
+	Tags       []string      // отсортированы, без повторов
+	Source     string        // откуда задача: компания, книга, ссылка
+	Estimate   time.Duration // оценка времени на решение, 0 — не задана
+	Text       string        // полный комментарий задачи, для поиска
+	Hints      []string      // подсказки по порядку, от общей к конкретной
+}
+
+// Key — "topic/num", как в прогрессе и выводе раннера.
+func (m Meta) Key() string {
+	return fmt.Sprintf("%s/%d", m.Topic, m.Num)
+}
+
+// sidecarEntry — запись meta.json.
+type sidecarEntry struct {
+	Title      string   `json:"title"`
+	Difficulty string   `json:"difficulty"`
+	Tags       []string `json:"tags"`
+	Source     string   `json:"source"`
+	Time       string   `json:"time"`
+	Hints      []string `json:"hints"`
+}
+
+var (
+	headerRe = regexp.MustCompile(`(?i)^\s*(сложность|difficulty|теги|tags|источник|source|время|time)\s*:\s*(.+?)\s*$`)
+	// titleRe снимает с заголовка префиксы вида "Задача:", "ЗАДАЧА 13:", "Задача 1 –", "ТЗ:".
+	titleRe = regexp.MustCompile(`(?i)^(задача|тз)\s*\d*\s*[:.–—-]?\s*`)
+)
+
+// Load собирает метаданные всех задач темы.
+func Load(topic registry.Topic) ([]Meta, error) {
+	sidecar, err := loadSidecar(topic.Dir)
+	if err != nil {
+		return nil, err
+	}
+	src := newSourceIndex(topic)
+
+	out := make([]Meta, 0, len(topic.Numbers))
+	for _, num := range topic.Numbers {
+		m := Meta{Topic: topic.Name, Num: num}
+		// Задача без комментария всё равно попадает в поиск — по тегам из кода.
+		if doc, err := taskdoc.Parse(topic, num); err == nil {
+			// Переводы попадают в текст для поиска, заголовок — на языке сообщений.
+			m.Text = doc.Text
+			for _, tr := range doc.Translations {
+				m.Text += "\n" + tr.Title + "\n" + tr.Explanation
+			}
+			m.Title = CleanTitle(doc.TitleIn(i18n.Current()))
+			m.Hints = doc.Hints
+			applyHeader(&m, doc.Text)
+		}
+		if e, ok := sidecar[num]; ok {
+			if err := applySidecar(&m, e); err != nil {
+				return nil, fmt.Errorf("%s: задача %d: %w", filepath.Join(topic.Dir, SidecarFile), num, err)
+			}
+		}
+		m.Tags = append(m.Tags, src.tags(num)...)
+		m.Tags = normalizeTags(m.Tags)
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func loadSidecar(dir string) (map[int]sidecarEntry, error) {
+	path := filepath.Join(dir, SidecarFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]sidecarEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	out := make(map[int]sidecarEntry, len(raw))
+	for k, e := range raw {
+		num, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("%s: ключ %q — не номер задачи", path, k)
+		}
+		out[num] = e
+	}
+	return out, nil
+}
+
+// CleanTitle убирает из первой строки комментария служебный префикс
+// ("Задача:", "ЗАДАЧА 13:", "ТЗ:") и точку в конце.
+func CleanTitle(title string) string {
+	title = strings.TrimSpace(titleRe.ReplaceAllString(title, ""))
+	return strings.TrimSuffix(title, ".")
+}
+
+// applyHeader разбирает нормализованные строки "Ключ: значение" комментария.
+func applyHeader(m *Meta, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		match := headerRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		// Ошибки разбора здесь не фатальны: это свободный текст задачи.
+		applyField(m, strings.ToLower(match[1]), match[2])
+	}
+}
+
+func applySidecar(m *Meta, e sidecarEntry) error {
+	if e.Title != "" {
+		m.Title = e.Title
+	}
+	if e.Source != "" {
+		m.Source = e.Source
+	}
+	if len(e.Hints) > 0 {
+		m.Hints = e.Hints
+	}
+	m.Tags = append(m.Tags, e.Tags...)
+	if e.Difficulty != "" {
+		if err := applyField(m, "difficulty", e.Difficulty); err != nil {
+			return err
+		}
+	}
+	if e.Time != "" {
+		return applyField(m, "time", e.Time)
+	}
+	return nil
+}
+
+func applyField(m *Meta, key, value string) error {
+	switch key {
+	case "сложность", "difficulty":
+		d, ok := parseDifficulty(value)
+		if !ok {
+			return fmt.Errorf("неизвестная сложность %q", value)
+		}
+		m.Difficulty = d
+	case "теги", "tags":
+		m.Tags = append(m.Tags, strings.Split(value, ",")...)
+	case "источник", "source":
+		m.Source = value
+	case "время", "time":
+		d, err := parseEstimate(value)
+		if err != nil {
+			return err
+		}
+		m.Estimate = d
+	}
+	return nil
+}
+
+func parseDifficulty(s string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case Easy, "лёгкая", "легкая", "простая":
+		return Easy, true
+	case Medium, "средняя":
+		return Medium, true
+	case Hard, "сложная", "трудная":
+		return Hard, true
+	}
+	return "", false
+}
+
+// parseEstimate понимает длительности Go ("15m", "1h30m") и число минут ("15", "15 мин").
+func parseEstimate(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(s, "мин")))
+	if err != nil {
+		return 0, fmt.Errorf("не удалось разобрать время %q", s)
+	}
+	return time.Duration(n) * time.Minute, nil
+}
+
+// normalizeTags приводит теги к нижнему регистру, убирает пустые и повторы.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := tags[:0]
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		t = strings.ReplaceAll(t, " ", "-")
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,104 @@
+package meta
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Meta
+	}{
+		{
+			name: "russian keys",
+			text: "ЗАДАЧА 1: Каналы\nСложность: средняя\nТеги: channels, context\nИсточник: собеседование\nВремя: 15 мин",
+			want: Meta{Difficulty: Medium, Tags: []string{"channels", " context"}, Source: "собеседование", Estimate: 15 * time.Minute},
+		},
+		{
+			name: "english keys in any case",
+			text: "Difficulty: HARD\nTAGS: maps\nsource: book  \n  Time: 1h30m",
+			want: Meta{Difficulty: Hard, Tags: []string{"maps"}, Source: "book", Estimate: 90 * time.Minute},
+		},
+		{
+			name: "invalid values are ignored",
+			text: "Сложность: невозможная\nВремя: скоро",
+			want: Meta{},
+		},
+		{
+			name: "key must start the line",
+			text: "Подсказка: сложность: лёгкая\nНе теги: x",
+			want: Meta{},
+		},
+		{
+			name: "empty value",
+			text: "Источник:\nВремя:",
+			want: Meta{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var m Meta
+			applyHeader(&m, tc.text)
+			if m.Difficulty != tc.want.Difficulty || m.Source != tc.want.Source || m.Estimate != tc.want.Estimate || !slices.Equal(m.Tags, tc.want.Tags) {
+				t.Fatalf("applyHeader = %+v, want %+v", m, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "15m", want: 15 * time.Minute},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: " 45s ", want: 45 * time.Second},
+		{in: "15", want: 15 * time.Minute},
+		{in: "15 мин", want: 15 * time.Minute},
+		{in: "15мин", want: 15 * time.Minute},
+		{in: "", wantErr: true},
+		{in: "мин", wantErr: true},
+		{in: "15 минут", wantErr: true},
+		{in: "полчаса", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := parseEstimate(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("parseEstimate(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Fatalf("parseEstimate(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"ЗАДАЧА 13: Что выведет?":   "Что выведет?",
+		"Задача: Кэш с TTL.":        "Кэш с TTL",
+		"Задача 1 – Пул воркеров":   "Пул воркеров",
+		"ТЗ: rate limiter":          "rate limiter",
+		"Задачка про каналы":        "Задачка про каналы",
+		"  Merge sort без задачи. ": "Merge sort без задачи",
+	}
+	for in, want := range tests {
+		if got := CleanTitle(in); got != want {
+			t.Errorf("CleanTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := normalizeTags([]string{" Channels", "nil interface", "", "channels", "context "})
+	want := []string{"channels", "context", "nil-interface"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("normalizeTags = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package meta
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Query — разобранный поисковый запрос. Все условия должны выполняться
+// одновременно.
+type Query struct {
+	Tags       []string // tag:x — точное совпадение тега
+	Topics     []string // topic:x
+	Difficulty string   // difficulty:x или сложность:x
+	Terms      []string // остальные слова ищутся в заголовке, тексте, тегах и теме
+}
+
+// ParseQuery разбирает аргументы команды search.
+func ParseQuery(args []string) Query {
+	var q Query
+	for _, arg := range args {
+		for _, word := range strings.Fields(strings.ToLower(arg)) {
+			key, value, ok := strings.Cut(word, ":")
+			if !ok || value == "" {
+				q.Terms = append(q.Terms, word)
+				continue
+			}
+			switch key {
+			case "tag", "тег":
+				q.Tags = append(q.Tags, value)
+			case "topic", "тема":
+				q.Topics = append(q.Topics, value)
+			case "difficulty", "сложность":
+				if d, ok := parseDifficulty(value); ok {
+					value = d
+				}
+				q.Difficulty = value
+			default:
+				q.Terms = append(q.Terms, word)
+			}
+		}
+	}
+	return q
+}
+
+// Match сообщает, подходит ли задача под запрос.
+func (q Query) Match(m Meta) bool {
+	for _, t := range q.Tags {
+		if !slices.Contains(m.Tags, t) {
+			return false
+		}
+	}
+	if len(q.Topics) > 0 && !slices.Contains(q.Topics, m.Topic) {
+		return false
+	}
+	if q.Difficulty != "" && m.Difficulty != q.Difficulty {
+		return false
+	}
+	if len(q.Terms) == 0 {
+		return true
+	}
+	haystack := strings.ToLower(strings.Join([]string{m.Topic, m.Title, m.Source, m.Text, strings.Join(m.Tags, " ")}, "\n"))
+	for _, term := range q.Terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Search возвращает задачи, подходящие под запрос, в исходном порядке.
+func Search(all []Meta, q Query) []Meta {
+	var out []Meta
+	for _, m := range all {
+		if q.Match(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// TagCount — тег и число задач с ним.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// Tags возвращает все теги задач, самые частые первыми.
+func Tags(all []Meta) []TagCount {
+	counts := make(map[string]int)
+	for _, m := range all {
+		for _, t := range m.Tags {
+			counts[t]++
+		}
+	}
+	out := make([]TagCount, 0, len(counts))
+	for t, n := range counts {
+		out = append(out, TagCount{t, n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
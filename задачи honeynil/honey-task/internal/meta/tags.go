@@ -0,0 +1,162 @@
+package meta
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// packageTags — пакеты стандартной библиотеки, использование которых
+// само по себе говорит о теме задачи.
+var packageTags = map[string]string{
+	"context":        "context",
+	"sync":           "sync",
+	"sync/atomic":    "atomic",
+	"encoding/json":  "json",
+	"reflect":        "reflect",
+	"unsafe":         "unsafe",
+	"errors":         "errors",
+	"net/http":       "http",
+	"database/sql":   "sql",
+	"container/heap": "heap",
+	"runtime":        "runtime",
+	"iter":           "iterators",
+	"container/list": "list",
+}
+
+// selectorTags — конкретные функции и типы пакетов.
+var selectorTags = map[string]string{
+	"sync.Mutex":           "mutex",
+	"sync.RWMutex":         "mutex",
+	"sync.WaitGroup":       "waitgroup",
+	"sync.Once":            "once",
+	"sync.Pool":            "pool",
+	"sync.Cond":            "cond",
+	"context.WithCancel":   "cancellation",
+	"context.WithTimeout":  "cancellation",
+	"context.WithDeadline": "cancellation",
+	"time.After":           "timers",
+	"time.NewTimer":        "timers",
+	"time.NewTicker":       "timers",
+	"time.Tick":            "timers",
+}
+
+// sourceIndex выводит теги из исходников темы. Файл разбирается один раз:
+// у func-based тем все задачи лежат в одном tasks.go.
+type sourceIndex struct {
+	topic registry.Topic
+	files map[string]*ast.File
+}
+
+func newSourceIndex(topic registry.Topic) *sourceIndex {
+	return &sourceIndex{topic: topic, files: make(map[string]*ast.File)}
+}
+
+func (s *sourceIndex) file(name string) *ast.File {
+	if f, ok := s.files[name]; ok {
+		return f
+	}
+	// Файл с ошибкой разбора просто не даёт тегов.
+	f, _ := parser.ParseFile(token.NewFileSet(), name, nil, parser.SkipObjectResolution)
+	s.files[name] = f
+	return f
+}
+
+// tags возвращает теги задачи num. Для file-based тем анализируется весь
+// main.go, для func-based — только функция taskN.
+func (s *sourceIndex) tags(num int) []string {
+	f := s.file(s.topic.TaskFile(num))
+	if f == nil {
+		return nil
+	}
+
+	var scope ast.Node = f
+	if !s.topic.IsFileBased {
+		scope = nil
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == s.topic.TaskFunc(num) {
+				scope = fn
+			}
+		}
+		if scope == nil {
+			return nil
+		}
+	}
+
+	imports := make(map[string]string) // имя в файле -> путь
+	for _, spec := range f.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = p
+	}
+
+	var tags []string
+	ast.Inspect(scope, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if id, ok := n.X.(*ast.Ident); ok {
+				if p, ok := imports[id.Name]; ok {
+					if t, ok := packageTags[p]; ok {
+						tags = append(tags, t)
+					}
+					if t, ok := selectorTags[p+"."+n.Sel.Name]; ok {
+						tags = append(tags, t)
+					}
+				}
+			}
+		case *ast.GoStmt:
+			tags = append(tags, "goroutines")
+		case *ast.ChanType, *ast.SendStmt:
+			tags = append(tags, "channels")
+		case *ast.UnaryExpr:
+			switch n.Op {
+			case token.ARROW:
+				tags = append(tags, "channels")
+			case token.AND:
+				tags = append(tags, "pointers")
+			}
+		case *ast.SelectStmt:
+			tags = append(tags, "select")
+		case *ast.DeferStmt:
+			tags = append(tags, "defer")
+		case *ast.CallExpr:
+			if id, ok := n.Fun.(*ast.Ident); ok {
+				switch id.Name {
+				case "recover":
+					// panic не считаем: им написаны самопроверки почти всех задач.
+					tags = append(tags, "recover")
+				case "append":
+					tags = append(tags, "slices")
+				}
+			}
+		case *ast.FuncType:
+			if n.TypeParams != nil {
+				tags = append(tags, "generics")
+			}
+		case *ast.TypeSpec:
+			if n.TypeParams != nil {
+				tags = append(tags, "generics")
+			}
+		case *ast.InterfaceType:
+			// interface{} в сигнатурах — ещё не задача про интерфейсы.
+			if len(n.Methods.List) > 0 {
+				tags = append(tags, "interfaces")
+			}
+		case *ast.TypeAssertExpr, *ast.TypeSwitchStmt:
+			tags = append(tags, "type-assertion")
+		case *ast.MapType:
+			tags = append(tags, "maps")
+		case *ast.FuncLit:
+			tags = append(tags, "closures")
+		}
+		return true
+	})
+	return tags
+}
@@ -0,0 +1,136 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Элементы JUnit XML в том виде, который понимают Jenkins, GitLab и GitHub Actions.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       float64         `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+	SystemErr *junitOutput  `xml:"system-err,omitempty"`
+}
+
+// junitOutput — вывод задачи в CDATA, чтобы переводы строк остались читаемыми.
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+func newOutput(s string) *junitOutput {
+	if s == "" {
+		return nil
+	}
+	return &junitOutput{s}
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit пишет отчёт как JUnit XML: тема — testsuite, задача — testcase
+// с именем taskNNN, чтобы дашборды сортировали задачи по номеру.
+func WriteJUnit(w io.Writer, r *Report) error {
+	doc := junitSuites{Name: "honey-task"}
+	index := map[string]int{}
+	for _, t := range r.Tasks {
+		i, ok := index[t.Topic]
+		if !ok {
+			i = len(doc.Suites)
+			index[t.Topic] = i
+			doc.Suites = append(doc.Suites, junitSuite{
+				Name: t.Topic,
+				Properties: []junitProperty{
+					{Name: "command", Value: r.Command},
+					{Name: "go.version", Value: r.GoVersion},
+				},
+			})
+		}
+		s := &doc.Suites[i]
+
+		c := junitCase{
+			Name:      fmt.Sprintf("task%03d", t.Num),
+			Classname: "honey-task." + t.Topic,
+			Time:      t.Duration,
+			SystemOut: newOutput(t.Stdout),
+			SystemErr: newOutput(t.Stderr),
+		}
+		msg := &junitMessage{Message: t.Message, Type: string(t.Status), Text: t.Message}
+		switch t.Verdict {
+		case Fail:
+			c.Failure = msg
+			s.Failures++
+		case Error:
+			c.Error = msg
+			s.Errors++
+		case Skip:
+			c.Skipped = &junitMessage{Message: t.Message}
+			s.Skipped++
+		}
+		s.Tests++
+		s.Time += t.Duration
+		s.Cases = append(s.Cases, c)
+	}
+	for i := range doc.Suites {
+		s := &doc.Suites[i]
+		s.Time = roundMillis(s.Time)
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Skipped += s.Skipped
+		doc.Time += s.Time
+	}
+	doc.Time = roundMillis(doc.Time)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// roundMillis убирает хвосты сложения float вроде 0.30000000000000004.
+func roundMillis(sec float64) float64 {
+	return math.Round(sec*1000) / 1000
+}
@@ -0,0 +1,134 @@
+// Package output — машиночитаемый вывод команд раннера (флаг --format):
+// JSON с записью на каждую задачу и JUnit XML для CI-дашбордов.
+// Текстовый формат печатает сам main, здесь его нет.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/runner"
+)
+
+// Format — формат вывода команды.
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	JUnit Format = "junit"
+)
+
+// ParseFormat проверяет значение флага --format.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case Text, JSON, JUnit:
+		return f, nil
+	}
+	return "", fmt.Errorf("неизвестный формат %q: ожидается text, json или junit", s)
+}
+
+// Verdict — итог задачи для отчёта.
+type Verdict string
+
+const (
+	Pass  Verdict = "pass"
+	Fail  Verdict = "fail"
+	Skip  Verdict = "skip"
+	Error Verdict = "error" // задачу не удалось даже запустить
+)
+
+// Record — запись о задаче.
+type Record struct {
+	Topic    string        `json:"topic"`
+	Num      int           `json:"num"`
+	Duration float64       `json:"duration"` // секунды
+	Status   runner.Status `json:"status,omitempty"`
+	ExitCode int           `json:"exit_code"`
+	Stdout   string        `json:"stdout"`
+	Stderr   string        `json:"stderr"`
+	Verdict  Verdict       `json:"verdict"`
+	Message  string        `json:"message,omitempty"` // причина провала или пропуска
+}
+
+// FromResult строит запись по результату запуска: задача прошла, если
+// процесс завершился без паник, таймаутов и гонок.
+func FromResult(topic string, num int, res runner.Result) Record {
+	r := Record{
+		Topic:    topic,
+		Num:      num,
+		Duration: Seconds(res.Duration),
+		Status:   res.Status,
+		ExitCode: res.ExitCode,
+		Stdout:   res.Stdout,
+		Stderr:   res.Stderr,
+		Verdict:  Pass,
+		Message:  res.Message,
+	}
+	if res.Status != runner.StatusOK || len(res.Races) > 0 {
+		r.Verdict = Fail
+	}
+	return r
+}
+
+// Seconds переводит длительность в секунды с точностью до миллисекунды.
+func Seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
+
+// Report — отчёт команды о прогоне задач.
+type Report struct {
+	Command   string   `json:"command"`
+	GoVersion string   `json:"go_version"` // чтобы сравнивать снапшоты между версиями Go
+	Tasks     []Record `json:"tasks"`
+}
+
+// NewReport создаёт пустой отчёт команды command.
+func NewReport(command string) *Report {
+	return &Report{Command: command, GoVersion: runtime.Version(), Tasks: []Record{}}
+}
+
+// Add добавляет запись в отчёт.
+func (r *Report) Add(rec Record) {
+	r.Tasks = append(r.Tasks, rec)
+}
+
+// Count — сколько задач с вердиктом v.
+func (r *Report) Count(v Verdict) int {
+	n := 0
+	for _, t := range r.Tasks {
+		if t.Verdict == v {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed — есть ли упавшие или не запустившиеся задачи.
+func (r *Report) Failed() bool {
+	for _, t := range r.Tasks {
+		if t.Verdict == Fail || t.Verdict == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Write пишет отчёт в формате f.
+func (r *Report) Write(w io.Writer, f Format) error {
+	if f == JUnit {
+		return WriteJUnit(w, r)
+	}
+	return WriteJSON(w, r)
+}
+
+// WriteJSON пишет v в w как JSON с отступами.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
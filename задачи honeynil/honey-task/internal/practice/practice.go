@@ -0,0 +1,202 @@
+// Package practice готовит рабочее место для самостоятельного решения задачи:
+// копию main.go, в которой от эталона остались только типы и сигнатуры,
+// и проверку решения скрытыми тестами или самопроверками эталона.
+package practice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// WorkspaceDir — каталог рабочих мест в корне модуля. Он начинается с "_",
+// поэтому ./... и поиск тем его не видят.
+const WorkspaceDir = "_practice"
+
+var (
+	// ErrExists — рабочее место уже создано; перезаписывать решение нельзя.
+	ErrExists = errors.New("рабочее место уже существует")
+	// ErrNoWorkspace — verify вызван до practice.
+	ErrNoWorkspace = errors.New("рабочее место не найдено")
+	// ErrQuizTask — задача на чтение кода: в ней нечего реализовывать,
+	// а заготовка выдала бы ответ.
+	ErrQuizTask = errors.New("задача на чтение кода, её решают командой quiz")
+)
+
+// Dir возвращает каталог рабочего места задачи num темы topic.
+func Dir(root string, topic registry.Topic, num int) string {
+	return filepath.Join(root, WorkspaceDir, topic.Name, fmt.Sprintf("task%03d", num))
+}
+
+// Create создаёт рабочее место: заготовку main.go и собственный go.mod,
+// чтобы решение собиралось отдельно от модуля с эталонами. Для задач на
+// чтение кода (с ответом в комментарии) возвращает ErrQuizTask.
+func Create(root string, topic registry.Topic, num int) (string, error) {
+	if !topic.IsFileBased {
+		return "", fmt.Errorf("тема %s: практика доступна только для задач в отдельных каталогах", topic.Name)
+	}
+	if doc, err := taskdoc.Parse(topic, num); err == nil && len(doc.Answers) > 0 {
+		return "", ErrQuizTask
+	}
+	dir := Dir(root, topic, num)
+	if _, err := os.Stat(dir); err == nil {
+		return dir, ErrExists
+	}
+
+	src, err := os.ReadFile(topic.TaskFile(num))
+	if err != nil {
+		return "", err
+	}
+	ext, err := externalImports(src)
+	if err != nil {
+		return "", err
+	}
+	if len(ext) > 0 {
+		return "", fmt.Errorf("задача использует внешние зависимости: %s", strings.Join(ext, ", "))
+	}
+	stub, err := Stub(taskdoc.StripHidden(src), CheckMode(topic, num) != ModeRun)
+	if err != nil {
+		return "", err
+	}
+	gomod, err := goMod(root, topic, num)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), gomod, 0o644); err != nil {
+		return "", err
+	}
+	return dir, os.WriteFile(filepath.Join(dir, "main.go"), stub, 0o644)
+}
+
+// goMod собирает go.mod рабочего места с той же версией Go, что у модуля.
+func goMod(root string, topic registry.Topic, num int) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	goLine := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "go ") {
+			goLine = line
+		}
+	}
+	return fmt.Appendf(nil, "module practice/%s/task%03d\n\n%s\n", topic.Name, num, goLine), nil
+}
+
+// Mode — способ проверки решения.
+type Mode string
+
+const (
+	ModeTests     Mode = "tests"      // скрытые тесты задачи (main_test.go эталона)
+	ModeSelfCheck Mode = "self-check" // самопроверки panic("wrong code") в main
+	ModeRun       Mode = "run"        // проверок нет — только запуск
+)
+
+// Verification — результат проверки решения.
+type Verification struct {
+	Mode   Mode
+	Result runner.Result
+}
+
+// Passed сообщает, прошло ли решение проверку.
+func (v Verification) Passed() bool {
+	return v.Result.Status == runner.StatusOK
+}
+
+// CheckMode сообщает, чем будет проверяться решение задачи num.
+func CheckMode(topic registry.Topic, num int) Mode {
+	if _, err := os.Stat(testsFile(topic, num)); err == nil {
+		return ModeTests
+	}
+	if src, err := os.ReadFile(topic.TaskFile(num)); err == nil && strings.Contains(string(src), `panic("wrong code")`) {
+		return ModeSelfCheck
+	}
+	return ModeRun
+}
+
+func testsFile(topic registry.Topic, num int) string {
+	return filepath.Join(topic.TaskDir(num), "main_test.go")
+}
+
+// Verify проверяет решение из рабочего места. Решение копируется во временный
+// каталог вместе со скрытыми тестами эталона, если они есть; иначе запускается
+// main с самопроверками. Вывод дублируется в stdout/stderr.
+func Verify(root string, topic registry.Topic, num int, stdout, stderr io.Writer) (Verification, error) {
+	dir := Dir(root, topic, num)
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+		return Verification{}, fmt.Errorf("%w: %s (сначала выполните practice)", ErrNoWorkspace, dir)
+	}
+
+	tmp, err := os.MkdirTemp("", "honey-practice")
+	if err != nil {
+		return Verification{}, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := copyWorkspace(dir, tmp); err != nil {
+		return Verification{}, err
+	}
+
+	v := Verification{Mode: CheckMode(topic, num)}
+	if v.Mode == ModeTests {
+		if err := copyFile(testsFile(topic, num), filepath.Join(tmp, "main_test.go")); err != nil {
+			return Verification{}, err
+		}
+	}
+
+	args := []string{"run"}
+	if v.Mode == ModeTests {
+		args = []string{"test", "-count=1"}
+	}
+	if runner.DetectRaces {
+		args = append(args, "-race")
+	}
+	cmd := exec.Command("go", append(args, ".")...)
+	cmd.Dir = tmp
+	v.Result = runner.Exec(cmd, runner.Timeout, stdout, stderr)
+	return v, nil
+}
+
+// copyWorkspace копирует решение без тестов: тесты в рабочем месте
+// подменили бы скрытые.
+func copyWorkspace(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if name == "go.mod" || name == "go.sum" || strings.HasSuffix(name, ".go") {
+			if err := copyFile(filepath.Join(from, name), filepath.Join(to, name)); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(to, "go.mod")); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: нет go.mod", from)
+	}
+	return nil
+}
+
+func copyFile(from, to string) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0o644)
+}
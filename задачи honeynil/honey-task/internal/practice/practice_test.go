@@ -0,0 +1,54 @@
+package practice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+func TestCreate(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/tasks\n\ngo 1.25.1\n",
+		"concurrency/task001/main.go": taskSrc,
+		"concurrency/task002/main.go": "// ЗАДАЧА 2: Что выведет?\n//\n// ОТВЕТ: 1\npackage main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1) }\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg, err := registry.Discover(root, nil)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	topic, _ := reg.Topic("concurrency")
+
+	if _, err := Create(root, topic, 2); !errors.Is(err, ErrQuizTask) {
+		t.Fatalf("Create of a code-reading task = %v, want ErrQuizTask", err)
+	}
+
+	dir, err := Create(root, topic, 1)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	stub, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Проверок у задачи нет — main заглушён вместе с остальными функциями.
+	if strings.Contains(string(stub), "c.Inc()") || strings.Contains(string(stub), "ИСПРАВЛЕНИЕ") {
+		t.Fatalf("stub gives the solution away:\n%s", stub)
+	}
+	if _, err := Create(root, topic, 1); !errors.Is(err, ErrExists) {
+		t.Fatalf("second Create = %v, want ErrExists", err)
+	}
+}
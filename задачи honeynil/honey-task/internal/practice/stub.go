@@ -0,0 +1,188 @@
+package practice
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// todoBody заменяет тело каждой заглушённой функции.
+const todoBody = "{\n\tpanic(\"TODO\")\n}"
+
+// fixMarker начинает комментарий с исправлением эталона: в заготовке он
+// выдал бы решение.
+const fixMarker = "ИСПРАВЛЕНИЕ"
+
+// Stub возвращает заготовку решения: типы, интерфейсы, сигнатуры и комментарии
+// остаются как в src, а тела функций и методов заменяются на panic("TODO").
+// main сохраняется, если keepMain: тогда в нём демо и самопроверки задачи;
+// у задач без проверок решение нередко целиком в main, и его тоже заглушаем.
+// Комментарии "// ИСПРАВЛЕНИЕ..." удаляются, как и импорты, которые после
+// этого перестали использоваться.
+func Stub(src []byte, keepMain bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Тела и комментарии вырезаются из текста, а не из AST: так форматирование
+	// и остальные комментарии остаются нетронутыми.
+	type span struct {
+		from, to int
+		repl     string
+	}
+	var cuts []span
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || (keepMain && fn.Recv == nil && fn.Name.Name == "main") {
+			continue
+		}
+		cuts = append(cuts, span{fset.Position(fn.Body.Lbrace).Offset, fset.Position(fn.Body.Rbrace).Offset + 1, todoBody})
+	}
+	inBody := func(off int) bool {
+		for _, c := range cuts {
+			if c.from <= off && off < c.to {
+				return true
+			}
+		}
+		return false
+	}
+	isFix := func(c *ast.Comment) bool {
+		return strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(c.Text, "//")), fixMarker)
+	}
+	for _, cg := range f.Comments {
+		// Пустая строка "//", после которой в группе остались только
+		// исправления, тоже удаляется, чтобы комментарий не обрывался на ней.
+		tail := len(cg.List)
+		for tail > 0 && isFix(cg.List[tail-1]) {
+			tail--
+		}
+		for i, c := range cg.List {
+			from, to := fset.Position(c.Pos()).Offset, fset.Position(c.End()).Offset
+			if !(isFix(c) || i == tail-1 && tail < len(cg.List) && c.Text == "//") || inBody(from) {
+				continue
+			}
+			// Комментарий на отдельной строке удаляется вместе со строкой,
+			// после кода — вместе с пробелами перед ним.
+			lineStart := bytes.LastIndexByte(src[:from], '\n') + 1
+			if len(bytes.TrimSpace(src[lineStart:from])) == 0 {
+				from = lineStart
+				if to < len(src) && src[to] == '\n' {
+					to++
+				}
+			} else {
+				for from > lineStart && (src[from-1] == ' ' || src[from-1] == '\t') {
+					from--
+				}
+			}
+			cuts = append(cuts, span{from: from, to: to})
+		}
+	}
+
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].from > cuts[j].from })
+	out := bytes.Clone(src)
+	for _, c := range cuts {
+		out = append(out[:c.from], append([]byte(c.repl), out[c.to:]...)...)
+	}
+
+	return dropUnusedImports(out)
+}
+
+// dropUnusedImports удаляет импорты, на которые больше нет ссылок, и
+// форматирует результат. Импорты вырезаются из текста вместе со своими
+// строками: printer по позициям удалённых из AST узлов оставил бы на их
+// месте пустые строки.
+func dropUnusedImports(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		// Неразрешённый идентификатор слева от точки — имя пакета.
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+	var cuts [][2]int
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		var unused []ast.Spec
+		for _, s := range gen.Specs {
+			if name := importName(s.(*ast.ImportSpec)); name != "_" && name != "." && !used[name] {
+				unused = append(unused, s)
+			}
+		}
+		if len(unused) == len(gen.Specs) {
+			cuts = append(cuts, lineSpan(src, offset(gen.Pos()), offset(gen.End())))
+			continue
+		}
+		for _, s := range unused {
+			cuts = append(cuts, lineSpan(src, offset(s.Pos()), offset(s.End())))
+		}
+	}
+
+	out := bytes.Clone(src)
+	for i := len(cuts) - 1; i >= 0; i-- {
+		out = append(out[:cuts[i][0]], out[cuts[i][1]:]...)
+	}
+	return format.Source(out)
+}
+
+// lineSpan расширяет отрезок [from, to) до целых строк, если кроме него
+// на них только пробелы и комментарий в конце.
+func lineSpan(src []byte, from, to int) [2]int {
+	lineStart := bytes.LastIndexByte(src[:from], '\n') + 1
+	lineEnd := len(src)
+	if i := bytes.IndexByte(src[to:], '\n'); i >= 0 {
+		lineEnd = to + i + 1
+	}
+	rest := bytes.TrimSpace(src[to:lineEnd])
+	if len(bytes.TrimSpace(src[lineStart:from])) != 0 || len(rest) != 0 && !bytes.HasPrefix(rest, []byte("//")) {
+		return [2]int{from, to}
+	}
+	return [2]int{lineStart, lineEnd}
+}
+
+// importName возвращает имя, под которым импорт виден в файле.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	p, _ := strconv.Unquote(spec.Path.Value)
+	return path.Base(p)
+}
+
+// externalImports возвращает импорты не из стандартной библиотеки.
+func externalImports(src []byte) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "main.go", src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	var ext []string
+	for _, spec := range f.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		first, _, _ := strings.Cut(p, "/")
+		if strings.Contains(first, ".") {
+			ext = append(ext, p)
+		}
+	}
+	return ext, nil
+}
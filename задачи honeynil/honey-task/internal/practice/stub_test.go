@@ -0,0 +1,152 @@
+package practice
+
+import (
+	"strings"
+	"testing"
+)
+
+const taskSrc = `// ЗАДАЧА 1: Счётчик
+// Исправьте гонку.
+//
+// ИСПРАВЛЕНИЕ: защитить n мьютексом
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Counter считает события.
+type Counter struct {
+	mu sync.Mutex // ИСПРАВЛЕНИЕ: добавить mutex
+	n  int
+}
+
+// Inc увеличивает счётчик.
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func upper(s string) string { return strings.ToUpper(s) }
+
+func main() {
+	var c Counter
+	// ИСПРАВЛЕНИЕ: ждать горутину
+	c.Inc()
+	fmt.Println(upper("ok"), c.n)
+}
+`
+
+// taskStub — заготовка из taskSrc с сохранённым main.
+const taskStub = `// ЗАДАЧА 1: Счётчик
+// Исправьте гонку.
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Counter считает события.
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+// Inc увеличивает счётчик.
+func (c *Counter) Inc() {
+	panic("TODO")
+}
+
+func upper(s string) string {
+	panic("TODO")
+}
+
+func main() {
+	var c Counter
+	c.Inc()
+	fmt.Println(upper("ok"), c.n)
+}
+`
+
+func TestStub(t *testing.T) {
+	tests := []struct {
+		name     string
+		keepMain bool
+		want     []string // фрагменты, которые должны остаться
+		gone     []string // фрагменты, которых быть не должно
+	}{
+		{
+			name:     "keep main",
+			keepMain: true,
+			want: []string{
+				"// Исправьте гонку.\npackage main",
+				"mu sync.Mutex\n",
+				"// Inc увеличивает счётчик.\nfunc (c *Counter) Inc() {\n\tpanic(\"TODO\")\n}",
+				"func upper(s string) string {\n\tpanic(\"TODO\")\n}",
+				"fmt.Println(upper(\"ok\"), c.n)",
+				`"fmt"`,
+			},
+			gone: []string{"ИСПРАВЛЕНИЕ", "c.n++", "strings.ToUpper", `"strings"`, "//\npackage"},
+		},
+		{
+			name: "stub main",
+			want: []string{"func main() {\n\tpanic(\"TODO\")\n}", `"sync"`},
+			gone: []string{"ИСПРАВЛЕНИЕ", "c.Inc()", `"fmt"`, `"strings"`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Stub([]byte(taskSrc), tc.keepMain)
+			if err != nil {
+				t.Fatalf("Stub: %v", err)
+			}
+			for _, s := range tc.want {
+				if !strings.Contains(string(out), s) {
+					t.Errorf("stub lacks %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tc.gone {
+				if strings.Contains(string(out), s) {
+					t.Errorf("stub still contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestStubText(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		keepMain bool
+		want     string
+	}{
+		{"task", taskSrc, true, taskStub},
+		{"single import dropped", "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n", false,
+			"package main\n\nfunc main() {\n\tpanic(\"TODO\")\n}\n"},
+		{"import block with trailing comment dropped", "package main\n\nimport (\n\t\"fmt\"\n\t\"os\" // выход\n)\n\nfunc main() { fmt.Println(); os.Exit(1) }\n", false,
+			"package main\n\nfunc main() {\n\tpanic(\"TODO\")\n}\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Stub([]byte(tc.src), tc.keepMain)
+			if err != nil {
+				t.Fatalf("Stub: %v", err)
+			}
+			if string(out) != tc.want {
+				t.Fatalf("stub:\n%s\nwant:\n%s", out, tc.want)
+			}
+		})
+	}
+}
+
+func TestStubInvalidSource(t *testing.T) {
+	if _, err := Stub([]byte("package main\n\nfunc main() {"), true); err == nil {
+		t.Fatal("Stub of invalid source returned no error")
+	}
+}
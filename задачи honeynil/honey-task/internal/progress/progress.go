@@ -0,0 +1,262 @@
+// Package progress хранит историю попыток по задачам и планирует повторения
+// по алгоритму интервального повторения SM-2.
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Источники попыток.
+const (
+	SourceQuiz      = "quiz"
+	SourceVerify    = "verify" // проверка своего решения из practice
+	SourceReview    = "review"
+	SourceInterview = "interview"
+)
+
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+)
+
+// Attempt — одна попытка решить задачу.
+type Attempt struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Correct bool      `json:"correct"`
+	Quality int       `json:"quality"` // оценка SM-2 от 0 до 5
+	Graded  bool      `json:"graded"`  // попытка влияет на расписание повторений
+}
+
+// Card — состояние задачи в расписании повторений.
+type Card struct {
+	Attempts    []Attempt   `json:"attempts"`
+	Repetitions int         `json:"repetitions"` // успешных повторений подряд
+	Interval    int         `json:"interval"`    // интервал до следующего повторения, дни
+	Ease        float64     `json:"ease"`
+	Due         time.Time   `json:"due"`
+	Hints       []time.Time `json:"hints,omitempty"` // когда открыта каждая подсказка, по порядку
+}
+
+// Reviewed сообщает, участвовала ли задача хотя бы в одной оцениваемой попытке.
+func (c *Card) Reviewed() bool {
+	return !c.Due.IsZero()
+}
+
+// Mastered — задача закреплена: интервал повторения не меньше трёх недель.
+func (c *Card) Mastered() bool {
+	return c.Interval >= 21
+}
+
+// Store — прогресс пользователя, сохраняемый в JSON-файл.
+type Store struct {
+	Tasks map[string]*Card `json:"tasks"`
+
+	path string
+}
+
+// DefaultPath возвращает путь к файлу прогресса в конфигурационном каталоге пользователя.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "honey-task", "progress.json"), nil
+}
+
+// Load читает прогресс из path. Отсутствующий файл — пустой прогресс.
+func Load(path string) (*Store, error) {
+	s := &Store{Tasks: make(map[string]*Card), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]*Card)
+	}
+	return s, nil
+}
+
+// Save атомарно записывает прогресс: сначала во временный файл, затем rename.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Key — ключ задачи в хранилище: "topic/num".
+func Key(topic string, num int) string {
+	return topic + "/" + strconv.Itoa(num)
+}
+
+// ParseKey разбирает ключ "topic/num".
+func ParseKey(key string) (topic string, num int, ok bool) {
+	topic, n, ok := strings.Cut(key, "/")
+	if !ok {
+		return "", 0, false
+	}
+	num, err := strconv.Atoi(n)
+	return topic, num, err == nil
+}
+
+// Card возвращает карточку задачи, создавая её при необходимости.
+func (s *Store) Card(topic string, num int) *Card {
+	key := Key(topic, num)
+	c, ok := s.Tasks[key]
+	if !ok {
+		c = &Card{Ease: initialEase}
+		s.Tasks[key] = c
+	}
+	return c
+}
+
+// Record добавляет неоцениваемую попытку: она попадает в историю,
+// но не сдвигает расписание (например, проверка своего решения через verify).
+func (s *Store) Record(topic string, num int, source string, correct bool, now time.Time) {
+	c := s.Card(topic, num)
+	c.Attempts = append(c.Attempts, Attempt{Time: now, Source: source, Correct: correct})
+}
+
+// Grade добавляет оцениваемую попытку с качеством ответа quality (0–5)
+// и пересчитывает интервал повторения по SM-2.
+func (s *Store) Grade(topic string, num int, source string, quality int, now time.Time) {
+	quality = min(max(quality, 0), 5)
+	c := s.Card(topic, num)
+	c.Attempts = append(c.Attempts, Attempt{
+		Time:    now,
+		Source:  source,
+		Correct: quality >= 3,
+		Quality: quality,
+		Graded:  true,
+	})
+
+	if quality < 3 {
+		c.Repetitions = 0
+		c.Interval = 1
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetitions++
+	}
+
+	q := float64(5 - quality)
+	c.Ease = max(c.Ease+0.1-q*(0.08+q*0.02), minEase)
+	// AddDate, а не Add(n*24h): при переходе на летнее время сутки короче
+	// или длиннее, и повторение сползло бы на час с полуночи.
+	c.Due = startOfDay(now).AddDate(0, 0, c.Interval)
+}
+
+// UseHint отмечает, что открыта следующая подсказка задачи, и возвращает,
+// сколько подсказок открыто всего. Подсказки не меняют расписание повторений.
+func (s *Store) UseHint(topic string, num int, now time.Time) int {
+	c := s.Card(topic, num)
+	c.Hints = append(c.Hints, now)
+	return len(c.Hints)
+}
+
+// HintsUsed — сколько подсказок задачи уже открыто.
+func (s *Store) HintsUsed(topic string, num int) int {
+	if c, ok := s.Tasks[Key(topic, num)]; ok {
+		return len(c.Hints)
+	}
+	return 0
+}
+
+// Due возвращает ключи задач, повторение которых назначено на now или раньше,
+// начиная с самых просроченных.
+func (s *Store) Due(now time.Time) []string {
+	end := startOfDay(now).AddDate(0, 0, 1)
+	var keys []string
+	for key, c := range s.Tasks {
+		if c.Reviewed() && c.Due.Before(end) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		di, dj := s.Tasks[keys[i]].Due, s.Tasks[keys[j]].Due
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// TopicStats — сводка по теме.
+type TopicStats struct {
+	Attempted int // задач с хотя бы одной попыткой
+	Mastered  int
+	Due       int
+	Attempts  int
+	Correct   int
+	Hints     int // открытых подсказок
+}
+
+// Stats считает сводку по задачам темы topic с номерами nums.
+func (s *Store) Stats(topic string, nums []int, now time.Time) TopicStats {
+	var st TopicStats
+	end := startOfDay(now).AddDate(0, 0, 1)
+	for _, num := range nums {
+		c, ok := s.Tasks[Key(topic, num)]
+		if !ok {
+			continue
+		}
+		st.Hints += len(c.Hints)
+		if len(c.Attempts) == 0 {
+			continue
+		}
+		st.Attempted++
+		if c.Mastered() {
+			st.Mastered++
+		}
+		if c.Reviewed() && c.Due.Before(end) {
+			st.Due++
+		}
+		for _, a := range c.Attempts {
+			if !a.Graded {
+				continue
+			}
+			st.Attempts++
+			if a.Correct {
+				st.Correct++
+			}
+		}
+	}
+	return st
+}
@@ -0,0 +1,156 @@
+package progress
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestGradeDueAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 30 марта 2025 в Берлине часы переводятся вперёд: в сутках 23 часа.
+	now := time.Date(2025, 3, 29, 20, 0, 0, 0, berlin)
+	s := &Store{Tasks: make(map[string]*Card)}
+	s.Grade("maps", 1, SourceQuiz, 5, now)
+	s.Grade("maps", 1, SourceQuiz, 5, now) // второе успешное повторение — 6 дней
+
+	if due, want := s.Card("maps", 1).Due, time.Date(2025, 4, 4, 0, 0, 0, 0, berlin); !due.Equal(want) {
+		t.Fatalf("Due = %v, want local midnight %v", due, want)
+	}
+	if got := s.Due(time.Date(2025, 4, 4, 0, 30, 0, 0, berlin)); len(got) != 1 {
+		t.Fatalf("Due(Apr 4) = %v, want the task", got)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	now := time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		qualities    []int
+		interval     int
+		repetitions  int
+		ease         float64
+		correctCount int
+	}{
+		{"first success", []int{5}, 1, 1, 2.6, 1},
+		{"second success", []int{5, 5}, 6, 2, 2.7, 2},
+		{"third success multiplies by ease", []int{5, 5, 4}, 16, 3, 2.7, 3},
+		{"failure resets repetitions", []int{5, 5, 2}, 1, 0, 2.38, 2},
+		{"quality clamped", []int{9, -3}, 1, 0, 1.8, 1},
+		{"ease has a floor", []int{0, 0, 0, 0, 0}, 1, 0, 1.3, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Store{Tasks: make(map[string]*Card)}
+			for _, q := range tc.qualities {
+				s.Grade("maps", 1, SourceQuiz, q, now)
+			}
+			c := s.Card("maps", 1)
+			if c.Interval != tc.interval || c.Repetitions != tc.repetitions {
+				t.Errorf("Interval, Repetitions = %d, %d; want %d, %d", c.Interval, c.Repetitions, tc.interval, tc.repetitions)
+			}
+			if diff := c.Ease - tc.ease; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Ease = %v, want %v", c.Ease, tc.ease)
+			}
+			if want := time.Date(2025, 1, 10+tc.interval, 0, 0, 0, 0, time.UTC); !c.Due.Equal(want) {
+				t.Errorf("Due = %v, want %v", c.Due, want)
+			}
+			correct := 0
+			for _, a := range c.Attempts {
+				if !a.Graded {
+					t.Errorf("attempt %+v not graded", a)
+				}
+				if a.Correct {
+					correct++
+				}
+			}
+			if correct != tc.correctCount {
+				t.Errorf("correct attempts = %d, want %d", correct, tc.correctCount)
+			}
+		})
+	}
+}
+
+func TestRecordDoesNotSchedule(t *testing.T) {
+	s := &Store{Tasks: make(map[string]*Card)}
+	s.Record("maps", 1, SourceVerify, true, time.Now())
+	c := s.Card("maps", 1)
+	if c.Reviewed() || len(c.Attempts) != 1 || c.Attempts[0].Graded {
+		t.Fatalf("card after Record = %+v, want one ungraded attempt and no schedule", c)
+	}
+	if due := s.Due(time.Now().AddDate(1, 0, 0)); len(due) != 0 {
+		t.Fatalf("Due = %v, want nothing scheduled", due)
+	}
+}
+
+func TestDue(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 1, d, h, 0, 0, 0, time.UTC) }
+	s := &Store{Tasks: map[string]*Card{
+		"maps/2":   {Due: day(5, 0)},
+		"maps/1":   {Due: day(3, 0)},
+		"slices/1": {Due: day(5, 0)},
+		"slices/2": {Due: day(6, 0)},
+		"slices/3": {}, // не оценивалась
+	}}
+	tests := []struct {
+		now  time.Time
+		want []string
+	}{
+		{day(2, 23), nil},
+		{day(3, 0), []string{"maps/1"}},
+		{day(5, 23), []string{"maps/1", "maps/2", "slices/1"}},
+		{day(9, 12), []string{"maps/1", "maps/2", "slices/1", "slices/2"}},
+	}
+	for _, tc := range tests {
+		if got := s.Due(tc.now); !slices.Equal(got, tc.want) {
+			t.Errorf("Due(%v) = %v, want %v", tc.now, got, tc.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "progress.json")
+	s, err := Load(path)
+	if err != nil || len(s.Tasks) != 0 {
+		t.Fatalf("Load of missing file = %v, %v; want empty store", s, err)
+	}
+	now := time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)
+	s.Grade("maps", 1, SourceQuiz, 4, now)
+	s.UseHint("maps", 1, now)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := loaded.Card("maps", 1)
+	if !c.Due.Equal(now.AddDate(0, 0, 1).Truncate(24*time.Hour)) || loaded.HintsUsed("maps", 1) != 1 || len(c.Attempts) != 1 {
+		t.Fatalf("loaded card = %+v", c)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		topic string
+		num   int
+		ok    bool
+	}{
+		{Key("code-review", 12), "code-review", 12, true},
+		{"maps", "", 0, false},
+		{"maps/x", "maps", 0, false},
+	}
+	for _, tc := range tests {
+		topic, num, ok := ParseKey(tc.key)
+		if ok != tc.ok || (ok && (topic != tc.topic || num != tc.num)) {
+			t.Errorf("ParseKey(%q) = %q, %d, %v; want %q, %d, %v", tc.key, topic, num, ok, tc.topic, tc.num, tc.ok)
+		}
+	}
+}
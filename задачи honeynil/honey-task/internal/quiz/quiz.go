@@ -0,0 +1,227 @@
+// Package quiz — интерактивный режим "угадай вывод": показывает код задачи
+// без ответа, читает предсказание пользователя, запускает задачу и сравнивает.
+package quiz
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/check"
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+// Исходы запуска задачи, которые можно назвать вместо вывода.
+const (
+	OutcomeOutput   = "output"
+	OutcomePanic    = "panic"
+	OutcomeDeadlock = "deadlock"
+)
+
+// ErrNoAnswer — в комментарии задачи нет ответа, угадывать нечего.
+var ErrNoAnswer = errors.New("в комментарии задачи нет ответа")
+
+// Question — подготовленная задача для квиза.
+type Question struct {
+	Topic       string
+	Num         int
+	Prompt      string // формулировка задачи
+	Source      string // код без комментариев
+	Explanation string // полный комментарий задачи, показывается после ответа
+	Answer      taskdoc.Answer
+	HasLines    bool   // в комментарии есть ожидаемые строки вывода
+	Expect      string // ожидаемый исход по комментарию: output, panic, deadlock или ""
+}
+
+// Result — итог одного вопроса.
+type Result struct {
+	Topic      string
+	Num        int
+	Correct    bool
+	Skipped    bool
+	Prediction []string // ответ пользователя
+}
+
+// Prepare готовит вопрос по задаче num темы topic.
+func Prepare(reg *registry.Registry, topic registry.Topic, num int) (*Question, error) {
+	doc, err := taskdoc.Parse(topic, num)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Answers) == 0 {
+		return nil, ErrNoAnswer
+	}
+	src, err := taskdoc.Source(topic, num)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Question{
+		Topic:       topic.Name,
+		Num:         num,
+		Prompt:      doc.QuestionIn(i18n.Current()),
+		Source:      src,
+		Explanation: doc.ExplanationIn(i18n.Current()),
+	}
+	if a, ok := doc.Expected(reg.GoMinor); ok {
+		q.Answer, q.HasLines, q.Expect = a, true, OutcomeOutput
+	} else {
+		q.Answer = doc.Answers[0]
+		q.Expect = inlineOutcome(q.Answer.Inline)
+	}
+	return q, nil
+}
+
+// inlineOutcome распознаёт ответы вида "ОТВЕТ: DEADLOCK / программа зависнет".
+func inlineOutcome(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "deadlock"):
+		return OutcomeDeadlock
+	case strings.Contains(s, "panic"), strings.Contains(s, "паник"):
+		return OutcomePanic
+	}
+	return ""
+}
+
+// Session проводит квиз по списку задач, читая ответы из in и печатая в out.
+type Session struct {
+	Reg *registry.Registry
+	In  *bufio.Reader
+	Out io.Writer
+}
+
+// NewSession создаёт сессию поверх in/out.
+func NewSession(reg *registry.Registry, in io.Reader, out io.Writer) *Session {
+	return &Session{Reg: reg, In: bufio.NewReader(in), Out: out}
+}
+
+// Ask задаёт один вопрос. quit == true, если пользователь завершил квиз.
+func (s *Session) Ask(topic registry.Topic, num int) (res Result, quit bool, err error) {
+	res = Result{Topic: topic.Name, Num: num}
+	q, err := Prepare(s.Reg, topic, num)
+	if err != nil {
+		return res, false, err
+	}
+
+	fmt.Fprintf(s.Out, "\n%s\n%s/%d: %s\n%s\n\n", rule, q.Topic, q.Num, q.Prompt, rule)
+	fmt.Fprintln(s.Out, q.Source)
+	i18n.Fprintln(s.Out, "Введите ожидаемый вывод построчно и завершите строкой \".\"")
+	i18n.Fprintln(s.Out, "(или одно слово: panic, deadlock; skip — пропустить, quit — выйти)")
+
+	prediction, eof := s.readPrediction()
+	res.Prediction = prediction
+	switch {
+	case len(prediction) == 1 && prediction[0] == "quit", eof && len(prediction) == 0:
+		return res, true, nil
+	case len(prediction) == 1 && prediction[0] == "skip":
+		res.Skipped = true
+		i18n.Fprintln(s.Out, "\nПропущено. Ответ из комментария:")
+		fmt.Fprintln(s.Out, q.Explanation)
+		return res, false, nil
+	}
+
+	actual, outcome := execute(s.Reg, topic, num)
+	i18n.Fprintf(s.Out, "\nФактический результат (%s):\n%s\n", outcome, strings.Join(actual, "\n"))
+
+	res.Correct = s.score(q, prediction, actual, outcome)
+	if res.Correct {
+		i18n.Fprintln(s.Out, "\nВерно!")
+	} else {
+		i18n.Fprintln(s.Out, "\nНеверно.")
+		if outcome == OutcomeOutput {
+			fmt.Fprint(s.Out, check.Diff(prediction, actual))
+		}
+	}
+	i18n.Fprintf(s.Out, "\nОбъяснение:\n%s\n", q.Explanation)
+	return res, false, nil
+}
+
+func (s *Session) score(q *Question, prediction, actual []string, outcome string) bool {
+	if len(prediction) == 1 {
+		switch word := strings.ToLower(prediction[0]); word {
+		case OutcomePanic, OutcomeDeadlock:
+			return word == outcome
+		}
+	}
+	if outcome == OutcomeOutput {
+		return check.Equal(prediction, actual, q.Answer.Unordered)
+	}
+	if !q.HasLines && q.Expect == "" {
+		// Ответ в свободной форме ("Зависит от версии Go") — пусть решает пользователь.
+		i18n.Fprintf(s.Out, "\nОтвет из комментария: %s\nЗасчитать? [y/N] ", q.Answer.Inline)
+		line, _ := s.In.ReadString('\n')
+		return strings.EqualFold(strings.TrimSpace(line), "y")
+	}
+	return false
+}
+
+// readPrediction читает строки до "." или EOF.
+func (s *Session) readPrediction() (lines []string, eof bool) {
+	for {
+		line, err := s.In.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n \t")
+		if err == nil && line == "." {
+			return lines, false
+		}
+		if err != nil {
+			if line != "" {
+				lines = append(lines, line)
+			}
+			return lines, true
+		}
+		lines = append(lines, line)
+		if len(lines) == 1 {
+			switch strings.ToLower(line) {
+			case "quit", "skip", OutcomePanic, OutcomeDeadlock:
+				return lines, false
+			}
+		}
+	}
+}
+
+const rule = "=================================================="
+
+// execute запускает задачу и возвращает её вывод и исход.
+func execute(reg *registry.Registry, topic registry.Topic, num int) ([]string, string) {
+	if fn, ok := topic.Tasks[num]; ok {
+		out, err := check.Capture(fn)
+		if err != nil {
+			return lines(out + err.Error()), OutcomePanic
+		}
+		return lines(out), OutcomeOutput
+	}
+
+	cmd, cleanup, err := runner.Command(reg.Root, topic, num)
+	if err != nil {
+		return []string{err.Error()}, OutcomePanic
+	}
+	defer cleanup()
+
+	res := runner.Exec(cmd, runner.Timeout, nil, nil)
+	switch res.Status {
+	case runner.StatusDeadlock, runner.StatusTimeout:
+		// Зависшая программа для квиза — тоже "deadlock".
+		return lines(res.Stdout), OutcomeDeadlock
+	case runner.StatusOK:
+		return lines(res.Stdout), OutcomeOutput
+	}
+	return lines(res.Stdout + res.Stderr), OutcomePanic
+}
+
+func lines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	out := strings.Split(s, "\n")
+	for i, l := range out {
+		out[i] = strings.TrimRight(l, " \t")
+	}
+	return out
+}
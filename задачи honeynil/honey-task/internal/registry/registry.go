@@ -0,0 +1,300 @@
+// Package registry находит темы и задачи на диске, чтобы раннеру не нужен был
+// вручную поддерживаемый список.
+//
+// Поддерживаются два вида тем:
+//   - file-based: каталог темы содержит подкаталоги taskNNN с main.go;
+//   - func-based: каталог темы — пакет с функцией GetTasks() и функциями taskN.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Описания тем, у которых нет package-комментария (file-based темы не являются пакетами).
+var knownDescriptions = map[string]string{
+	"interface":   "Интерфейсы в go (реализация паттернов и систем)",
+	"concurrency": "Конкурентность в go (goroutines, channels, sync)",
+	"algo":        "Алгоритмические задачи",
+	"code-review": "Ревью кода: найти и исправить проблемы",
+}
+
+var (
+	taskDirRe  = regexp.MustCompile(`^task(\d{3})$`)
+	taskFuncRe = regexp.MustCompile(`^task(\d+)$`)
+)
+
+// Topic — тема с задачами.
+type Topic struct {
+	Name        string
+	Description string
+	Dir         string         // абсолютный путь к каталогу темы
+	ImportPath  string         // import path пакета (только для func-based тем)
+	Tasks       map[int]func() // функции задач, если пакет вкомпилирован в раннер
+	Numbers     []int          // номера задач, найденные на диске, по возрастанию
+	Count       int
+	IsFileBased bool // для тем где задачи в отдельных файлах
+
+	files map[int]string // файл с исходником задачи для func-based тем
+}
+
+// Has сообщает, есть ли в теме задача с номером n.
+func (t Topic) Has(n int) bool {
+	i := sort.SearchInts(t.Numbers, n)
+	return i < len(t.Numbers) && t.Numbers[i] == n
+}
+
+// TaskDir возвращает каталог задачи (для func-based тем — каталог темы).
+func (t Topic) TaskDir(n int) string {
+	if t.IsFileBased {
+		return filepath.Join(t.Dir, fmt.Sprintf("task%03d", n))
+	}
+	return t.Dir
+}
+
+// TaskFile возвращает путь к файлу, в котором лежит исходник задачи.
+func (t Topic) TaskFile(n int) string {
+	if t.IsFileBased {
+		return filepath.Join(t.TaskDir(n), "main.go")
+	}
+	return t.files[n]
+}
+
+// TaskFunc возвращает имя функции задачи для func-based тем.
+func (t Topic) TaskFunc(n int) string {
+	if t.IsFileBased {
+		return "main"
+	}
+	return "task" + strconv.Itoa(n)
+}
+
+// Registry — набор тем, найденных в модуле.
+type Registry struct {
+	Root    string // корень модуля (каталог с go.mod)
+	Module  string // module path из go.mod
+	GoMinor int    // минорная версия из директивы go (1.25.1 → 25)
+
+	// ParseErrors — ошибки разбора func-based тем. Такая тема остаётся с
+	// задачами из файлов, которые удалось разобрать, а ошибку показывает lint.
+	ParseErrors []error
+
+	topics map[string]*Topic
+}
+
+// FindRoot поднимается от dir вверх до каталога с go.mod.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod не найден")
+		}
+		dir = parent
+	}
+}
+
+// Discover сканирует корень модуля и собирает темы.
+// provided — функции GetTasks() пакетов, вкомпилированных в раннер; ключ — имя темы.
+func Discover(root string, provided map[string]func() map[int]func()) (*Registry, error) {
+	module, goMinor, err := parseGoMod(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry{Root: root, Module: module, GoMinor: goMinor, topics: make(map[string]*Topic)}
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || skipDir(name) {
+			continue
+		}
+		dir := filepath.Join(root, name)
+
+		topic, err := discoverFileBased(name, dir)
+		if err != nil {
+			return nil, err
+		}
+		if topic == nil {
+			topic, err = discoverFuncBased(name, dir)
+			if err != nil {
+				r.ParseErrors = append(r.ParseErrors, err)
+			}
+			if topic == nil {
+				continue
+			}
+			topic.ImportPath = module + "/" + name
+			if get, ok := provided[name]; ok {
+				topic.Tasks = get()
+			}
+		}
+		if topic.Description == "" {
+			topic.Description = knownDescriptions[name]
+		}
+		if topic.Description == "" {
+			topic.Description = name
+		}
+		r.topics[name] = topic
+	}
+	return r, nil
+}
+
+// Topic возвращает тему по имени.
+func (r *Registry) Topic(name string) (Topic, bool) {
+	t, ok := r.topics[name]
+	if !ok {
+		return Topic{}, false
+	}
+	return *t, true
+}
+
+// Topics возвращает все темы, отсортированные по имени.
+func (r *Registry) Topics() []Topic {
+	out := make([]Topic, 0, len(r.topics))
+	for _, t := range r.topics {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func skipDir(name string) bool {
+	switch {
+	case strings.HasPrefix(name, "."), strings.HasPrefix(name, "_"):
+		return true
+	case name == "internal", name == "testdata", name == "vendor":
+		return true
+	}
+	return false
+}
+
+func discoverFileBased(name, dir string) (*Topic, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var nums []int
+	for _, e := range entries {
+		m := taskDirRe.FindStringSubmatch(e.Name())
+		if !e.IsDir() || m == nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), "main.go")); err != nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return nil, nil
+	}
+	sort.Ints(nums)
+	return &Topic{Name: name, Dir: dir, Numbers: nums, Count: len(nums), IsFileBased: true}, nil
+}
+
+// discoverFuncBased ищет пакет с GetTasks в каталоге темы. Файлы, которые не
+// разбираются, пропускаются: тема собирается из остальных, а ошибка
+// возвращается вместе с ней.
+func discoverFuncBased(name, dir string) (*Topic, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, pkg := range pkgs {
+		var (
+			hasGetTasks bool
+			doc         string
+			files       = make(map[int]string)
+		)
+		for path, f := range pkg.Files {
+			if f.Doc != nil && doc == "" {
+				doc = f.Doc.Text()
+			}
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil {
+					continue
+				}
+				if fn.Name.Name == "GetTasks" {
+					hasGetTasks = true
+					continue
+				}
+				if m := taskFuncRe.FindStringSubmatch(fn.Name.Name); m != nil {
+					n, _ := strconv.Atoi(m[1])
+					files[n] = path
+				}
+			}
+		}
+		if !hasGetTasks {
+			continue
+		}
+
+		nums := make([]int, 0, len(files))
+		for n := range files {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		return &Topic{
+			Name:        name,
+			Description: packageDescription(pkg.Name, doc),
+			Dir:         dir,
+			Numbers:     nums,
+			Count:       len(nums),
+			files:       files,
+		}, err
+	}
+	return nil, err
+}
+
+// packageDescription достаёт описание из комментария вида "Package maps — Maps в go".
+func packageDescription(pkg, doc string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(doc), "\n")
+	line = strings.TrimPrefix(line, "Package "+pkg)
+	line = strings.TrimSpace(strings.TrimLeft(line, " —-:"))
+	return line
+}
+
+func parseGoMod(gomod string) (module string, goMinor int, err error) {
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "module "); ok {
+			module = strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+		if rest, ok := strings.CutPrefix(line, "go "); ok {
+			parts := strings.Split(strings.TrimSpace(rest), ".")
+			if len(parts) > 1 {
+				goMinor, _ = strconv.Atoi(parts[1])
+			}
+		}
+	}
+	if module == "" {
+		return "", 0, fmt.Errorf("%s: директива module не найдена", gomod)
+	}
+	return module, goMinor, nil
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"errors"
+	"go/scanner"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTree создаёт файлы по относительным путям внутри root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod": "module example.com/tasks\n\ngo 1.25.1\n",
+
+		"interface/task001/main.go": "package main\n",
+		"interface/task003/main.go": "package main\n",
+		"interface/task002/README":  "нет main.go — не задача",
+		"interface/notes/main.go":   "package main\n",
+
+		"maps/tasks.go": `// Package maps — Maps в go
+package maps
+
+func GetTasks() map[int]func() { return nil }
+func task2()                   {}
+func task10()                  {}
+func helper()                  {}
+`,
+		"maps/tasks_test.go": "package maps\n\nfunc task99() {}\n",
+
+		"util/util.go":                "package util\n\nfunc task1() {}\n",
+		"_practice/x/task001/main.go": "package main\n",
+		"internal/task001/main.go":    "package main\n",
+		"mytopic/task001/main.go":     "package main\n",
+	})
+
+	r, err := Discover(root, map[string]func() map[int]func(){
+		"maps": func() map[int]func() { return map[int]func(){2: func() {}} },
+	})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if r.Module != "example.com/tasks" || r.GoMinor != 25 {
+		t.Fatalf("module = %q, go minor = %d; want example.com/tasks, 25", r.Module, r.GoMinor)
+	}
+
+	var names []string
+	for _, topic := range r.Topics() {
+		names = append(names, topic.Name)
+	}
+	if want := []string{"interface", "maps", "mytopic"}; !slices.Equal(names, want) {
+		t.Fatalf("topics = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		name        string
+		numbers     []int
+		fileBased   bool
+		description string
+		taskFile    string
+	}{
+		{"interface", []int{1, 3}, true, "Интерфейсы в go (реализация паттернов и систем)", "interface/task003/main.go"},
+		{"maps", []int{2, 10}, false, "Maps в go", "maps/tasks.go"},
+		{"mytopic", []int{1}, true, "mytopic", "mytopic/task001/main.go"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			topic, ok := r.Topic(tc.name)
+			if !ok {
+				t.Fatalf("topic %s not found", tc.name)
+			}
+			if !slices.Equal(topic.Numbers, tc.numbers) || topic.Count != len(tc.numbers) {
+				t.Errorf("Numbers = %v, Count = %d; want %v", topic.Numbers, topic.Count, tc.numbers)
+			}
+			if topic.IsFileBased != tc.fileBased {
+				t.Errorf("IsFileBased = %v, want %v", topic.IsFileBased, tc.fileBased)
+			}
+			if topic.Description != tc.description {
+				t.Errorf("Description = %q, want %q", topic.Description, tc.description)
+			}
+			last := tc.numbers[len(tc.numbers)-1]
+			if got := topic.TaskFile(last); got != filepath.Join(root, tc.taskFile) {
+				t.Errorf("TaskFile(%d) = %s, want %s", last, got, tc.taskFile)
+			}
+			if topic.Has(last+1) || !topic.Has(last) {
+				t.Errorf("Has reports wrong membership for %d/%d", last, last+1)
+			}
+		})
+	}
+
+	maps, _ := r.Topic("maps")
+	if maps.ImportPath != "example.com/tasks/maps" || maps.Tasks == nil {
+		t.Errorf("maps: ImportPath = %q, Tasks = %v; want compiled-in func-based topic", maps.ImportPath, maps.Tasks)
+	}
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"no go.mod", map[string]string{"a/task001/main.go": "package main\n"}},
+		{"no module directive", map[string]string{"go.mod": "go 1.25\n"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tc.files)
+			if _, err := Discover(root, nil); err == nil {
+				t.Fatal("Discover succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestDiscoverBrokenTopic(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":             "module m\n",
+		"maps/tasks.go":      "package maps\n\nfunc GetTasks() map[int]func() { return nil }\nfunc task1() {}\n",
+		"maps/broken.go":     "package maps\n\nfunc task2( {\n",
+		"bad/a.go":           "package bad\n\nfunc GetTasks( {\n",
+		"ok/task001/main.go": "package main\n",
+	})
+
+	r, err := Discover(root, nil)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	var names []string
+	for _, topic := range r.Topics() {
+		names = append(names, topic.Name)
+	}
+	if want := []string{"maps", "ok"}; !slices.Equal(names, want) {
+		t.Fatalf("topics = %v, want %v", names, want)
+	}
+	if maps, _ := r.Topic("maps"); !slices.Equal(maps.Numbers, []int{1}) {
+		t.Errorf("maps.Numbers = %v, want tasks from parsable files only", maps.Numbers)
+	}
+
+	var files []string
+	for _, err := range r.ParseErrors {
+		var list scanner.ErrorList
+		if !errors.As(err, &list) {
+			t.Fatalf("parse error %v is not a scanner.ErrorList", err)
+		}
+		files = append(files, list[0].Pos.Filename)
+	}
+	slices.Sort(files)
+	want := []string{filepath.Join(root, "bad", "a.go"), filepath.Join(root, "maps", "broken.go")}
+	if !slices.Equal(files, want) {
+		t.Fatalf("parse errors in %v, want %v", files, want)
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"go.mod": "module m\n", "a/b/c/x.go": "package c\n"})
+	got, err := FindRoot(filepath.Join(root, "a", "b", "c"))
+	if err != nil || got != root {
+		t.Fatalf("FindRoot = %q, %v; want %q", got, err, root)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+var forwardSignals = []os.Signal{os.Interrupt}
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// signalProcessGroup: послать os.Interrupt другому процессу здесь нельзя.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) {
+	cmd.Process.Kill()
+}
+
+func reraise(sig os.Signal) {
+	os.Exit(1)
+}
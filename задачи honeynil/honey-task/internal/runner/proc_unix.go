@@ -0,0 +1,31 @@
+//go:build unix
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// forwardSignals — сигналы, которые пересылаются группе процессов задачи.
+var forwardSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	// Отрицательный pid — сигнал всей группе процессов.
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) {
+	syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal))
+}
+
+// reraise посылает sig раннеру: без других обработчиков он завершится
+// так же, как завершился бы, не перехватывая сигнал.
+func reraise(sig os.Signal) {
+	syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Race — один отчёт race detector'а (блок WARNING: DATA RACE).
+type Race struct {
+	Access   Access // текущий доступ
+	Previous Access // конфликтующий предыдущий доступ
+}
+
+// Access — доступ к памяти из отчёта о гонке.
+type Access struct {
+	Op        string // read, write, atomic read ...
+	Goroutine int
+	Func      string
+	File      string
+	Line      int
+}
+
+func (a Access) String() string {
+	return fmt.Sprintf("%s %s:%d (goroutine %d, %s)", a.Op, filepath.Base(a.File), a.Line, a.Goroutine, a.Func)
+}
+
+// Location — место доступа без номера горутины, для группировки отчётов.
+func (a Access) Location() string {
+	return fmt.Sprintf("%s %s:%d", a.Op, filepath.Base(a.File), a.Line)
+}
+
+var (
+	accessRe = regexp.MustCompile(`^(Previous )?([A-Za-z ]+?) at 0x[0-9a-f]+ by (goroutine (\d+)|main goroutine):$`)
+	frameRe  = regexp.MustCompile(`^\s+(\S.*):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+const raceBanner = "WARNING: DATA RACE"
+
+// ParseRaces разбирает отчёты race detector'а из stderr.
+func ParseRaces(stderr string) []Race {
+	var (
+		races []Race
+		cur   *Race
+		acc   *Access // доступ, для которого ждём первый кадр стека
+		fn    string
+	)
+	for _, line := range strings.Split(stderr, "\n") {
+		if strings.TrimSpace(line) == raceBanner {
+			races = append(races, Race{})
+			cur, acc = &races[len(races)-1], nil
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "==================") {
+			cur, acc = nil, nil
+			continue
+		}
+		if m := accessRe.FindStringSubmatch(line); m != nil {
+			acc = &cur.Access
+			if m[1] != "" {
+				acc = &cur.Previous
+			}
+			acc.Op = strings.ToLower(m[2])
+			acc.Goroutine, _ = strconv.Atoi(m[4]) // main goroutine → 0
+			fn = ""
+			continue
+		}
+		if acc == nil {
+			continue
+		}
+		// Кадр стека — две строки: имя функции и file:line.
+		if m := frameRe.FindStringSubmatch(line); m != nil && fn != "" {
+			acc.Func, acc.File = fn, m[1]
+			acc.Line, _ = strconv.Atoi(m[2])
+			acc = nil
+			continue
+		}
+		if t := strings.TrimSpace(line); t != "" {
+			fn = strings.TrimSuffix(t, "()")
+		}
+	}
+	return races
+}
+
+// RaceSummary группирует отчёты по паре мест доступа и возвращает строки
+// вида "read main.go:25 ↔ write main.go:25 (×3)".
+func RaceSummary(races []Race) []string {
+	var (
+		order  []string
+		counts = make(map[string]int)
+	)
+	for _, r := range races {
+		key := r.Access.Location() + " ↔ " + r.Previous.Location()
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	out := make([]string, 0, len(order))
+	for _, key := range order {
+		if n := counts[key]; n > 1 {
+			key = fmt.Sprintf("%s (×%d)", key, n)
+		}
+		out = append(out, key)
+	}
+	return out
+}
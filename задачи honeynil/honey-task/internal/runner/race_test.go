@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+)
+
+const twoRaces = `Hello
+==================
+WARNING: DATA RACE
+Write at 0x00c000012345 by goroutine 7:
+  main.main.func1()
+      /tmp/task/main.go:14 +0x44
+
+Previous read at 0x00c000012345 by main goroutine:
+  main.main()
+      /tmp/task/main.go:20 +0x9c
+
+Goroutine 7 (running) created at:
+  main.main()
+      /tmp/task/main.go:12 +0x30
+==================
+==================
+WARNING: DATA RACE
+Write at 0x00c000012345 by goroutine 8:
+  main.main.func1()
+      /tmp/task/main.go:14 +0x44
+
+Previous write at 0x00c000012345 by goroutine 7:
+  main.main.func1()
+      /tmp/task/main.go:14 +0x44
+==================
+Found 2 data race(s)
+exit status 66
+`
+
+func TestParseRaces(t *testing.T) {
+	races := ParseRaces(twoRaces)
+	want := []Race{
+		{
+			Access:   Access{Op: "write", Goroutine: 7, Func: "main.main.func1", File: "/tmp/task/main.go", Line: 14},
+			Previous: Access{Op: "read", Goroutine: 0, Func: "main.main", File: "/tmp/task/main.go", Line: 20},
+		},
+		{
+			Access:   Access{Op: "write", Goroutine: 8, Func: "main.main.func1", File: "/tmp/task/main.go", Line: 14},
+			Previous: Access{Op: "write", Goroutine: 7, Func: "main.main.func1", File: "/tmp/task/main.go", Line: 14},
+		},
+	}
+	if !slices.Equal(races, want) {
+		t.Fatalf("ParseRaces =\n%+v\nwant\n%+v", races, want)
+	}
+}
+
+func TestParseRacesEmpty(t *testing.T) {
+	tests := []string{"", "panic: boom\n", "==================\n"}
+	for _, stderr := range tests {
+		if races := ParseRaces(stderr); len(races) != 0 {
+			t.Errorf("ParseRaces(%q) = %+v, want none", stderr, races)
+		}
+	}
+}
+
+func TestRaceSummary(t *testing.T) {
+	a := Access{Op: "write", File: "/x/main.go", Line: 14}
+	b := Access{Op: "read", File: "/x/main.go", Line: 20}
+	races := []Race{{a, b}, {b, a}, {Access: a, Previous: b}}
+	want := []string{"write main.go:14 ↔ read main.go:20 (×2)", "read main.go:20 ↔ write main.go:14"}
+	if got := RaceSummary(races); !slices.Equal(got, want) {
+		t.Fatalf("RaceSummary = %q, want %q", got, want)
+	}
+}
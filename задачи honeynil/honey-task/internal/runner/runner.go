@@ -0,0 +1,326 @@
+// Package runner запускает задачи в отдельном процессе: собирает команду,
+// ограничивает время работы и классифицирует исход.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+var (
+	// Timeout — ограничение времени на задачу по умолчанию (флаг --timeout раннера).
+	Timeout = 30 * time.Second
+	// KillGrace — сколько задача может завершаться после Ctrl+C, прежде чем
+	// её группу процессов убьют.
+	KillGrace = 2 * time.Second
+	// DetectRaces — собирать задачи с race detector'ом (флаг --race раннера).
+	DetectRaces bool
+)
+
+// Command возвращает команду, запускающую задачу num темы topic.
+// Для file-based тем это go run main.go в каталоге задачи, для func-based —
+// сгенерированная программа-обёртка, вызывающая GetTasks()[num]().
+// cleanup нужно вызвать после завершения команды.
+func Command(root string, topic registry.Topic, num int) (cmd *exec.Cmd, cleanup func(), err error) {
+	args := []string{"run"}
+	if DetectRaces {
+		args = append(args, "-race")
+	}
+
+	if topic.IsFileBased {
+		cmd = exec.Command("go", append(args, "main.go")...)
+		cmd.Dir = topic.TaskDir(num)
+		return cmd, func() {}, nil
+	}
+
+	file, cleanup, err := writeHarness(root, topic.ImportPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd = exec.Command("go", append(args, file, strconv.Itoa(num))...)
+	cmd.Dir = root
+	return cmd, cleanup, nil
+}
+
+// writeHarness пишет во временный каталог внутри модуля программу, вызывающую
+// задачу пакета importPath. Каталог начинается с "_", поэтому ./... его не видит.
+func writeHarness(root, importPath string) (string, func(), error) {
+	dir, err := os.MkdirTemp(root, "_run")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	src := fmt.Sprintf(`package main
+
+import (
+	"os"
+	"strconv"
+
+	task %q
+)
+
+func main() {
+	n, _ := strconv.Atoi(os.Args[1])
+	task.GetTasks()[n]()
+}
+`, importPath)
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return file, cleanup, nil
+}
+
+// ErrInterrupted — раннер получил Ctrl+C или SIGTERM, пока работала задача.
+var ErrInterrupted = errors.New("interrupted")
+
+// Run запускает cmd и ждёт завершения не дольше timeout (0 — без ограничения).
+// По таймауту убивается вся группа процессов: go run запускает собранный
+// бинарник дочерним процессом, и убийство одного go оставило бы его работать.
+//
+// Задача работает в своей группе процессов, поэтому Ctrl+C из терминала до
+// неё не доходит: Run сам пересылает ей SIGINT или SIGTERM, через KillGrace
+// убивает группу и затем завершает раннер тем же сигналом.
+func Run(cmd *exec.Cmd, timeout time.Duration) (timedOut bool, err error) {
+	return RunContext(context.Background(), cmd, timeout)
+}
+
+// RunContext — Run, который дополнительно убивает процесс при отмене ctx
+// (например, когда клиент веб-интерфейса закрыл соединение).
+func RunContext(ctx context.Context, cmd *exec.Cmd, timeout time.Duration) (timedOut bool, err error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, forwardSignals...)
+	defer signal.Stop(sigs)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case err := <-done:
+		return false, err
+	case <-expired:
+		killProcessGroup(cmd)
+		return true, <-done
+	case <-ctx.Done():
+		killProcessGroup(cmd)
+		<-done
+		return false, ctx.Err()
+	case sig := <-sigs:
+		signalProcessGroup(cmd, sig)
+		grace := time.NewTimer(KillGrace)
+		defer grace.Stop()
+		select {
+		case <-done:
+		case <-grace.C:
+			killProcessGroup(cmd)
+			<-done
+		}
+		signal.Stop(sigs)
+		reraise(sig)
+		return false, ErrInterrupted
+	}
+}
+
+// Status — итог запуска задачи.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusPanic    Status = "panic"
+	StatusDeadlock Status = "deadlock" // рантайм Go: all goroutines are asleep
+	StatusTimeout  Status = "timeout"
+	StatusFatal    Status = "fatal" // прочие fatal error рантайма: concurrent map writes и т.п.
+	StatusBuild    Status = "build" // задача не скомпилировалась
+	StatusExit     Status = "exit"  // ненулевой код выхода без паники
+	StatusRace     Status = "race"  // race detector нашёл гонки
+)
+
+// Result — результат запуска задачи в отдельном процессе.
+type Result struct {
+	Status   Status
+	Message  string // сообщение паники или fatal error
+	ExitCode int
+	Duration time.Duration
+	Stdout   string
+	Stderr   string
+	Races    []Race // отчёты race detector'а (при запуске с -race)
+}
+
+// Exec запускает cmd с ограничением timeout, дублируя вывод в stdout/stderr
+// (если они не nil), и классифицирует исход. go run и go test сначала
+// собираются без ограничения времени: timeout относится только к задаче.
+func Exec(cmd *exec.Cmd, timeout time.Duration, stdout, stderr io.Writer) Result {
+	return ExecContext(context.Background(), cmd, timeout, stdout, stderr)
+}
+
+// ExecContext — Exec с отменой через ctx.
+func ExecContext(ctx context.Context, cmd *exec.Cmd, timeout time.Duration, stdout, stderr io.Writer) Result {
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = tee(&outBuf, stdout)
+	cmd.Stderr = tee(&errBuf, stderr)
+
+	start := time.Now()
+	cleanup, err := build(ctx, cmd, cmd.Stderr)
+	defer cleanup()
+	var timedOut bool
+	if err == nil {
+		start = time.Now()
+		timedOut, err = RunContext(ctx, cmd, timeout)
+	}
+	res := Result{
+		Duration: time.Since(start),
+		Stdout:   outBuf.String(),
+		Stderr:   errBuf.String(),
+	}
+	var exit *exec.ExitError
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	} else if errors.As(err, &exit) {
+		res.ExitCode = exit.ExitCode()
+	}
+	res.Status, res.Message = Classify(res.Stdout, res.Stderr, timedOut, err)
+	res.Races = ParseRaces(res.Stdout + res.Stderr)
+	return res
+}
+
+// build собирает программу, которую запускает cmd — go run или go test, —
+// и переключает cmd на собранный бинарник. Холодная сборка, особенно
+// с -race, бывает дольше самой задачи и не должна съедать её таймаут.
+// Вывод компилятора пишется в errOut; прочие команды не меняются.
+func build(ctx context.Context, cmd *exec.Cmd, errOut io.Writer) (cleanup func(), err error) {
+	cleanup = func() {}
+	if filepath.Base(cmd.Path) != "go" || len(cmd.Args) < 3 {
+		return cleanup, nil
+	}
+	mode := cmd.Args[1]
+	if mode != "run" && mode != "test" {
+		return cleanup, nil
+	}
+
+	dir, err := os.MkdirTemp("", "honey-build")
+	if err != nil {
+		return cleanup, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+	bin := filepath.Join(dir, "task")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+
+	// Флаги go run — флаги сборки; у go test сборке нужен только -race,
+	// остальные (-count=1) — флаги тестового бинарника.
+	buildArgs := []string{"build", "-o", bin}
+	if mode == "test" {
+		buildArgs = []string{"test", "-c", "-o", bin}
+	}
+	var runArgs []string
+	rest := cmd.Args[2:]
+	for ; len(rest) > 0 && strings.HasPrefix(rest[0], "-"); rest = rest[1:] {
+		if mode == "test" && rest[0] != "-race" {
+			runArgs = append(runArgs, "-test."+strings.TrimLeft(rest[0], "-"))
+			continue
+		}
+		buildArgs = append(buildArgs, rest[0])
+	}
+	// Пакет — список .go-файлов или один путь; дальше аргументы программы.
+	n := 0
+	for n < len(rest) && strings.HasSuffix(rest[n], ".go") {
+		n++
+	}
+	if n == 0 && len(rest) > 0 {
+		n = 1
+	}
+	buildArgs, runArgs = append(buildArgs, rest[:n]...), append(runArgs, rest[n:]...)
+
+	b := exec.CommandContext(ctx, "go", buildArgs...)
+	b.Dir, b.Env = cmd.Dir, cmd.Env
+	b.Stdout, b.Stderr = errOut, errOut
+	if err := b.Run(); err != nil {
+		return cleanup, err
+	}
+	if _, err := os.Stat(bin); err != nil {
+		// go test -c без тестовых файлов бинарника не собирает:
+		// пусть go test сам сообщит, что тестов нет.
+		return cleanup, nil
+	}
+	cmd.Path = bin
+	cmd.Args = append([]string{bin}, runArgs...)
+	return cleanup, nil
+}
+
+func tee(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// Classify определяет исход запуска по его выводу и ошибке ожидания процесса.
+// stdout смотрится, только если процесс завершился с ошибкой: go test пишет
+// паники туда, а у удачного запуска строка «panic: » — обычный вывод задачи.
+func Classify(stdout, stderr string, timedOut bool, err error) (Status, string) {
+	if timedOut {
+		return StatusTimeout, ""
+	}
+	out := stderr
+	if err != nil {
+		out = stdout + "\n" + stderr
+	}
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "fatal error: all goroutines are asleep - deadlock!"):
+			return StatusDeadlock, strings.TrimPrefix(line, "fatal error: ")
+		case strings.HasPrefix(line, "panic: "):
+			msg, _, _ := strings.Cut(strings.TrimPrefix(line, "panic: "), " [recovered")
+			return StatusPanic, msg
+		case strings.HasPrefix(line, "fatal error: "):
+			return StatusFatal, strings.TrimPrefix(line, "fatal error: ")
+		}
+	}
+	if n := strings.Count(out, raceBanner); n > 0 {
+		return StatusRace, i18n.Sprintf("найдено гонок: %d", n)
+	}
+	if err == nil {
+		return StatusOK, ""
+	}
+	if strings.HasPrefix(stderr, "# ") || strings.Contains(stderr, "\n# ") {
+		return StatusBuild, firstLine(stderr)
+	}
+	return StatusExit, err.Error()
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "# ") {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	exit := errors.New("exit status 2")
+	tests := []struct {
+		name     string
+		stdout   string
+		stderr   string
+		timedOut bool
+		err      error
+		status   Status
+		message  string
+	}{
+		{"ok", "", "", false, nil, StatusOK, ""},
+		{"stderr without error is ok", "", "log line\n", false, nil, StatusOK, ""},
+		{"timeout wins over everything", "", "panic: boom\n", true, exit, StatusTimeout, ""},
+		{"panic", "", "goroutine 1 [running]:\npanic: boom [recovered]\n", false, exit, StatusPanic, "boom"},
+		{"deadlock before generic fatal", "", "fatal error: all goroutines are asleep - deadlock!\n", false, exit, StatusDeadlock, "all goroutines are asleep - deadlock!"},
+		{"fatal error", "", "fatal error: concurrent map writes\n", false, exit, StatusFatal, "concurrent map writes"},
+		{"first line decides", "", "fatal error: concurrent map writes\npanic: later\n", false, exit, StatusFatal, "concurrent map writes"},
+		{"panic beats race report", "", "WARNING: DATA RACE\n==================\npanic: boom\n", false, exit, StatusPanic, "boom"},
+		{"race", "", "WARNING: DATA RACE\n==================\nWARNING: DATA RACE\n==================\n", false, exit, StatusRace, "найдено гонок: 2"},
+		{"build error", "", "# example/task\n./main.go:3:2: undefined: x\n", false, exit, StatusBuild, "./main.go:3:2: undefined: x"},
+		{"exit code", "", "usage\n", false, exit, StatusExit, "exit status 2"},
+		{"repanicked", "", "panic: boom [recovered, repanicked]\n", false, exit, StatusPanic, "boom"},
+		{"go test panic on stdout", "--- FAIL: TestX\npanic: boom\n", "", false, exit, StatusPanic, "boom"},
+		{"go test race on stdout", "WARNING: DATA RACE\n==================\n", "", false, exit, StatusRace, "найдено гонок: 1"},
+		{"panic text in output of a successful run", "panic: not really\n", "", false, nil, StatusOK, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, msg := Classify(tc.stdout, tc.stderr, tc.timedOut, tc.err)
+			if status != tc.status || msg != tc.message {
+				t.Fatalf("Classify = %s, %q; want %s, %q", status, msg, tc.status, tc.message)
+			}
+		})
+	}
+}
+
+func TestExecTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+	res := Exec(exec.Command("sleep", "10"), 50*time.Millisecond, nil, nil)
+	if res.Status != StatusTimeout {
+		t.Fatalf("Status = %s, want %s", res.Status, StatusTimeout)
+	}
+	if res.Duration > 5*time.Second {
+		t.Fatalf("Duration = %s, want the process killed at the timeout", res.Duration)
+	}
+}
+
+func TestExecGo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds Go programs")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module m\n\ngo 1.21\n",
+		"main.go":      "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println(os.Args[1:]) }\n",
+		"main_test.go": "package main\n\nimport \"testing\"\n\nfunc TestBoom(t *testing.T) { panic(\"boom\") }\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		status  Status
+		message string
+		stdout  string
+	}{
+		{"go run passes program arguments", []string{"run", "main.go", "7"}, StatusOK, "", "[7]\n"},
+		{"go test panic", []string{"test", "-count=1", "."}, StatusPanic, "boom", ""},
+		{"build error", []string{"run", "missing.go"}, StatusExit, "exit status 1", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command("go", tc.args...)
+			cmd.Dir = dir
+			res := Exec(cmd, 10*time.Second, nil, nil)
+			if res.Status != tc.status || res.Message != tc.message {
+				t.Fatalf("Status = %s, %q; want %s, %q\nstdout: %s\nstderr: %s", res.Status, res.Message, tc.status, tc.message, res.Stdout, res.Stderr)
+			}
+			if tc.stdout != "" && res.Stdout != tc.stdout {
+				t.Fatalf("Stdout = %q, want %q", res.Stdout, tc.stdout)
+			}
+		})
+	}
+}
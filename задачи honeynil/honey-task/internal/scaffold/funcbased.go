@@ -0,0 +1,261 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// tasksPerLine — сколько записей в строке литерала GetTasks, как в maps/tasks.go.
+const tasksPerLine = 10
+
+// table — файл func-based темы с литералом GetTasks и константой Count.
+type table struct {
+	file  string
+	src   []byte
+	fset  *token.FileSet
+	f     *ast.File
+	lit   *ast.CompositeLit
+	count *ast.BasicLit // nil, если константы Count в пакете нет
+}
+
+func parseTable(dir string) (*table, token.Position, error) {
+	file, err := getTasksFile(dir)
+	if err != nil {
+		return nil, token.Position{Filename: dir}, err
+	}
+	t := &table{file: file, fset: token.NewFileSet()}
+	if t.src, err = os.ReadFile(file); err != nil {
+		return nil, token.Position{Filename: file}, err
+	}
+	if t.f, err = parser.ParseFile(t.fset, file, t.src, parser.ParseComments); err != nil {
+		return nil, errorPos(file, err), err
+	}
+	if t.lit = tasksLiteral(t.f); t.lit == nil {
+		return nil, t.fset.Position(getTasksDecl(t.f).Pos()), fmt.Errorf("GetTasks должна возвращать литерал map[int]func(){...}")
+	}
+	t.count = countConst(t.f)
+	return t, token.Position{}, nil
+}
+
+// CheckNumbering проверяет, что задачи темы пронумерованы подряд с единицы,
+// а у func-based темы литерал GetTasks и константа Count с ними согласованы.
+// При ошибке pos указывает на место проблемы.
+func CheckNumbering(topic registry.Topic) (pos token.Position, err error) {
+	for i, n := range topic.Numbers {
+		if n != i+1 {
+			return token.Position{Filename: topic.TaskFile(n)},
+				fmt.Errorf("%w: в теме %s нет задачи %d (следующая — %d), исправьте нумерацию", ErrGap, topic.Name, i+1, n)
+		}
+	}
+	if topic.IsFileBased {
+		return token.Position{}, nil
+	}
+
+	t, pos, err := parseTable(topic.Dir)
+	if err != nil {
+		return pos, err
+	}
+	if node, err := checkRegistered(t.lit, topic.Numbers); err != nil {
+		return t.fset.Position(node.Pos()), err
+	}
+	if t.count != nil {
+		if n, _ := strconv.Atoi(t.count.Value); n != len(topic.Numbers) {
+			return t.fset.Position(t.count.Pos()), fmt.Errorf("Count = %d, а задач %d", n, len(topic.Numbers))
+		}
+	}
+	return token.Position{}, nil
+}
+
+// errorPos — место первой синтаксической ошибки или просто файл.
+func errorPos(file string, err error) token.Position {
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		return list[0].Pos
+	}
+	return token.Position{Filename: file}
+}
+
+// appendTask дописывает функцию taskN в файл темы с GetTasks и синхронно
+// обновляет константу Count и литерал GetTasks. Нумерация уже проверена
+// CheckNumbering. Возвращает путь к файлу.
+func appendTask(topic registry.Topic, num int) (string, error) {
+	t, _, err := parseTable(topic.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	// Правки вносятся в текст по смещениям из AST, с конца файла к началу,
+	// чтобы остальное оформление и комментарии не менялись.
+	type edit struct {
+		from, to int
+		text     string
+	}
+	edits := []edit{{
+		from: t.fset.Position(t.lit.Lbrace).Offset,
+		to:   t.fset.Position(t.lit.Rbrace).Offset + 1,
+		text: tasksMap(num),
+	}}
+	if t.count != nil {
+		edits = append(edits, edit{t.fset.Position(t.count.Pos()).Offset, t.fset.Position(t.count.End()).Offset, strconv.Itoa(num)})
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].from > edits[j].from })
+
+	out := string(t.src)
+	for _, e := range edits {
+		out = out[:e.from] + e.text + out[e.to:]
+	}
+	out = strings.TrimRight(out, "\n") + "\n\n" + predictFunc(num)
+	if name := t.f.Name.Name; !importsFmt(t.f) {
+		out = strings.Replace(out, "package "+name+"\n", "package "+name+"\n\nimport \"fmt\"\n", 1)
+	}
+
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		return "", fmt.Errorf("%s: не удалось отформатировать: %w", t.file, err)
+	}
+	return t.file, os.WriteFile(t.file, formatted, 0o644)
+}
+
+// getTasksFile ищет файл пакета темы с функцией GetTasks.
+func getTasksFile(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", err
+		}
+		if getTasksDecl(f) != nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s: функция GetTasks не найдена", dir)
+}
+
+func getTasksDecl(f *ast.File) *ast.FuncDecl {
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "GetTasks" {
+			return fd
+		}
+	}
+	return nil
+}
+
+// tasksLiteral — литерал из "return map[int]func(){...}" в GetTasks.
+func tasksLiteral(f *ast.File) *ast.CompositeLit {
+	fd := getTasksDecl(f)
+	if fd == nil || fd.Body == nil {
+		return nil
+	}
+	for _, stmt := range fd.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		if lit, ok := ret.Results[0].(*ast.CompositeLit); ok {
+			return lit
+		}
+	}
+	return nil
+}
+
+// checkRegistered сверяет литерал GetTasks с функциями задач: каждая taskN
+// зарегистрирована ровно один раз под своим номером. node — место ошибки.
+func checkRegistered(lit *ast.CompositeLit, nums []int) (node ast.Node, err error) {
+	seen := make(map[int]ast.Node)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return elt, fmt.Errorf("непонятная запись в GetTasks")
+		}
+		key, ok := kv.Key.(*ast.BasicLit)
+		if !ok || key.Kind != token.INT {
+			return elt, fmt.Errorf("ключ GetTasks должен быть числом")
+		}
+		n, _ := strconv.Atoi(key.Value)
+		if seen[n] != nil {
+			return elt, fmt.Errorf("%w: номер %d в GetTasks встречается дважды", ErrDuplicate, n)
+		}
+		seen[n] = elt
+		if id, ok := kv.Value.(*ast.Ident); !ok || id.Name != "task"+strconv.Itoa(n) {
+			return elt, fmt.Errorf("номер %d в GetTasks указывает не на task%d", n, n)
+		}
+	}
+	for _, n := range nums {
+		if seen[n] == nil {
+			return lit, fmt.Errorf("task%d не зарегистрирована в GetTasks", n)
+		}
+		delete(seen, n)
+	}
+	for n, elt := range seen {
+		return elt, fmt.Errorf("%w: в GetTasks есть номер %d, а функции task%d нет", ErrGap, n, n)
+	}
+	return nil, nil
+}
+
+// tasksMap — литерал GetTasks для задач 1..n.
+func tasksMap(n int) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i := 1; i <= n; i++ {
+		if i%tasksPerLine == 1 {
+			b.WriteString("\t\t")
+		}
+		fmt.Fprintf(&b, "%d: task%d,", i, i)
+		if i%tasksPerLine == 0 || i == n {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("\t}")
+	return b.String()
+}
+
+// countConst — значение константы Count пакета, если она есть.
+func countConst(f *ast.File) *ast.BasicLit {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if name.Name != "Count" || i >= len(vs.Values) {
+					continue
+				}
+				if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.INT {
+					return lit
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func importsFmt(f *ast.File) bool {
+	for _, imp := range f.Imports {
+		if imp.Path.Value == `"fmt"` && imp.Name == nil {
+			return true
+		}
+	}
+	return false
+}
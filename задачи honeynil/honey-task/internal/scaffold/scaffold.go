@@ -0,0 +1,166 @@
+// Package scaffold создаёт заготовки новых задач и тем: следующий каталог
+// taskNNN или функцию taskN с шаблоном комментария, блоком ответа или ТЗ
+// и заготовкой теста. Нумерация задач проверяется: пропуски и дубли — ошибка.
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// Kind — вид задачи, от него зависит шаблон.
+type Kind string
+
+const (
+	Predict   Kind = "predict"   // "Что выведет?" с блоком OUTPUT/ОТВЕТ
+	Implement Kind = "implement" // реализовать тип или систему, с тестами
+	Review    Kind = "review"    // код-ревью по ТЗ
+	Algo      Kind = "algo"      // алгоритмическая задача, с тестами
+)
+
+// ParseKind проверяет значение флага --kind; пустое значение допустимо.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case "", Predict, Implement, Review, Algo:
+		return k, nil
+	}
+	return "", fmt.Errorf("неизвестный вид задачи %q: ожидается predict, implement, review или algo", s)
+}
+
+// DefaultKind — вид задачи, если --kind не указан: для func-based тем это
+// всегда "Что выведет?", для file-based — по названию темы.
+func DefaultKind(topic registry.Topic) Kind {
+	switch {
+	case !topic.IsFileBased:
+		return Predict
+	case topic.Name == "algo":
+		return Algo
+	case topic.Name == "code-review":
+		return Review
+	}
+	return Implement
+}
+
+var (
+	// ErrGap — в нумерации задач темы есть пропуск.
+	ErrGap = errors.New("пропуск в нумерации задач")
+	// ErrDuplicate — задача со следующим номером уже есть или номера расходятся с GetTasks/Count.
+	ErrDuplicate = errors.New("задача уже существует")
+)
+
+var (
+	topicNameRe   = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	packageNameRe = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+)
+
+// Result — что создано.
+type Result struct {
+	Topic    string
+	Num      int
+	NewTopic bool
+	Files    []string // созданные или изменённые файлы
+}
+
+// New добавляет следующую задачу вида kind в тему name; если темы нет,
+// она создаётся: "Что выведет?" — пакетом с GetTasks, остальные — file-based.
+// Пустой kind — DefaultKind темы.
+func New(reg *registry.Registry, name string, kind Kind) (Result, error) {
+	topic, ok := reg.Topic(name)
+	if !ok {
+		return newTopic(reg.Root, name, kind)
+	}
+	if kind == "" {
+		kind = DefaultKind(topic)
+	}
+
+	if pos, err := CheckNumbering(topic); err != nil {
+		return Result{}, fmt.Errorf("%s: %w", pos, err)
+	}
+	num := len(topic.Numbers) + 1
+	res := Result{Topic: name, Num: num}
+	if !topic.IsFileBased {
+		if kind != Predict {
+			return Result{}, fmt.Errorf("%s: в func-based теме задачи только вида %s — для %s нужна file-based тема", name, Predict, kind)
+		}
+		file, err := appendTask(topic, num)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Files = []string{file}
+		return res, nil
+	}
+
+	var err error
+	res.Files, err = writeTaskDir(topic.TaskDir(num), num, kind)
+	return res, err
+}
+
+func newTopic(root, name string, kind Kind) (Result, error) {
+	if !topicNameRe.MatchString(name) {
+		return Result{}, fmt.Errorf("некорректное имя темы %q: строчные латинские буквы, цифры и дефис", name)
+	}
+	dir := filepath.Join(root, name)
+	if _, err := os.Stat(dir); err == nil {
+		return Result{}, fmt.Errorf("каталог %s уже существует, но задач в нём не найдено", name)
+	}
+	if kind == "" {
+		kind = Predict
+	}
+	res := Result{Topic: name, Num: 1, NewTopic: true}
+
+	if kind == Predict {
+		if !packageNameRe.MatchString(name) {
+			return Result{}, fmt.Errorf("тема %q станет пакетом Go: имя без дефисов", name)
+		}
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			return Result{}, err
+		}
+		file := filepath.Join(dir, "tasks.go")
+		if err := os.WriteFile(file, []byte(packageSource(name)), 0o644); err != nil {
+			return Result{}, err
+		}
+		res.Files = []string{file}
+		return res, nil
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return Result{}, err
+	}
+	var err error
+	res.Files, err = writeTaskDir(filepath.Join(dir, "task001"), 1, kind)
+	return res, err
+}
+
+// writeTaskDir создаёт каталог file-based задачи с main.go и, для задач
+// на реализацию, заготовкой main_test.go.
+func writeTaskDir(dir string, num int, kind Kind) ([]string, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%w: каталог %s уже есть", ErrDuplicate, dir)
+	}
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, err
+	}
+	files := map[string]string{"main.go": mainSource(num, kind)}
+	if test := testSource(kind); test != "" {
+		files["main_test.go"] = test
+	}
+
+	var written []string
+	for _, name := range []string{"main.go", "main_test.go"} {
+		src, ok := files[name]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
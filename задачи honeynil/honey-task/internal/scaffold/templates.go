@@ -0,0 +1,138 @@
+package scaffold
+
+import "fmt"
+
+// Шаблоны повторяют оформление существующих задач: заголовок комментария
+// "ЗАДАЧА N: ..." или "Задача: ...", блок ответа с выводом через табуляцию
+// (пояснения после ←) и ТЗ для код-ревью. TODO отмечают места, которые
+// автор должен заполнить.
+
+// predictFunc — функция taskN для func-based темы.
+func predictFunc(num int) string {
+	return fmt.Sprintf(`// ЗАДАЧА %d: Что выведет?
+// OUTPUT:
+//
+//	TODO   ← ожидаемый вывод построчно, пояснение после стрелки
+func task%d() {
+	fmt.Println("TODO")
+}
+`, num, num)
+}
+
+// packageSource — tasks.go новой func-based темы с первой задачей.
+func packageSource(name string) string {
+	return fmt.Sprintf(`// Package %s — TODO: описание темы
+package %s
+
+import "fmt"
+
+const Count = 1
+
+func GetTasks() map[int]func() {
+	return map[int]func(){
+		1: task1,
+	}
+}
+
+%s`, name, name, predictFunc(1))
+}
+
+// mainSource — main.go file-based задачи.
+func mainSource(num int, kind Kind) string {
+	switch kind {
+	case Predict:
+		return fmt.Sprintf(`// ЗАДАЧА %d: TODO — тема задачи
+// Что выведет код?
+//
+// ОТВЕТ:
+//
+//	TODO   ← ожидаемый вывод построчно, пояснение после стрелки
+//
+// Объяснение:
+// - TODO
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("TODO")
+}
+`, num)
+	case Review:
+		return `package main
+
+import "fmt"
+
+// ТЗ: TODO — что должна делать программа.
+// Найдите проблемы в коде и предложите исправления.
+
+func main() {
+	fmt.Println("TODO")
+}
+`
+	case Algo:
+		return `package main
+
+// Задача: TODO — условие.
+//
+// in:  TODO
+// out: TODO
+
+import "fmt"
+
+func solve(in []int) int {
+	return 0
+}
+
+func main() {
+	fmt.Println(solve([]int{1, 2, 3}))
+}
+`
+	}
+	return `package main
+
+// Задача: TODO — что нужно реализовать.
+
+import "fmt"
+
+func solve(in []int) int {
+	return 0
+}
+
+func main() {
+	fmt.Println(solve([]int{1, 2, 3}))
+}
+`
+}
+
+// testSource — заготовка main_test.go; у "Что выведет?" проверка — блок
+// ответа (команда check), у код-ревью тестов нет.
+func testSource(kind Kind) string {
+	if kind != Implement && kind != Algo {
+		return ""
+	}
+	return `package main
+
+import "testing"
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want int
+	}{
+		// TODO: примеры из условия и граничные случаи
+	}
+	if len(tests) == 0 {
+		t.Skip("TODO: добавьте случаи")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := solve(tt.in); got != tt.want {
+				t.Errorf("solve(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+`
+}
@@ -0,0 +1,476 @@
+// Package taskdoc разбирает комментарии задач: заголовок, блоки с ответом
+// (// OUTPUT: для func-based тем, // ОТВЕТ: для file-based), а также
+// подсказки (// HINT 1: ...) и переводы (// TITLE (en): ...), которые не
+// показываются вместе с условием.
+package taskdoc
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// Doc — разобранный комментарий задачи.
+type Doc struct {
+	Title   string // первая строка комментария ("ЗАДАЧА 1: Что выведет?")
+	Text    string // текст комментария без маркеров //
+	Pos     token.Position
+	Answers []Answer
+	Hints   []string // подсказки по порядку номеров; из Text они убраны
+	// Translations — переводы заголовка и объяснения; из Text они убраны.
+	Translations map[i18n.Lang]Translation
+}
+
+// Translation — перевод задачи, который пишется в комментарии рядом
+// с оригиналом:
+//
+//	// TITLE (en): Stopping a goroutine with a done channel
+//	// EXPLANATION (en): both channels are unbuffered, so every send
+//	// waits for the goroutine ...
+type Translation struct {
+	Title       string
+	Explanation string
+}
+
+// Answer — блок с ожидаемым выводом.
+type Answer struct {
+	Label     string   // пояснение в скобках: "порядок НЕДЕТЕРМИНИРОВАН", "Go 1.22+"
+	Inline    string   // ответ в той же строке: "ОТВЕТ: DEADLOCK / программа зависнет"
+	Lines     []string // ожидаемые строки вывода без пояснений после ←
+	Unordered bool     // порядок вывода не определён — сравнивать как множество
+	Unchecked bool     // вывод недетерминирован настолько, что сверять его нельзя
+
+	empty bool   // ответ "(ничего)": задача ничего не выводит
+	goOp  string // условие на версию Go из Label: "<", ">=" ...
+	goVer int    // минорная версия Go 1.x для условия
+}
+
+// Особые значения строк ответа: пустая строка вывода и отсутствие вывода.
+const (
+	emptyLine = "(пустая строка)"
+	noOutput  = "(ничего)"
+)
+
+var (
+	answerRe = regexp.MustCompile(`^(OUTPUT|ОТВЕТ)\s*(?:\(([^)]*)\))?\s*:\s*(.*)$`)
+	hintRe   = regexp.MustCompile(`^(?:HINT|ПОДСКАЗКА)\s*(\d+)\s*:\s*(.*)$`)
+	trRe     = regexp.MustCompile(`^(TITLE|EXPLANATION)\s*\(([a-z]{2})\)\s*:\s*(.*)$`)
+	goVerRe  = regexp.MustCompile(`Go\s*(<=|>=|<|>)?\s*1\.(\d+)(\+)?`)
+)
+
+// Parse разбирает комментарий задачи num темы topic.
+func Parse(topic registry.Topic, num int) (*Doc, error) {
+	file := topic.TaskFile(num)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	cg := docComment(f, topic.TaskFunc(num))
+	if cg == nil {
+		return nil, fmt.Errorf("%s: у задачи %d нет комментария", file, num)
+	}
+
+	d := &Doc{Pos: fset.Position(cg.Pos())}
+	d.Text, d.Hints, d.Translations = splitHidden(cg.Text())
+	d.Title, _, _ = strings.Cut(strings.TrimSpace(d.Text), "\n")
+	d.Answers = parseAnswers(d.Text)
+	return d, nil
+}
+
+// docComment ищет комментарий задачи: doc-комментарий функции taskN или,
+// для main.go, комментарий файла (до или сразу после package).
+func docComment(f *ast.File, fn string) *ast.CommentGroup {
+	if fn != "main" {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == fn {
+				return fd.Doc
+			}
+		}
+		return nil
+	}
+	if f.Doc != nil {
+		return f.Doc
+	}
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Name.End() {
+			return cg
+		}
+	}
+	return nil
+}
+
+// splitHidden вынимает из текста комментария подсказки "HINT N: ..." и
+// переводы "TITLE (en): ...", "EXPLANATION (en): ...". Каждый маркер
+// продолжается на следующих непустых строках до следующего маркера.
+func splitHidden(text string) (rest string, hints []string, tr map[i18n.Lang]Translation) {
+	type hint struct {
+		n     int
+		lines []string
+	}
+	var (
+		found []hint
+		kept  []string
+		cur   *[]string // строки текущего маркера
+		field *string   // поле перевода, в которое пишется текущий маркер
+	)
+	flush := func() {
+		if field != nil {
+			*field = strings.Join(*cur, "\n")
+		}
+		cur, field = nil, nil
+	}
+	trs := map[i18n.Lang]*Translation{}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := hintRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			n, _ := strconv.Atoi(m[1])
+			found = append(found, hint{n: n, lines: []string{m[2]}})
+			cur = &found[len(found)-1].lines
+			continue
+		}
+		if m := trRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			lang := i18n.Lang(m[2])
+			if trs[lang] == nil {
+				trs[lang] = &Translation{}
+			}
+			field = &trs[lang].Explanation
+			if m[1] == "TITLE" {
+				field = &trs[lang].Title
+			}
+			cur = &[]string{m[3]}
+			continue
+		}
+		if cur != nil && trimmed != "" && !answerRe.MatchString(line) {
+			*cur = append(*cur, trimmed)
+			continue
+		}
+		flush()
+		kept = append(kept, line)
+	}
+	flush()
+	if len(found) == 0 && len(trs) == 0 {
+		return text, nil, nil
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].n < found[j].n })
+	for _, h := range found {
+		hints = append(hints, strings.Join(h.lines, "\n"))
+	}
+	if len(trs) > 0 {
+		tr = make(map[i18n.Lang]Translation, len(trs))
+		for lang, t := range trs {
+			tr[lang] = *t
+		}
+	}
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n") + "\n", hints, tr
+}
+
+// StripHidden убирает из исходника комментарии, которые не показываются
+// вместе с условием: подсказки и переводы. Пустая строка комментария
+// перед ними убирается вместе с ними.
+func StripHidden(src []byte) []byte {
+	var (
+		kept   []string
+		hidden bool
+	)
+	for _, line := range strings.SplitAfter(string(src), "\n") {
+		body, comment := strings.CutPrefix(strings.TrimSpace(line), "//")
+		body = strings.TrimSpace(body)
+		switch {
+		case comment && (hintRe.MatchString(body) || trRe.MatchString(body)):
+			if n := len(kept); !hidden && n > 0 && strings.TrimSpace(kept[n-1]) == "//" {
+				kept = kept[:n-1]
+			}
+			hidden = true
+			continue
+		case hidden && comment && body != "" && !answerRe.MatchString(body):
+			continue
+		}
+		hidden = false
+		kept = append(kept, line)
+	}
+	return []byte(strings.Join(kept, ""))
+}
+
+func parseAnswers(text string) []Answer {
+	var (
+		answers []Answer
+		cur     *Answer
+		blank   bool // пустая строка после строк ответа завершает блок
+	)
+	for _, line := range strings.Split(text, "\n") {
+		if m := answerRe.FindStringSubmatch(line); m != nil {
+			answers = append(answers, newAnswer(m[2], m[3]))
+			cur, blank = &answers[len(answers)-1], false
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if !strings.HasPrefix(line, "\t") {
+			if strings.TrimSpace(line) != "" || blank {
+				cur = nil
+			} else if len(cur.Lines) > 0 {
+				blank = true
+			}
+			continue
+		}
+		if blank {
+			cur = nil
+			continue
+		}
+		body := line[1:]
+		if i := strings.Index(body, "←"); i >= 0 {
+			body = body[:i]
+		}
+		body = strings.TrimRight(body, " \t")
+		switch strings.TrimSpace(body) {
+		case "":
+			continue // строка только с пояснением
+		case emptyLine:
+			body = ""
+		case noOutput:
+			cur.empty = true
+			continue
+		}
+		cur.Lines = append(cur.Lines, body)
+	}
+	return answers
+}
+
+func newAnswer(label, inline string) Answer {
+	a := Answer{Label: label, Inline: strings.TrimSpace(inline)}
+	lower := strings.ToLower(label)
+	a.Unordered = strings.Contains(lower, "недетерминир")
+	a.Unchecked = strings.Contains(lower, "не проверяется")
+	if m := goVerRe.FindStringSubmatch(label); m != nil {
+		a.goVer, _ = strconv.Atoi(m[2])
+		switch {
+		case m[1] != "":
+			a.goOp = m[1]
+		case m[3] == "+":
+			a.goOp = ">="
+		default:
+			a.goOp = "=="
+		}
+	}
+	return a
+}
+
+// Matches сообщает, подходит ли ответ для минорной версии Go 1.minor.
+func (a Answer) Matches(minor int) bool {
+	switch a.goOp {
+	case "<":
+		return minor < a.goVer
+	case "<=":
+		return minor <= a.goVer
+	case ">":
+		return minor > a.goVer
+	case ">=":
+		return minor >= a.goVer
+	case "==":
+		return minor == a.goVer
+	}
+	return true
+}
+
+// Blank сообщает, что блок ответа пуст: ни строк вывода, ни ответа в той же
+// строке, ни "(ничего)", и он не помечен как непроверяемый.
+func (a Answer) Blank() bool {
+	return len(a.Lines) == 0 && a.Inline == "" && !a.empty && !a.Unchecked
+}
+
+// Expected возвращает блок с ожидаемым выводом для версии Go 1.minor.
+// Блоки без строк вывода (например "ОТВЕТ: DEADLOCK") не учитываются;
+// "(ничего)" считается пустым выводом.
+func (d *Doc) Expected(minor int) (Answer, bool) {
+	for _, a := range d.Answers {
+		if (len(a.Lines) > 0 || a.empty) && a.Matches(minor) {
+			return a, true
+		}
+	}
+	return Answer{}, false
+}
+
+// Question возвращает формулировку задачи — текст комментария до первого
+// блока с ответом, без подсказок и объяснений.
+func (d *Doc) Question() string {
+	var lines []string
+	for _, line := range strings.Split(d.Text, "\n") {
+		if answerRe.MatchString(line) {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Solution возвращает текст комментария начиная с первого блока ответа:
+// ответ и объяснение к нему. Пусто, если блоков ответа нет.
+func (d *Doc) Solution() string {
+	lines := strings.Split(d.Text, "\n")
+	for i, line := range lines {
+		if answerRe.MatchString(line) {
+			return strings.TrimSpace(strings.Join(lines[i:], "\n"))
+		}
+	}
+	return ""
+}
+
+// TitleIn — заголовок на языке lang: перевод, если он есть, иначе оригинал.
+func (d *Doc) TitleIn(lang i18n.Lang) string {
+	if t := d.Translations[lang].Title; t != "" {
+		return t
+	}
+	return d.Title
+}
+
+// QuestionIn — формулировка задачи на языке lang. Переводится только
+// заголовок, поэтому без перевода это Question.
+func (d *Doc) QuestionIn(lang i18n.Lang) string {
+	if t := d.Translations[lang].Title; t != "" {
+		return t
+	}
+	return d.Question()
+}
+
+// ExplanationIn — объяснение на языке lang: переведённый заголовок,
+// объяснение и блоки ответа без пояснений после ←; без перевода — весь
+// комментарий, как в Text.
+func (d *Doc) ExplanationIn(lang i18n.Lang) string {
+	tr, ok := d.Translations[lang]
+	if !ok || tr.Explanation == "" {
+		return d.Text
+	}
+	parts := []string{d.TitleIn(lang), tr.Explanation}
+	for _, a := range d.Answers {
+		block := "OUTPUT"
+		if a.Label != "" {
+			block += " (" + a.Label + ")"
+		}
+		block += ": " + a.Inline
+		block = strings.TrimSpace(block)
+		for _, l := range a.Lines {
+			block += "\n\t" + l
+		}
+		parts = append(parts, block)
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// Source возвращает исходник задачи без комментариев, чтобы по нему нельзя
+// было подсмотреть ответ. Для func-based тем это функция taskN и объявления
+// пакета, на которые она ссылается; для file-based — весь main.go.
+// Исходник печатается как после gofmt, с выравниванием пробелами: табы
+// в терминале и браузере разъезжаются по колонкам.
+func Source(topic registry.Topic, num int) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, topic.TaskFile(num), nil, 0)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if topic.IsFileBased {
+		if err := format.Node(&buf, fset, f); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	decls, methods := topLevelDecls(f)
+	fn, ok := decls[topic.TaskFunc(num)]
+	if !ok {
+		return "", fmt.Errorf("%s: функция %s не найдена", topic.TaskFile(num), topic.TaskFunc(num))
+	}
+
+	// Собираем транзитивно все объявления пакета, упомянутые в задаче,
+	// и печатаем их в порядке следования в файле.
+	seen := map[ast.Decl]bool{fn: true}
+	queue := []ast.Decl{fn}
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+		ast.Inspect(d, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			deps := methods[id.Name]
+			if dep, ok := decls[id.Name]; ok {
+				deps = append(deps, dep)
+			}
+			for _, dep := range deps {
+				if !seen[dep] {
+					seen[dep] = true
+					queue = append(queue, dep)
+				}
+			}
+			return true
+		})
+	}
+	for _, d := range f.Decls {
+		if !seen[d] {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n\n")
+		}
+		if err := format.Node(&buf, fset, d); err != nil {
+			return "", err
+		}
+	}
+	buf.WriteString("\n")
+	return buf.String(), nil
+}
+
+// topLevelDecls индексирует функции, типы, переменные и константы файла
+// по имени, а методы — по имени типа-получателя.
+func topLevelDecls(f *ast.File) (decls map[string]ast.Decl, methods map[string][]ast.Decl) {
+	decls = make(map[string]ast.Decl)
+	methods = make(map[string][]ast.Decl)
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				decls[d.Name.Name] = d
+				continue
+			}
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				methods[id.Name] = append(methods[id.Name], d)
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					decls[s.Name.Name] = d
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						decls[name.Name] = d
+					}
+				}
+			}
+		}
+	}
+	return decls, methods
+}
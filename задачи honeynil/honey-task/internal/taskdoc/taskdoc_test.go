@@ -0,0 +1,202 @@
+package taskdoc
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/honeynil/honey-task/internal/registry"
+)
+
+// funcTopic создаёт модуль с func-based темой из одного файла src.
+func funcTopic(t *testing.T, src string) registry.Topic {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module m\n\ngo 1.25\n",
+		"tasks/tasks.go": src,
+	}
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg, err := registry.Discover(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, ok := reg.Topic("tasks")
+	if !ok {
+		t.Fatal("topic tasks not discovered")
+	}
+	return topic
+}
+
+func TestSourceAlignsWithSpaces(t *testing.T) {
+	topic := funcTopic(t, `package tasks
+
+func GetTasks() map[int]func() { return map[int]func(){1: task1} }
+
+type key struct{ a, b int }
+
+// ЗАДАЧА 1: Что выведет?
+// OUTPUT:
+//	A
+func task1() {
+	m := map[key]string{
+		{1, 2}: "A", // ответ
+		{10, 20}: "B",
+	}
+	println(m[key{1, 2}])
+}
+
+func unrelated() {}
+`)
+	got, err := Source(topic, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `type key struct{ a, b int }
+
+func task1() {
+	m := map[key]string{
+		{1, 2}:   "A",
+		{10, 20}: "B",
+	}
+	println(m[key{1, 2}])
+}
+`
+	if got != want {
+		t.Fatalf("Source =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseAnswers(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		minor     int
+		wantOK    bool
+		want      []string
+		unordered bool
+		unchecked bool
+		label     string
+	}{
+		{
+			name:   "plain block",
+			text:   "ЗАДАЧА 1: Что выведет?\nOUTPUT:\n\t1\n\t2\n",
+			wantOK: true, want: []string{"1", "2"},
+		},
+		{
+			name:   "comment after arrow is dropped",
+			text:   "OUTPUT:\n\t[1 2]   ← append в ту же память\n\t       ← строка только с пояснением\n\t3\n",
+			wantOK: true, want: []string{"[1 2]", "3"},
+		},
+		{
+			name:   "empty line marker",
+			text:   "OUTPUT:\n\ta\n\t(пустая строка)\n\tb\n",
+			wantOK: true, want: []string{"a", "", "b"},
+		},
+		{
+			name:   "no output",
+			text:   "ОТВЕТ:\n\t(ничего)\n",
+			wantOK: true, want: nil,
+		},
+		{
+			name:   "blank line ends block",
+			text:   "OUTPUT:\n\t1\n\nОбъяснение:\n\t2\n",
+			wantOK: true, want: []string{"1"},
+		},
+		{
+			name:   "inline answer has no expected output",
+			text:   "ОТВЕТ: DEADLOCK / программа зависнет\n",
+			wantOK: false,
+		},
+		{
+			name:   "nondeterministic order",
+			text:   "OUTPUT (порядок НЕДЕТЕРМИНИРОВАН):\n\ta\n\tb\n",
+			wantOK: true, want: []string{"a", "b"}, unordered: true, label: "порядок НЕДЕТЕРМИНИРОВАН",
+		},
+		{
+			name:   "unchecked output",
+			text:   "OUTPUT (не проверяется: время):\n\t12:00\n",
+			wantOK: true, want: []string{"12:00"}, unchecked: true, label: "не проверяется: время",
+		},
+		{
+			name:  "version label picks new block",
+			text:  "OUTPUT (Go < 1.22):\n\t3 3 3\nOUTPUT (Go 1.22+):\n\t0 1 2\n",
+			minor: 25, wantOK: true, want: []string{"0 1 2"}, label: "Go 1.22+",
+		},
+		{
+			name:  "version label picks old block",
+			text:  "OUTPUT (Go < 1.22):\n\t3 3 3\nOUTPUT (Go 1.22+):\n\t0 1 2\n",
+			minor: 21, wantOK: true, want: []string{"3 3 3"}, label: "Go < 1.22",
+		},
+		{
+			name:  "exact version",
+			text:  "OUTPUT (Go 1.21):\n\told\nOUTPUT:\n\tnew\n",
+			minor: 22, wantOK: true, want: []string{"new"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Doc{Answers: parseAnswers(tc.text)}
+			a, ok := d.Expected(tc.minor)
+			if ok != tc.wantOK {
+				t.Fatalf("Expected ok = %v, want %v (answers %+v)", ok, tc.wantOK, d.Answers)
+			}
+			if !ok {
+				return
+			}
+			if !slices.Equal(a.Lines, tc.want) {
+				t.Errorf("Lines = %q, want %q", a.Lines, tc.want)
+			}
+			if a.Unordered != tc.unordered || a.Unchecked != tc.unchecked || a.Label != tc.label {
+				t.Errorf("Unordered, Unchecked, Label = %v, %v, %q; want %v, %v, %q",
+					a.Unordered, a.Unchecked, a.Label, tc.unordered, tc.unchecked, tc.label)
+			}
+		})
+	}
+}
+
+func TestAnswerBlank(t *testing.T) {
+	tests := []struct {
+		text  string
+		blank bool
+	}{
+		{"OUTPUT:\n", true},
+		{"OUTPUT:\n\n", true},
+		{"OUTPUT: panic\n", false},
+		{"OUTPUT:\n\t(ничего)\n", false},
+		{"OUTPUT (не проверяется):\n", false},
+	}
+	for _, tc := range tests {
+		if got := parseAnswers(tc.text)[0].Blank(); got != tc.blank {
+			t.Errorf("Blank() for %q = %v, want %v", tc.text, got, tc.blank)
+		}
+	}
+}
+
+func TestSplitHidden(t *testing.T) {
+	text := "ЗАДАЧА 1: Что выведет?\n" +
+		"HINT 2: вторая\n" +
+		"HINT 1: первая\n  продолжение\n" +
+		"TITLE (en): What is printed?\n" +
+		"EXPLANATION (en): because\n  slices share memory\n" +
+		"OUTPUT:\n\t1\n"
+	rest, hints, tr := splitHidden(text)
+	if want := "ЗАДАЧА 1: Что выведет?\nOUTPUT:\n\t1\n"; rest != want {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+	if want := []string{"первая\nпродолжение", "вторая"}; !slices.Equal(hints, want) {
+		t.Errorf("hints = %q, want %q", hints, want)
+	}
+	if got := tr["en"]; got.Title != "What is printed?" || got.Explanation != "because\nslices share memory" {
+		t.Errorf("translation = %+v", got)
+	}
+}
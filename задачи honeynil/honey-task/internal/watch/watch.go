@@ -0,0 +1,267 @@
+// Package watch перезапускает задачу при сохранении её файлов: опрашивает
+// каталог задачи, выжидает паузу между сохранениями, запускает задачу и её
+// тесты и печатает короткий итог и отличия вывода от предыдущего запуска.
+// Опрос вместо inotify — чтобы не тянуть зависимостей и работать везде.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/check"
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+)
+
+const (
+	// PollInterval — как часто проверяются файлы задачи.
+	PollInterval = 300 * time.Millisecond
+	// Debounce — сколько файлы должны не меняться, чтобы запустить задачу:
+	// редакторы сохраняют файл в несколько записей, а gofmt-on-save — ещё одной.
+	Debounce = 500 * time.Millisecond
+	// MaxDiffLines — сколько строк diff'а печатать, остальное сокращается.
+	MaxDiffLines = 40
+)
+
+// Watcher следит за одной задачей.
+type Watcher struct {
+	Root    string
+	Topic   registry.Topic
+	Num     int
+	Timeout time.Duration
+	Out     io.Writer
+
+	prev []string // stdout предыдущего запуска
+}
+
+// New создаёт наблюдателя за задачей num темы topic.
+func New(root string, topic registry.Topic, num int, timeout time.Duration, out io.Writer) *Watcher {
+	return &Watcher{Root: root, Topic: topic, Num: num, Timeout: timeout, Out: out}
+}
+
+// Run запускает задачу сразу и затем после каждого изменения файлов,
+// пока не отменён ctx.
+func (w *Watcher) Run(ctx context.Context) error {
+	dir := w.Topic.TaskDir(w.Num)
+	last, err := snapshot(dir)
+	if err != nil {
+		return err
+	}
+	w.runOnce(ctx)
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	var changed time.Time // время последнего изменения, которое ещё не запущено
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		cur, err := snapshot(dir)
+		if err != nil {
+			continue // файл могли удалить на время сохранения
+		}
+		if !sameFiles(last, cur) {
+			last, changed = cur, time.Now()
+			continue
+		}
+		if !changed.IsZero() && time.Since(changed) >= Debounce {
+			changed = time.Time{}
+			w.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce запускает задачу и тесты, если они есть, и печатает итог.
+func (w *Watcher) runOnce(ctx context.Context) {
+	cmd, cleanup, err := runner.Command(w.Root, w.Topic, w.Num)
+	if err != nil {
+		fmt.Fprintf(w.Out, "%s ERROR %v\n", clock(), err)
+		return
+	}
+	run := runner.ExecContext(ctx, cmd, w.Timeout, nil, nil)
+	cleanup()
+	if ctx.Err() != nil {
+		return
+	}
+
+	var tests *runner.Result
+	if testsFile := filepath.Join(w.Topic.TaskDir(w.Num), "main_test.go"); w.Topic.IsFileBased && exists(testsFile) {
+		res := runner.ExecContext(ctx, testCommand(w.Topic.TaskDir(w.Num)), w.Timeout, nil, nil)
+		if ctx.Err() != nil {
+			return
+		}
+		tests = &res
+	}
+
+	pass := passed(run) && (tests == nil || passed(*tests))
+	verdict := "PASS"
+	if !pass {
+		verdict = "FAIL"
+	}
+	line := fmt.Sprintf("%s %s %s/%d  run %s", clock(), verdict, w.Topic.Name, w.Num, summary(run, w.Timeout))
+	if tests != nil {
+		line += "  tests " + summary(*tests, w.Timeout)
+	}
+	fmt.Fprintln(w.Out, line)
+
+	if !passed(run) {
+		printDetails(w.Out, run)
+	}
+	if tests != nil && !passed(*tests) {
+		printTestFailures(w.Out, tests.Stdout+tests.Stderr)
+	}
+
+	// Несобравшаяся задача ничего не вывела: сравнивать не с чем, и
+	// следующий удачный запуск сравнивается с последним выводом.
+	if run.Status == runner.StatusBuild {
+		return
+	}
+	out := splitLines(run.Stdout)
+	printDiff(w.Out, w.prev, out)
+	w.prev = out
+}
+
+func testCommand(dir string) *exec.Cmd {
+	args := []string{"test", "-count=1"}
+	if runner.DetectRaces {
+		args = append(args, "-race")
+	}
+	cmd := exec.Command("go", append(args, ".")...)
+	cmd.Dir = dir
+	return cmd
+}
+
+func passed(res runner.Result) bool {
+	return res.Status == runner.StatusOK && len(res.Races) == 0
+}
+
+// summary — "ok 312ms" или "panic 40ms".
+func summary(res runner.Result, timeout time.Duration) string {
+	if res.Status == runner.StatusTimeout {
+		return fmt.Sprintf("timeout %s", timeout)
+	}
+	return fmt.Sprintf("%s %s", res.Status, res.Duration.Round(time.Millisecond))
+}
+
+// printDetails печатает причину падения запуска: сообщение паники или
+// ошибки сборки и сводку гонок.
+func printDetails(w io.Writer, res runner.Result) {
+	if res.Status == runner.StatusBuild || res.Message == "" {
+		for _, l := range firstLines(res.Stderr, 10) {
+			fmt.Fprintf(w, "    %s\n", l)
+		}
+	} else {
+		fmt.Fprintf(w, "    %s\n", res.Message)
+	}
+	for _, r := range runner.RaceSummary(res.Races) {
+		fmt.Fprintf(w, "    DATA RACE: %s\n", r)
+	}
+}
+
+// printTestFailures печатает из вывода go test только упавшие тесты и их сообщения.
+func printTestFailures(w io.Writer, out string) {
+	n := 0
+	for _, l := range strings.Split(out, "\n") {
+		t := strings.TrimSpace(l)
+		if strings.HasPrefix(t, "--- FAIL") || strings.Contains(t, "_test.go:") || strings.HasPrefix(t, "panic:") {
+			fmt.Fprintf(w, "    %s\n", t)
+			if n++; n == MaxDiffLines {
+				return
+			}
+		}
+	}
+}
+
+// printDiff печатает только изменившиеся строки вывода относительно
+// предыдущего запуска; при первом запуске — весь вывод.
+func printDiff(w io.Writer, prev, cur []string) {
+	var changed []string
+	for _, l := range strings.Split(check.Diff(prev, cur), "\n") {
+		if strings.HasPrefix(l, "+ ") || strings.HasPrefix(l, "- ") {
+			changed = append(changed, l)
+		}
+	}
+	if len(changed) == 0 {
+		i18n.Fprintln(w, "    вывод не изменился")
+		return
+	}
+	for i, l := range changed {
+		if i == MaxDiffLines {
+			i18n.Fprintf(w, "    ... ещё %d строк\n", len(changed)-i)
+			break
+		}
+		fmt.Fprintf(w, "    %s\n", l)
+	}
+}
+
+// stamp — признаки изменения файла.
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// snapshot собирает отметки .go-файлов каталога задачи.
+func snapshot(dir string) (map[string]stamp, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]stamp)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name()] = stamp{info.ModTime(), info.Size()}
+	}
+	return files, nil
+}
+
+func sameFiles(a, b map[string]stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, s := range a {
+		if t, ok := b[name]; !ok || !t.mod.Equal(s.mod) || t.size != s.size {
+			return false
+		}
+	}
+	return true
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func clock() string {
+	return time.Now().Format("15:04:05")
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func firstLines(s string, n int) []string {
+	lines := splitLines(s)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return lines
+}
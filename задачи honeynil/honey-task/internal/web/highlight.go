@@ -0,0 +1,75 @@
+package web
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// builtins — предобъявленные идентификаторы, которые подсвечиваются отдельно.
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "copy": true, "delete": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true, "panic": true,
+	"print": true, "println": true, "recover": true,
+	"any": true, "bool": true, "byte": true, "comparable": true, "error": true, "float32": true,
+	"float64": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "uintptr": true, "true": true, "false": true, "nil": true, "iota": true,
+}
+
+// Highlight размечает исходник Go для подсветки: ключевые слова, строки,
+// числа, комментарии и встроенные идентификаторы оборачиваются в <span class>.
+// Текст между токенами копируется как есть, поэтому отступы сохраняются.
+func Highlight(src string) template.HTML {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	// Ошибки сканирования не важны: непонятный фрагмент просто останется без подсветки.
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // автоматически вставленная точка с запятой
+		}
+		class := tokenClass(tok, lit)
+		if class == "" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" {
+			end = start + len(tok.String())
+		}
+		b.WriteString(html.EscapeString(src[last:start]))
+		b.WriteString(`<span class="` + class + `">`)
+		b.WriteString(html.EscapeString(src[start:end]))
+		b.WriteString(`</span>`)
+		last = end
+	}
+	b.WriteString(html.EscapeString(src[last:]))
+	return template.HTML(b.String())
+}
+
+func tokenClass(tok token.Token, lit string) string {
+	switch {
+	case tok.IsKeyword():
+		return "kw"
+	case tok == token.COMMENT:
+		return "com"
+	case tok == token.STRING || tok == token.CHAR:
+		return "str"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "num"
+	case tok == token.IDENT && builtins[lit]:
+		return "bi"
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+// Package web — локальный веб-интерфейс тренажёра: список тем, задачи
+// с подсветкой синтаксиса, ответы под спойлером и запуск задач тем же
+// раннером, что и в CLI, с выводом в браузер через Server-Sent Events.
+// Всё, что нужно странице, встроено в бинарник — внешних ресурсов нет.
+package web
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/honeynil/honey-task/internal/meta"
+	"github.com/honeynil/honey-task/internal/practice"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+	"github.com/honeynil/honey-task/internal/taskdoc"
+)
+
+//go:embed templates static
+var files embed.FS
+
+var pages = map[string]*template.Template{}
+
+func init() {
+	for _, name := range []string{"index", "topic", "task"} {
+		pages[name] = template.Must(template.ParseFS(files, "templates/layout.html", "templates/"+name+".html"))
+	}
+}
+
+// MaxRuns — сколько задач сервер запускает одновременно; остальные запросы
+// получают 429, чтобы общий сервер не лёг от десятка go run сразу.
+const MaxRuns = 4
+
+// Server отдаёт страницы и запускает задачи из реестра.
+type Server struct {
+	Reg     *registry.Registry
+	Timeout time.Duration // как --timeout в CLI; 0 — без ограничения
+
+	runs chan struct{}
+}
+
+// New создаёт сервер поверх реестра тем.
+func New(reg *registry.Registry, timeout time.Duration) *Server {
+	return &Server{Reg: reg, Timeout: timeout, runs: make(chan struct{}, MaxRuns)}
+}
+
+// Handler возвращает маршруты сервера.
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(files, "static")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /topics/{topic}", s.topic)
+	mux.HandleFunc("GET /topics/{topic}/{num}", s.task)
+	mux.HandleFunc("GET /run/{topic}/{num}", s.run)
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	return mux
+}
+
+// Stylesheet — стили страниц, для документов, которые должны открываться
+// без сервера.
+func Stylesheet() template.CSS {
+	data, _ := files.ReadFile("static/style.css")
+	return template.CSS(data)
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	render(w, "index", s.Reg.Topics())
+}
+
+type topicPage struct {
+	Topic registry.Topic
+	Tasks []meta.Meta
+}
+
+func (s *Server) topic(w http.ResponseWriter, r *http.Request) {
+	topic, ok := s.Reg.Topic(r.PathValue("topic"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	tasks, err := meta.Load(topic)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render(w, "topic", topicPage{Topic: topic, Tasks: tasks})
+}
+
+type taskPage struct {
+	Topic      registry.Topic
+	Num        int
+	Title      string
+	Prev, Next int // соседние задачи темы, 0 — нет
+	Statement  string
+	Code       template.HTML // исходник, который показывается сразу
+	Solution   template.HTML // эталонное решение — под спойлером
+	Answer     string        // ответ из комментария — под спойлером
+}
+
+func (s *Server) task(w http.ResponseWriter, r *http.Request) {
+	topic, num, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	page := taskPage{Topic: topic, Num: num}
+	for i, n := range topic.Numbers {
+		if n != num {
+			continue
+		}
+		if i > 0 {
+			page.Prev = topic.Numbers[i-1]
+		}
+		if i+1 < len(topic.Numbers) {
+			page.Next = topic.Numbers[i+1]
+		}
+	}
+
+	doc, docErr := taskdoc.Parse(topic, num)
+	if docErr == nil {
+		page.Title = meta.CleanTitle(doc.Title)
+	}
+	switch {
+	case docErr == nil && len(doc.Answers) > 0:
+		// "Что выведет?": код без комментариев, ответ под спойлером.
+		src, err := taskdoc.Source(topic, num)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page.Statement, page.Answer, page.Code = doc.Question(), doc.Text, Highlight(src)
+	default:
+		src, err := taskSource(topic, num)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if docErr == nil {
+			page.Statement = doc.Text
+		}
+		// Задача с тестами или самопроверками — main.go это эталон, его прячем.
+		// Код-ревью и прочие задачи без проверок показываем целиком.
+		if topic.IsFileBased && practice.CheckMode(topic, num) != practice.ModeRun {
+			page.Solution = Highlight(src)
+		} else {
+			page.Code = Highlight(src)
+		}
+	}
+	render(w, "task", page)
+}
+
+// taskSource — исходник с комментариями: весь main.go или функция задачи.
+func taskSource(topic registry.Topic, num int) (string, error) {
+	if !topic.IsFileBased {
+		return taskdoc.Source(topic, num)
+	}
+	data, err := os.ReadFile(topic.TaskFile(num))
+	return string(data), err
+}
+
+// runResult — итог запуска, последнее событие потока.
+type runResult struct {
+	Status   runner.Status `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	ExitCode int           `json:"exitCode"`
+	Duration string        `json:"duration"`
+	Races    []string      `json:"races,omitempty"`
+}
+
+// run запускает задачу и стримит её stdout/stderr событиями "stdout" и "stderr";
+// последнее событие "result" — итог в JSON.
+func (s *Server) run(w http.ResponseWriter, r *http.Request) {
+	topic, num, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	select {
+	case s.runs <- struct{}{}:
+		defer func() { <-s.runs }()
+	default:
+		http.Error(w, "слишком много запущенных задач, попробуйте позже", http.StatusTooManyRequests)
+		return
+	}
+
+	cmd, cleanup, err := runner.Command(s.Reg.Root, topic, num)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cleanup()
+
+	ev := newEventStream(w)
+	res := runner.ExecContext(r.Context(), cmd, s.Timeout, ev.writer("stdout"), ev.writer("stderr"))
+	if r.Context().Err() != nil {
+		return // клиент ушёл, процесс уже остановлен
+	}
+
+	out := runResult{
+		Status:   res.Status,
+		Message:  res.Message,
+		ExitCode: res.ExitCode,
+		Duration: res.Duration.Round(time.Millisecond).String(),
+		Races:    runner.RaceSummary(res.Races),
+	}
+	if res.Status == runner.StatusTimeout {
+		out.Message = fmt.Sprintf("задача не завершилась за %s и была остановлена", s.Timeout)
+	}
+	data, _ := json.Marshal(out)
+	ev.send("result", string(data))
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (registry.Topic, int, bool) {
+	topic, ok := s.Reg.Topic(r.PathValue("topic"))
+	num, err := strconv.Atoi(r.PathValue("num"))
+	if !ok || err != nil || !topic.Has(num) {
+		http.NotFound(w, r)
+		return registry.Topic{}, 0, false
+	}
+	return topic, num, true
+}
+
+// render выполняет шаблон в буфер, чтобы ошибка не оставила полстраницы.
+func render(w http.ResponseWriter, page string, data any) {
+	var buf bytes.Buffer
+	if err := pages[page].ExecuteTemplate(&buf, "layout", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// eventStream пишет Server-Sent Events. stdout и stderr задачи копируются
+// из разных горутин, поэтому запись под мьютексом.
+type eventStream struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // чтобы прокси не копил поток
+	w.WriteHeader(http.StatusOK)
+	return &eventStream{w: w, rc: http.NewResponseController(w)}
+}
+
+// send отправляет событие; многострочные данные разбиваются на строки data:,
+// браузер склеит их обратно через "\n".
+func (e *eventStream) send(event, data string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", event)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return err
+	}
+	return e.rc.Flush()
+}
+
+// writer возвращает io.Writer, отправляющий каждый фрагмент вывода событием event.
+func (e *eventStream) writer(event string) io.Writer {
+	return streamWriter{e, event}
+}
+
+type streamWriter struct {
+	e     *eventStream
+	event string
+}
+
+// Write не возвращает ошибок отправки: если клиент ушёл, процесс задачи
+// остановит отмена контекста запроса, а вывод должен дойти до буфера раннера.
+func (s streamWriter) Write(p []byte) (int, error) {
+	s.e.send(s.event, string(p))
+	return len(p), nil
+}
@@ -0,0 +1,57 @@
+// Запуск задачи: вывод приходит событиями stdout/stderr, итог — событием result.
+(function () {
+  var button = document.getElementById("run");
+  if (!button) {
+    return;
+  }
+  var output = document.getElementById("output");
+  var status = document.getElementById("status");
+  var source = null;
+
+  function finish() {
+    source.close();
+    source = null;
+    button.disabled = false;
+  }
+
+  function append(cls) {
+    return function (e) {
+      var span = document.createElement("span");
+      span.className = cls;
+      span.textContent = e.data;
+      output.appendChild(span);
+    };
+  }
+
+  button.addEventListener("click", function () {
+    output.textContent = "";
+    status.textContent = "выполняется…";
+    status.className = "status";
+    button.disabled = true;
+
+    source = new EventSource(button.dataset.url);
+    source.addEventListener("stdout", append("stdout"));
+    source.addEventListener("stderr", append("stderr"));
+    source.addEventListener("result", function (e) {
+      var r = JSON.parse(e.data);
+      var text = r.status + " · " + r.duration;
+      if (r.message) {
+        text += " · " + r.message;
+      }
+      (r.races || []).forEach(function (race) {
+        text += "\nDATA RACE: " + race;
+      });
+      status.textContent = text;
+      status.className = "status status-" + r.status;
+      finish();
+    });
+    // Без этого EventSource переподключится и запустит задачу ещё раз.
+    source.onerror = function () {
+      if (source) {
+        status.textContent = "соединение прервано (сервер занят или недоступен)";
+        status.className = "status status-error";
+        finish();
+      }
+    };
+  });
+})();
@@ -1,195 +1,1242 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/honeynil/honey-task/internal/check"
+	"github.com/honeynil/honey-task/internal/export"
+	"github.com/honeynil/honey-task/internal/i18n"
+	"github.com/honeynil/honey-task/internal/interview"
+	"github.com/honeynil/honey-task/internal/lint"
+	"github.com/honeynil/honey-task/internal/meta"
+	"github.com/honeynil/honey-task/internal/output"
+	"github.com/honeynil/honey-task/internal/practice"
+	"github.com/honeynil/honey-task/internal/progress"
+	"github.com/honeynil/honey-task/internal/quiz"
+	"github.com/honeynil/honey-task/internal/registry"
+	"github.com/honeynil/honey-task/internal/runner"
+	"github.com/honeynil/honey-task/internal/scaffold"
+	"github.com/honeynil/honey-task/internal/watch"
+	"github.com/honeynil/honey-task/internal/web"
 	"github.com/honeynil/honey-task/maps"
 	"github.com/honeynil/honey-task/pointers"
 	"github.com/honeynil/honey-task/slices"
 	"github.com/honeynil/honey-task/structs"
 )
 
-type Topic struct {
-	Name        string
-	Tasks       map[int]func()
-	Count       int
-	Description string
-	IsFileBased bool // для тем где задачи в отдельных файлах
-}
-
-var topics = map[string]Topic{
-	"slices": {
-		Name:        "slices",
-		Tasks:       slices.GetTasks(),
-		Count:       slices.Count,
-		Description: "Слайсы в go",
-	},
-	"pointers": {
-		Name:        "pointers",
-		Tasks:       pointers.GetTasks(),
-		Count:       pointers.Count,
-		Description: "Указатели в go",
-	},
-	"maps": {
-		Name:        "maps",
-		Tasks:       maps.GetTasks(),
-		Count:       maps.Count,
-		Description: "Maps в go",
-	},
-	"structs": {
-		Name:        "structs",
-		Tasks:       structs.GetTasks(),
-		Count:       structs.Count,
-		Description: "Структуры в go",
-	},
-	"interface": {
-		Name:        "interface",
-		Tasks:       nil,
-		Count:       30,
-		Description: "Интерфейсы в go (реализация паттернов и систем)",
-		IsFileBased: true,
-	},
-	"concurrency": {
-		Name:        "concurrency",
-		Tasks:       nil,
-		Count:       30,
-		Description: "Конкурентность в go (goroutines, channels, sync)",
-		IsFileBased: true,
-	},
+// compiled — пакеты с GetTasks(), вкомпилированные в раннер: их задачи запускаются
+// в том же процессе. Остальные найденные на диске func-based темы запускаются
+// через сгенерированную программу-обёртку.
+var compiled = map[string]func() map[int]func(){
+	"maps":     maps.GetTasks,
+	"pointers": pointers.GetTasks,
+	"slices":   slices.GetTasks,
+	"structs":  structs.GetTasks,
+}
+
+type Topic = registry.Topic
+
+var reg *registry.Registry
+
+// options — глобальные флаги раннера; могут стоять в любом месте командной строки.
+type options struct {
+	timeout  time.Duration
+	race     bool
+	duration time.Duration
+	mix      string
+	addr     string
+	format   output.Format
+	kind     scaffold.Kind
+	anki     bool
+	book     bool
+	out      string
+}
+
+var opts = options{timeout: runner.Timeout, duration: time.Hour, addr: "localhost:8080", format: output.Text}
+
+// interactive — команды, которые ведут диалог, работают бесконечно или
+// пишут свой формат: машиночитаемого отчёта у них нет.
+var interactive = map[string]bool{
+	"quiz": true, "review": true, "practice": true, "interview": true, "serve": true, "new": true, "watch": true, "hint": true, "export": true,
 }
 
 func main() {
-	if len(os.Args) < 2 {
+	i18n.Set(i18n.FromEnv())
+	args, err := parseFlags(os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+	runner.Timeout = opts.timeout
+	runner.DetectRaces = opts.race
+
+	if len(args) < 1 {
 		printHelp()
 		return
 	}
 
-	topicName := os.Args[1]
+	if err := loadRegistry(); err != nil {
+		i18n.Printf("Не удалось найти задачи: %v\n", err)
+		os.Exit(1)
+	}
 
-	if topicName == "list" {
+	topicName := args[0]
+	if opts.format != output.Text && interactive[topicName] {
+		i18n.Fprintf(os.Stderr, "Команда %s поддерживает только --format=text\n", topicName)
+		os.Exit(2)
+	}
+
+	switch topicName {
+	case "list":
 		listTopics()
 		return
+	case "check":
+		runCheck(args[1:])
+		return
+	case "quiz":
+		runQuiz(args[1:])
+		return
+	case "review":
+		runReview()
+		return
+	case "stats":
+		printStats()
+		return
+	case "racecheck":
+		runRaceCheck(args[1:])
+		return
+	case "practice":
+		runPractice(args[1:])
+		return
+	case "verify":
+		runVerify(args[1:])
+		return
+	case "search":
+		runSearch(args[1:])
+		return
+	case "interview":
+		runInterview()
+		return
+	case "serve":
+		runServe()
+		return
+	case "new":
+		runNew(args[1:])
+		return
+	case "lint":
+		runLint()
+		return
+	case "watch":
+		runWatch(args[1:])
+		return
+	case "hint":
+		runHint(args[1:])
+		return
+	case "export":
+		runExport(args[1:])
+		return
 	}
 
-	topic, ok := topics[topicName]
+	topic, ok := reg.Topic(topicName)
 	if !ok {
-		fmt.Printf("Неизвестная тема: %s\n\n", topicName)
+		i18n.Printf("Неизвестная тема: %s\n\n", topicName)
 		printHelp()
 		return
 	}
 
-	if len(os.Args) < 3 {
+	if len(args) < 2 {
 		printTopicHelp(topic)
 		return
 	}
 
-	runTasks(topic, os.Args[2:])
+	runTasks(topic, args[1:])
+}
+
+// parseFlags разбирает флаги, перемежающиеся с позиционными аргументами,
+// и возвращает позиционные аргументы.
+func parseFlags(args []string) ([]string, error) {
+	// Описания флагов переводятся при регистрации, поэтому язык из --lang
+	// нужен до неё — иначе "--lang en --help" напечатал бы справку по-русски.
+	if l, ok := langArg(args); ok {
+		i18n.Set(l)
+	}
+	fs := flag.NewFlagSet("honey-task", flag.ContinueOnError)
+	fs.DurationVar(&opts.timeout, "timeout", opts.timeout, i18n.T("ограничение времени на задачу в отдельном процессе (0 — без ограничения)"))
+	fs.BoolVar(&opts.race, "race", opts.race, i18n.T("запускать задачи с race detector'ом (go run -race)"))
+	fs.DurationVar(&opts.duration, "duration", opts.duration, i18n.T("длительность собеседования (interview)"))
+	fs.StringVar(&opts.mix, "mix", opts.mix, i18n.T("состав собеседования: тема:число через запятую (interview)"))
+	fs.StringVar(&opts.addr, "addr", opts.addr, i18n.T("адрес веб-интерфейса (serve)"))
+	fs.Func("format", i18n.T("формат вывода: text, json или junit"), func(s string) (err error) {
+		opts.format, err = output.ParseFormat(s)
+		return err
+	})
+	fs.BoolVar(&opts.anki, "anki", opts.anki, i18n.T("выгрузить колоду Anki из задач «Что выведет?» (export)"))
+	fs.BoolVar(&opts.book, "book", opts.book, i18n.T("выгрузить книгу по всем темам (export)"))
+	fs.StringVar(&opts.out, "out", opts.out, i18n.T("файл выгрузки; .html — книга в HTML, иначе Markdown (export)"))
+	fs.Func("lang", i18n.T("язык сообщений: ru или en (по умолчанию из LANG)"), func(s string) error {
+		l, err := i18n.Parse(s)
+		i18n.Set(l)
+		return err
+	})
+	fs.Func("kind", i18n.T("вид новой задачи: predict, implement, review или algo (new)"), func(s string) (err error) {
+		opts.kind, err = scaffold.ParseKind(s)
+		return err
+	})
+
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return rest, nil
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+}
+
+// langArg ищет в args значение --lang (последнее, как и flag). Ошибку
+// в значении сообщит сам разбор флагов.
+func langArg(args []string) (lang i18n.Lang, ok bool) {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if name != "lang" {
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				continue
+			}
+			value = args[i+1]
+		}
+		if l, err := i18n.Parse(value); err == nil {
+			lang, ok = l, true
+		}
+	}
+	return lang, ok
+}
+
+func loadRegistry() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := registry.FindRoot(wd)
+	if err != nil {
+		return err
+	}
+	reg, err = registry.Discover(root, compiled)
+	return err
 }
 
 func printHelp() {
-	fmt.Println("Go Practice Tasks - Практические задачи по Go")
-	fmt.Println("\nИспользование:")
-	fmt.Println("  go run main.go <тема> <задачи>")
-	fmt.Println("\nКоманды:")
-	fmt.Println("  go run main.go list                    - список доступных тем")
-	fmt.Println("  go run main.go <тема>                  - справка по теме")
-	fmt.Println("  go run main.go <тема> <номер>          - запустить задачу")
-	fmt.Println("  go run main.go <тема> <номера>         - запустить несколько задач")
-	fmt.Println("  go run main.go <тема> all              - запустить все задачи")
-	fmt.Println("\nПримеры:")
-	fmt.Println("  go run main.go slices 1                - запустить задачу 1 по слайсам")
-	fmt.Println("  go run main.go slices 1 5 10           - запустить задачи 1, 5 и 10")
-	fmt.Println("  go run main.go slices all              - все задачи по слайсам")
-	fmt.Println("\nДоступные темы:")
-	for name, topic := range topics {
-		fmt.Printf("  %-10s - %s (%d задач)\n", name, topic.Description, topic.Count)
+	i18n.Println("Go Practice Tasks - Практические задачи по Go")
+	i18n.Println("\nИспользование:")
+	i18n.Println("  go run main.go <тема> <задачи>")
+	i18n.Println("\nКоманды:")
+	i18n.Println("  go run main.go list                    - список доступных тем")
+	i18n.Println("  go run main.go <тема>                  - справка по теме")
+	i18n.Println("  go run main.go <тема> <номер>          - запустить задачу")
+	i18n.Println("  go run main.go <тема> <номера>         - запустить несколько задач")
+	i18n.Println("  go run main.go <тема> all              - запустить все задачи")
+	i18n.Println("  go run main.go check <тема> [номера]   - сверить вывод с блоками // OUTPUT:")
+	i18n.Println("  go run main.go quiz <тема> [номера]    - угадать вывод до запуска задачи")
+	i18n.Println("  go run main.go review                  - повторить задачи, назначенные на сегодня")
+	i18n.Println("  go run main.go stats                   - прогресс по темам")
+	i18n.Println("  go run main.go racecheck [темы]        - убедиться, что эталонные решения без гонок")
+	i18n.Println("  go run main.go practice <тема> <номер> - заготовка задачи для самостоятельного решения")
+	i18n.Println("  go run main.go verify <тема> <номер>   - проверить своё решение")
+	i18n.Println("  go run main.go search <запрос>         - поиск задач: слова, tag:x, topic:x, difficulty:x")
+	i18n.Println("  go run main.go interview               - тренировочное собеседование с таймером и отчётом")
+	i18n.Println("  go run main.go serve                   - веб-интерфейс: задачи, ответы и запуск в браузере")
+	i18n.Println("  go run main.go new <тема>              - заготовка следующей задачи темы (или новой темы)")
+	i18n.Println("  go run main.go lint                    - проверить оформление задач: заголовки, ответы, нумерацию, сборку")
+	i18n.Println("  go run main.go watch <тема> <номер>    - перезапускать задачу и её тесты при сохранении")
+	i18n.Println("  go run main.go hint <тема> <номер>     - открыть следующую подсказку к задаче")
+	i18n.Println("  go run main.go export --anki [темы]    - колода Anki (TSV) из задач «Что выведет?»")
+	i18n.Println("  go run main.go export --book [темы]    - книга по темам: Markdown или HTML (--out book.html)")
+	i18n.Println("\nФлаги:")
+	i18n.Println("  --timeout=30s                          - ограничение времени на задачу (0 — без ограничения)")
+	i18n.Println("  --race                                 - запускать задачи с race detector'ом")
+	i18n.Println("  --duration=60m                         - длительность собеседования")
+	i18n.Println("  --mix=algo:1,concurrency:2,maps:3      - состав собеседования (по умолчанию по задаче из темы)")
+	i18n.Println("  --addr=localhost:8080                  - адрес веб-интерфейса")
+	i18n.Println("  --format=text|json|junit               - формат вывода для CI (кроме интерактивных команд)")
+	i18n.Println("  --kind=predict|implement|review|algo   - вид новой задачи (по умолчанию по теме)")
+	i18n.Println("  --lang=ru|en                           - язык сообщений (по умолчанию из LANG)")
+	i18n.Println("\nПримеры:")
+	i18n.Println("  go run main.go slices 1                - запустить задачу 1 по слайсам")
+	i18n.Println("  go run main.go slices 1 5 10           - запустить задачи 1, 5 и 10")
+	i18n.Println("  go run main.go slices all              - все задачи по слайсам")
+	fmt.Println("  go run main.go check maps --format=junit > report.xml")
+	if reg == nil {
+		return
 	}
+	i18n.Println("\nДоступные темы:")
+	for _, topic := range reg.Topics() {
+		i18n.Printf("  %-12s - %s (%d задач)\n", topic.Name, i18n.T(topic.Description), topic.Count)
+	}
+}
+
+// topicInfo — тема в выводе list --format=json.
+type topicInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+	FileBased   bool   `json:"file_based"`
 }
 
 func listTopics() {
-	fmt.Printf("Доступные темы:\n")
-	for name, topic := range topics {
-		fmt.Printf("  %-10s - %s (%d задач)\n", name, topic.Description, topic.Count)
+	if opts.format != output.Text {
+		topics := []topicInfo{}
+		for _, topic := range reg.Topics() {
+			topics = append(topics, topicInfo{topic.Name, topic.Description, topic.Count, topic.IsFileBased})
+		}
+		writeJSON("list", topics)
+		return
 	}
-	fmt.Println("\nИспользуйте: go run main.go <тема> для подробностей")
+	i18n.Printf("Доступные темы:\n")
+	for _, topic := range reg.Topics() {
+		i18n.Printf("  %-12s - %s (%d задач)\n", topic.Name, i18n.T(topic.Description), topic.Count)
+	}
+	i18n.Println("\nИспользуйте: go run main.go <тема> для подробностей")
 }
 
 func printTopicHelp(topic Topic) {
-	fmt.Printf("%s\n\n", topic.Description)
-	fmt.Printf("Доступно задач: %d\n\n", topic.Count)
+	fmt.Printf("%s\n\n", i18n.T(topic.Description))
+	i18n.Printf("Доступно задач: %d\n\n", topic.Count)
 
 	if topic.IsFileBased {
-		fmt.Println("Задачи находятся в отдельных файлах.")
-		fmt.Println("\nИспользование:")
-		fmt.Printf("  cd %s/\n", topic.Name)
-		fmt.Printf("  go run task001.go              - запустить задачу 1\n")
-		fmt.Printf("  go run task002.go              - запустить задачу 2\n")
-		fmt.Println("\nИли используйте:")
-		fmt.Printf("  go run main.go %s <номер>      - автоматически запустит файл задачи\n", topic.Name)
-		fmt.Println("\nПримеры:")
+		i18n.Println("Каждая задача — отдельный каталог taskNNN с main.go.")
+		i18n.Println("\nИспользование:")
+		for _, num := range topic.Numbers[:min(2, len(topic.Numbers))] {
+			rel, _ := filepath.Rel(reg.Root, topic.TaskDir(num))
+			i18n.Printf("  %-36s - запустить задачу %d\n", "cd "+rel+" && go run .", num)
+		}
+		i18n.Println("\nИли используйте:")
+		i18n.Printf("  go run main.go %s <номер>      - автоматически запустит файл задачи\n", topic.Name)
+		i18n.Println("\nПримеры:")
 		fmt.Printf("  go run main.go %s 1\n", topic.Name)
 		fmt.Printf("  go run main.go %s 5\n", topic.Name)
-		fmt.Printf("\nСмотрите %s/README.md для подробного списка задач.\n", topic.Name)
+		if _, err := os.Stat(filepath.Join(topic.Dir, "README.md")); err == nil {
+			i18n.Printf("\nСмотрите %s/README.md для подробного списка задач.\n", topic.Name)
+		}
 	} else {
-		fmt.Println("Использование:")
-		fmt.Printf("  go run main.go %s <номер>          - запустить одну задачу\n", topic.Name)
-		fmt.Printf("  go run main.go %s 1 5 10           - запустить несколько задач\n", topic.Name)
-		fmt.Printf("  go run main.go %s all              - запустить все задачи\n", topic.Name)
-		fmt.Println("\nПримеры:")
+		i18n.Println("Использование:")
+		i18n.Printf("  go run main.go %s <номер>          - запустить одну задачу\n", topic.Name)
+		i18n.Printf("  go run main.go %s 1 5 10           - запустить несколько задач\n", topic.Name)
+		i18n.Printf("  go run main.go %s all              - запустить все задачи\n", topic.Name)
+		i18n.Println("\nПримеры:")
 		fmt.Printf("  go run main.go %s 1\n", topic.Name)
 		fmt.Printf("  go run main.go %s 1 5 10\n", topic.Name)
 	}
 }
 
 func runTasks(topic Topic, args []string) {
-	if topic.IsFileBased {
-		runFileBasedTasks(topic, args)
+	nums := parseTaskNumbers(topic, args)
+
+	if opts.format != output.Text {
+		rep := output.NewReport("run")
+		for _, num := range nums {
+			rep.Add(runRecord(topic, num))
+		}
+		writeReport(rep)
 		return
 	}
 
-	for _, arg := range args {
-		num, err := strconv.Atoi(arg)
-		if err != nil || num < 1 || num > topic.Count {
-			fmt.Printf("Неверный номер задачи: %s (доступны 1-%d)\n", arg, topic.Count)
-			continue
-		}
+	if topic.IsFileBased {
+		runFileBasedTasks(topic, nums)
+		return
+	}
 
+	for _, num := range nums {
 		fmt.Println("\n" + strings.Repeat("=", 50))
-		fmt.Printf("ЗАДАЧА %d\n", num)
+		i18n.Printf("ЗАДАЧА %d\n", num)
 		fmt.Println(strings.Repeat("=", 50) + "\n")
 
-		if task, ok := topic.Tasks[num]; ok {
+		// С --race задачу нужно собрать заново, поэтому в процессе раннера её не запускаем.
+		if task, ok := topic.Tasks[num]; ok && !opts.race {
 			task()
+			continue
+		}
+		res, err := runProcess(topic, num)
+		if err != nil {
+			i18n.Printf("Ошибка при запуске задачи: %v\n", err)
+			continue
+		}
+		if res.Status != runner.StatusOK {
+			fmt.Printf("\n[%s] %s\n", res.Status, statusDetails(res))
 		}
 	}
 }
 
-func runFileBasedTasks(topic Topic, args []string) {
+// parseTaskNumbers разбирает номера задач из аргументов; "all" — все задачи темы.
+func parseTaskNumbers(topic Topic, args []string) []int {
+	var nums []int
 	for _, arg := range args {
+		if arg == "all" {
+			nums = append(nums, topic.Numbers...)
+			continue
+		}
 		num, err := strconv.Atoi(arg)
-		if err != nil || num < 1 || num > topic.Count {
-			fmt.Printf("Неверный номер задачи: %s (доступны 1-%d)\n", arg, topic.Count)
+		if err != nil || !topic.Has(num) {
+			i18n.Fprintf(os.Stderr, "Неверный номер задачи: %s (доступны %s)\n", arg, taskRange(topic))
 			continue
 		}
+		nums = append(nums, num)
+	}
+	return nums
+}
 
-		taskDir := fmt.Sprintf("%s/task%03d", topic.Name, num)
+func taskRange(topic Topic) string {
+	if len(topic.Numbers) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d-%d", topic.Numbers[0], topic.Numbers[len(topic.Numbers)-1])
+}
+
+func runFileBasedTasks(topic Topic, nums []int) {
+	results := make(map[int]runner.Result, len(nums))
+	for _, num := range nums {
+		taskDir := topic.TaskDir(num)
+		rel, _ := filepath.Rel(reg.Root, taskDir)
 
 		fmt.Println("\n" + strings.Repeat("=", 50))
-		fmt.Printf("ЗАДАЧА %d - %s/main.go\n", num, taskDir)
+		i18n.Printf("ЗАДАЧА %d - %s/main.go\n", num, rel)
 		fmt.Println(strings.Repeat("=", 50) + "\n")
 
-		cmd := exec.Command("go", "run", "main.go")
-		cmd.Dir = taskDir
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		cmd.Stdin = os.Stdin
+		res, err := runProcess(topic, num)
+		if err != nil {
+			i18n.Printf("Ошибка при запуске задачи: %v\n", err)
+			continue
+		}
+		results[num] = res
+		if res.Status != runner.StatusOK {
+			fmt.Printf("\n[%s] %s\n", res.Status, statusDetails(res))
+		}
+		printRaces(res, "  ")
+	}
+
+	if len(nums) > 1 {
+		printSummary(topic, nums, results)
+	}
+}
+
+// runProcess запускает задачу в отдельном процессе с выводом в терминал.
+func runProcess(topic Topic, num int) (runner.Result, error) {
+	cmd, cleanup, err := runner.Command(reg.Root, topic, num)
+	if err != nil {
+		return runner.Result{}, err
+	}
+	defer cleanup()
+
+	cmd.Stdin = os.Stdin
+	return runner.Exec(cmd, opts.timeout, os.Stdout, os.Stderr), nil
+}
+
+// runRecord запускает задачу отдельным процессом без вывода в терминал —
+// даже вкомпилированную, иначе её stdout не отделить от отчёта.
+func runRecord(topic Topic, num int) output.Record {
+	cmd, cleanup, err := runner.Command(reg.Root, topic, num)
+	if err != nil {
+		return output.Record{Topic: topic.Name, Num: num, Verdict: output.Error, Message: err.Error()}
+	}
+	defer cleanup()
+
+	res := runner.Exec(cmd, opts.timeout, nil, nil)
+	rec := output.FromResult(topic.Name, num, res)
+	rec.Message = statusDetails(res)
+	return rec
+}
+
+// writeReport печатает отчёт в формате --format.
+func writeReport(rep *output.Report) {
+	if err := rep.Write(os.Stdout, opts.format); err != nil {
+		i18n.Fprintf(os.Stderr, "Не удалось записать отчёт: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeJSON печатает данные команды, у которых нет записей по задачам;
+// в JUnit их не уложить.
+func writeJSON(cmd string, v any) {
+	if opts.format == output.JUnit {
+		i18n.Fprintf(os.Stderr, "Команда %s не поддерживает --format=junit, используйте json\n", cmd)
+		os.Exit(2)
+	}
+	if err := output.WriteJSON(os.Stdout, v); err != nil {
+		i18n.Fprintf(os.Stderr, "Не удалось записать вывод: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func statusDetails(res runner.Result) string {
+	switch res.Status {
+	case runner.StatusTimeout:
+		return i18n.Sprintf("задача не завершилась за %s и была остановлена", opts.timeout)
+	case runner.StatusOK:
+		return ""
+	}
+	return res.Message
+}
+
+// printSummary печатает итоговую таблицу по нескольким задачам.
+func printSummary(topic Topic, nums []int, results map[int]runner.Result) {
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	i18n.Printf("ИТОГО: %s\n", topic.Name)
+	fmt.Println(strings.Repeat("=", 50))
+	for _, num := range nums {
+		res, ok := results[num]
+		if !ok {
+			fmt.Printf("  %3d  %-9s\n", num, "error")
+			continue
+		}
+		fmt.Printf("  %3d  %-9s %8s  %s\n", num, res.Status, res.Duration.Round(time.Millisecond), statusDetails(res))
+		printRaces(res, "                        ")
+	}
+}
+
+// printRaces печатает сжатую сводку отчётов race detector'а.
+func printRaces(res runner.Result, prefix string) {
+	for _, line := range runner.RaceSummary(res.Races) {
+		fmt.Printf("%sDATA RACE: %s\n", prefix, line)
+	}
+}
+
+// runRaceCheck прогоняет эталонные решения с -race и падает, если нашлась гонка.
+func runRaceCheck(names []string) {
+	if len(names) == 0 {
+		names = []string{"interface", "concurrency"}
+	}
+	runner.DetectRaces = true
+	text := opts.format == output.Text
+
+	var racy int
+	rep := output.NewReport("racecheck")
+	for _, name := range names {
+		topic, ok := reg.Topic(name)
+		if !ok {
+			i18n.Fprintf(os.Stderr, "Неизвестная тема: %s\n", name)
+			continue
+		}
+		for _, num := range topic.Numbers {
+			cmd, cleanup, err := runner.Command(reg.Root, topic, num)
+			if err != nil {
+				if text {
+					fmt.Printf("%s/%d: %v\n", topic.Name, num, err)
+				}
+				rep.Add(output.Record{Topic: topic.Name, Num: num, Verdict: output.Error, Message: err.Error()})
+				continue
+			}
+			res := runner.Exec(cmd, opts.timeout, nil, nil)
+			cleanup()
+
+			// Паники и дедлоки здесь — часть задач; провал только гонка.
+			rec := output.FromResult(topic.Name, num, res)
+			rec.Verdict, rec.Message = output.Pass, statusDetails(res)
+			if len(res.Races) > 0 {
+				racy++
+				rec.Verdict = output.Fail
+				rec.Message = strings.Join(runner.RaceSummary(res.Races), "\n")
+			}
+			rep.Add(rec)
+
+			if text {
+				fmt.Printf("%-6s %s/%d  %s\n", res.Status, topic.Name, num, statusDetails(res))
+				printRaces(res, "       ")
+			}
+		}
+	}
+
+	if !text {
+		writeReport(rep)
+		if racy > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	if racy > 0 {
+		i18n.Printf("\nЗадач с гонками: %d\n", racy)
+		os.Exit(1)
+	}
+	i18n.Println("\nГонок не найдено.")
+}
+
+// practiceTask разбирает аргументы "<тема> <номер>" команд practice, verify, watch и hint.
+func practiceTask(cmd string, args []string) (Topic, int, bool) {
+	if len(args) != 2 {
+		i18n.Printf("Использование: go run main.go %s <тема> <номер>\n", cmd)
+		return Topic{}, 0, false
+	}
+	topic, ok := reg.Topic(args[0])
+	if !ok {
+		i18n.Printf("Неизвестная тема: %s\n", args[0])
+		return Topic{}, 0, false
+	}
+	nums := parseTaskNumbers(topic, args[1:])
+	if len(nums) != 1 {
+		return Topic{}, 0, false
+	}
+	return topic, nums[0], true
+}
+
+func runPractice(args []string) {
+	topic, num, ok := practiceTask("practice", args)
+	if !ok {
+		return
+	}
+	dir, err := practice.Create(reg.Root, topic, num)
+	rel, _ := filepath.Rel(reg.Root, dir)
+	switch {
+	case errors.Is(err, practice.ErrExists):
+		i18n.Printf("Рабочее место уже есть: %s\n", rel)
+		i18n.Println("Удалите каталог, чтобы начать заново.")
+		return
+	case errors.Is(err, practice.ErrQuizTask):
+		i18n.Printf("Задача %s/%d — на чтение кода, реализовывать в ней нечего.\n", topic.Name, num)
+		i18n.Printf("Проверьте себя в квизе: go run main.go quiz %s %d\n", topic.Name, num)
+		return
+	case err != nil:
+		i18n.Printf("Не удалось создать рабочее место: %v\n", err)
+		os.Exit(1)
+	}
+	i18n.Printf("Заготовка задачи %s/%d: %s/main.go\n", topic.Name, num, rel)
+	i18n.Println("Тела функций заменены на panic(\"TODO\") — реализуйте их.")
+	if practice.CheckMode(topic, num) == practice.ModeRun {
+		i18n.Println("Проверок у задачи нет, поэтому main тоже заглушён — напишите программу по условию.")
+	}
+	i18n.Printf("Проверить решение: go run main.go verify %s %d\n", topic.Name, num)
+}
+
+func runVerify(args []string) {
+	topic, num, ok := practiceTask("verify", args)
+	if !ok {
+		return
+	}
+	if opts.format != output.Text {
+		verifyReport(topic, num)
+		return
+	}
+	v, err := practice.Verify(reg.Root, topic, num, os.Stdout, os.Stderr)
+	if err != nil {
+		i18n.Printf("Не удалось проверить решение: %v\n", err)
+		os.Exit(1)
+	}
+	recordAttempt(topic, num, progress.SourceVerify, v.Passed())
+
+	fmt.Println()
+	switch {
+	case !v.Passed():
+		fmt.Printf("FAIL %s/%d [%s] %s\n", topic.Name, num, v.Result.Status, statusDetails(v.Result))
+		printRaces(v.Result, "     ")
+		os.Exit(1)
+	case v.Mode == practice.ModeRun:
+		i18n.Printf("OK   %s/%d: задача выполнилась, но автоматических проверок у неё нет — сверьте вывод сами\n", topic.Name, num)
+	default:
+		fmt.Printf("PASS %s/%d (%s)\n", topic.Name, num, v.Mode)
+	}
+}
+
+func verifyReport(topic Topic, num int) {
+	rep := output.NewReport("verify")
+	v, err := practice.Verify(reg.Root, topic, num, nil, nil)
+	switch {
+	case err != nil:
+		rep.Add(output.Record{Topic: topic.Name, Num: num, Verdict: output.Error, Message: err.Error()})
+	default:
+		recordAttempt(topic, num, progress.SourceVerify, v.Passed())
+		rec := output.FromResult(topic.Name, num, v.Result)
+		rec.Verdict, rec.Message = output.Pass, string(v.Mode)
+		if !v.Passed() {
+			rec.Verdict, rec.Message = output.Fail, statusDetails(v.Result)
+		}
+		rep.Add(rec)
+	}
+	writeReport(rep)
+	if rep.Failed() {
+		os.Exit(1)
+	}
+}
+
+func runWatch(args []string) {
+	topic, num, ok := practiceTask("watch", args)
+	if !ok {
+		return
+	}
+	// Ctrl+C отменяет контекст: раннер убьёт группу процессов задачи,
+	// которая сигнала от терминала не получит.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rel, _ := filepath.Rel(reg.Root, topic.TaskDir(num))
+	i18n.Printf("Слежу за %s (Ctrl+C — выход)\n", rel)
+	if err := watch.New(reg.Root, topic, num, opts.timeout, os.Stdout).Run(ctx); err != nil {
+		i18n.Printf("Не удалось следить за задачей: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runHint открывает следующую подсказку задачи, напоминая уже открытые,
+// и отмечает это в прогрессе.
+func runHint(args []string) {
+	topic, num, ok := practiceTask("hint", args)
+	if !ok {
+		return
+	}
+	ms, err := meta.Load(topic)
+	if err != nil {
+		i18n.Printf("Не удалось прочитать подсказки: %v\n", err)
+		os.Exit(1)
+	}
+	var hints []string
+	for _, m := range ms {
+		if m.Num == num {
+			hints = m.Hints
+		}
+	}
+	if len(hints) == 0 {
+		i18n.Printf("У задачи %s %d нет подсказок.\n", topic.Name, num)
+		return
+	}
+
+	store := loadProgress()
+	used := min(store.HintsUsed(topic.Name, num), len(hints))
+	for i, h := range hints[:used] {
+		i18n.Printf("Подсказка %d/%d: %s\n", i+1, len(hints), strings.ReplaceAll(h, "\n", "\n  "))
+	}
+	if used == len(hints) {
+		i18n.Println("\nПодсказок больше нет. Эталонное решение:")
+		fmt.Printf("  go run main.go %s %d\n", topic.Name, num)
+		return
+	}
+	if used > 0 {
+		fmt.Println()
+	}
+	store.UseHint(topic.Name, num, time.Now())
+	saveProgress(store)
+	i18n.Printf("Подсказка %d/%d: %s\n", used+1, len(hints), strings.ReplaceAll(hints[used], "\n", "\n  "))
+	if used+1 < len(hints) {
+		i18n.Printf("\nСледующая: go run main.go hint %s %d\n", topic.Name, num)
+	}
+}
+
+// searchResult — задача в выводе search --format=json.
+type searchResult struct {
+	Topic      string   `json:"topic"`
+	Num        int      `json:"num"`
+	Title      string   `json:"title,omitempty"`
+	Difficulty string   `json:"difficulty,omitempty"`
+	Tags       []string `json:"tags"`
+	Source     string   `json:"source,omitempty"`
+	Minutes    int      `json:"estimate_minutes,omitempty"`
+}
+
+func runSearch(args []string) {
+	var all []meta.Meta
+	for _, topic := range reg.Topics() {
+		ms, err := meta.Load(topic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", topic.Name, err)
+			continue
+		}
+		all = append(all, ms...)
+	}
+
+	if opts.format != output.Text {
+		if len(args) == 0 {
+			writeJSON("search", meta.Tags(all))
+			return
+		}
+		found := []searchResult{}
+		for _, m := range meta.Search(all, meta.ParseQuery(args)) {
+			found = append(found, searchResult{
+				Topic: m.Topic, Num: m.Num, Title: m.Title, Difficulty: m.Difficulty,
+				Tags: m.Tags, Source: m.Source, Minutes: int(m.Estimate.Minutes()),
+			})
+		}
+		writeJSON("search", found)
+		return
+	}
+
+	if len(args) == 0 {
+		i18n.Println("Использование: go run main.go search <слова|tag:x|topic:x|difficulty:x>...")
+		i18n.Println("\nТеги:")
+		for _, tc := range meta.Tags(all) {
+			fmt.Printf("  %-16s %d\n", tc.Tag, tc.Count)
+		}
+		return
+	}
+
+	found := meta.Search(all, meta.ParseQuery(args))
+	i18n.Printf("Найдено задач: %d\n", len(found))
+	for _, m := range found {
+		line := fmt.Sprintf("  %-16s", m.Key())
+		if m.Difficulty != "" {
+			line += fmt.Sprintf(" [%s]", m.Difficulty)
+		}
+		if m.Estimate > 0 {
+			line += i18n.Sprintf(" ~%d мин", int(m.Estimate.Minutes()))
+		}
+		if m.Title != "" {
+			line += " " + m.Title
+		}
+		fmt.Println(line)
+		if len(m.Tags) > 0 {
+			fmt.Printf("  %16s (%s)\n", "", strings.Join(m.Tags, ", "))
+		}
+	}
+}
+
+func runNew(args []string) {
+	if len(args) != 1 {
+		i18n.Println("Использование: go run main.go new <тема> [--kind=predict|implement|review|algo]")
+		return
+	}
+	res, err := scaffold.New(reg, args[0], opts.kind)
+	if err != nil {
+		i18n.Printf("Не удалось создать задачу: %v\n", err)
+		os.Exit(1)
+	}
+	if res.NewTopic {
+		i18n.Printf("Новая тема: %s\n", res.Topic)
+	}
+	i18n.Printf("Задача %s/%d:\n", res.Topic, res.Num)
+	for _, file := range res.Files {
+		rel, _ := filepath.Rel(reg.Root, file)
+		fmt.Printf("  %s\n", rel)
+	}
+	i18n.Println("Заполните места с TODO и проверьте задачу:")
+	fmt.Printf("  go run main.go %s %d\n", res.Topic, res.Num)
+	if res.NewTopic && !strings.Contains(res.Topic, "-") {
+		if _, ok := compiled[res.Topic]; !ok {
+			i18n.Println("Чтобы задачи темы запускались без go run, добавьте её GetTasks в compiled в main.go.")
+		}
+	}
+}
+
+// lintIssue — замечание в выводе lint --format=json.
+type lintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func runLint() {
+	diags := lint.Run(reg)
+	if opts.format != output.Text {
+		issues := []lintIssue{}
+		for _, d := range diags {
+			issues = append(issues, lintIssue{d.Pos.Filename, d.Pos.Line, d.Pos.Column, d.Msg})
+		}
+		writeJSON("lint", issues)
+	} else {
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+		if len(diags) == 0 {
+			i18n.Println("Замечаний нет.")
+		} else {
+			i18n.Printf("\nЗамечаний: %d\n", len(diags))
+		}
+	}
+	if len(diags) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runExport выгружает колоду Anki или книгу в файл --out или в stdout.
+func runExport(names []string) {
+	if opts.anki == opts.book {
+		i18n.Println("Использование: go run main.go export --anki|--book [--out файл] [темы]")
+		os.Exit(2)
+	}
+	topics := reg.Topics()
+	if len(names) > 0 {
+		topics = nil
+		for _, name := range names {
+			topic, ok := reg.Topic(name)
+			if !ok {
+				i18n.Printf("Неизвестная тема: %s\n", name)
+				os.Exit(2)
+			}
+			topics = append(topics, topic)
+		}
+	}
+	book, err := export.Load(topics)
+	if err != nil {
+		i18n.Printf("Не удалось прочитать задачи: %v\n", err)
+		os.Exit(1)
+	}
+
+	w := os.Stdout
+	if opts.out != "" {
+		if w, err = os.Create(opts.out); err != nil {
+			i18n.Printf("Не удалось создать файл: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var summary string
+	switch ext := strings.ToLower(filepath.Ext(opts.out)); {
+	case opts.anki:
+		var n int
+		n, err = export.WriteAnki(w, book)
+		summary = i18n.Sprintf("Карточек: %d. Импорт в Anki: Файл → Импорт, колода %s.", n, export.Deck)
+	case ext == ".html" || ext == ".htm":
+		err = export.WriteHTML(w, book)
+		summary = i18n.Sprintf("Книга: %d тем.", len(book))
+	default:
+		err = export.WriteMarkdown(w, book)
+		summary = i18n.Sprintf("Книга: %d тем.", len(book))
+	}
+	if opts.out != "" {
+		err = errors.Join(err, w.Close())
+	}
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Не удалось записать: %v\n", err)
+		os.Exit(1)
+	}
+	// В stdout идёт сама выгрузка, поэтому итог — только при записи в файл.
+	if opts.out != "" {
+		i18n.Printf("%s Записано в %s\n", summary, opts.out)
+	}
+}
+
+func runServe() {
+	srv := &http.Server{
+		Addr:              opts.addr,
+		Handler:           web.New(reg, opts.timeout).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	i18n.Printf("Веб-интерфейс: http://%s/\n", opts.addr)
+	if err := srv.ListenAndServe(); err != nil {
+		i18n.Printf("Сервер остановлен: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runInterview() {
+	mix := interview.DefaultMix(reg)
+	if opts.mix != "" {
+		var err error
+		if mix, err = interview.ParseMix(opts.mix); err != nil {
+			fmt.Printf("--mix: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	items, err := interview.Plan(reg, mix, interview.NewRand())
+	if err != nil {
+		i18n.Printf("Не удалось собрать собеседование: %v\n", err)
+		os.Exit(1)
+	}
+
+	store := loadProgress()
+	defer saveProgress(store)
+
+	rep := interview.NewSession(reg, os.Stdin, os.Stdout, opts.duration).Run(mix, items)
+	now := time.Now()
+	for _, e := range rep.Entries {
+		if e.Outcome != interview.OutcomeNotStarted {
+			store.Grade(e.Topic.Name, e.Num, progress.SourceInterview, interviewQuality(e), now)
+		}
+	}
+
+	i18n.Printf("\nРешено: %d из %d за %s\n", rep.Count(interview.OutcomePassed), len(rep.Entries), rep.Spent.Round(time.Second))
+	path, err := rep.Save(reg.Root)
+	if err != nil {
+		i18n.Printf("Не удалось сохранить отчёт: %v\n", err)
+		return
+	}
+	rel, _ := filepath.Rel(reg.Root, path)
+	i18n.Printf("Отчёт: %s\n", rel)
+}
+
+// interviewQuality переводит итог задачи собеседования в оценку SM-2.
+func interviewQuality(e interview.Entry) int {
+	switch e.Outcome {
+	case interview.OutcomePassed:
+		if e.Attempts > 1 {
+			return 4
+		}
+		return 5
+	case interview.OutcomeSkipped:
+		return 1
+	}
+	return 2
+}
+
+func runCheck(args []string) {
+	if len(args) < 1 {
+		i18n.Println("Использование: go run main.go check <тема> [номера...]")
+		return
+	}
+	topic, ok := reg.Topic(args[0])
+	if !ok {
+		i18n.Fprintf(os.Stderr, "Неизвестная тема: %s\n", args[0])
+		return
+	}
+	nums := topic.Numbers
+	if len(args) > 1 {
+		nums = parseTaskNumbers(topic, args[1:])
+	}
+
+	// check прогоняет эталонные решения, а не решения пользователя, поэтому
+	// в прогресс не пишет.
+	rep := output.NewReport("check")
+	for _, num := range nums {
+		res := check.Task(reg, topic, num)
+		rep.Add(checkRecord(res))
+		if opts.format == output.Text {
+			printCheck(res)
+		}
+	}
+
+	if opts.format != output.Text {
+		writeReport(rep)
+	} else {
+		i18n.Printf("\nИтого: %d прошло, %d упало, %d пропущено\n",
+			rep.Count(output.Pass), rep.Count(output.Fail), rep.Count(output.Skip))
+	}
+	if rep.Failed() {
+		os.Exit(1)
+	}
+}
+
+func printCheck(res check.Result) {
+	switch {
+	case res.Skipped != "":
+		fmt.Printf("SKIP %s/%d: %s\n", res.Topic, res.Num, res.Skipped)
+	case res.Pass:
+		fmt.Printf("PASS %s/%d\n", res.Topic, res.Num)
+	default:
+		mode := ""
+		if res.Unordered {
+			mode = i18n.T(" (порядок не учитывается)")
+		}
+		fmt.Printf("FAIL %s/%d%s\n", res.Topic, res.Num, mode)
+		if res.Err != nil {
+			i18n.Printf("     ошибка: %v\n", res.Err)
+		}
+		fmt.Print(indent(check.Diff(res.Expected, res.Actual), "     "))
+	}
+}
+
+// checkRecord — запись отчёта о сверке вывода; в message для упавшей
+// задачи тот же дифф, что и в текстовом режиме.
+func checkRecord(res check.Result) output.Record {
+	rec := output.Record{
+		Topic:    res.Topic,
+		Num:      res.Num,
+		Duration: output.Seconds(res.Duration),
+		Stdout:   strings.Join(res.Actual, "\n"),
+		Stderr:   res.Stderr,
+		Verdict:  output.Pass,
+	}
+	switch {
+	case res.Skipped != "":
+		rec.Verdict, rec.Message = output.Skip, res.Skipped
+	case !res.Pass:
+		rec.Verdict, rec.Message = output.Fail, check.Diff(res.Expected, res.Actual)
+		if res.Err != nil {
+			rec.Message = i18n.Sprintf("ошибка: %v\n%s", res.Err, rec.Message)
+		}
+	}
+	return rec
+}
+
+func runQuiz(args []string) {
+	if len(args) < 1 {
+		i18n.Println("Использование: go run main.go quiz <тема> [номера...]")
+		return
+	}
+	topic, ok := reg.Topic(args[0])
+	if !ok {
+		i18n.Printf("Неизвестная тема: %s\n", args[0])
+		return
+	}
+	nums := topic.Numbers
+	explicit := len(args) > 1
+	if explicit {
+		nums = parseTaskNumbers(topic, args[1:])
+	}
+
+	store := loadProgress()
+	defer saveProgress(store)
+
+	session := quiz.NewSession(reg, os.Stdin, os.Stdout)
+	var asked, correct int
+	for _, num := range nums {
+		res, quit, err := session.Ask(topic, num)
+		if err != nil {
+			// Задачи без ответа в комментарии пропускаем молча, если их не просили явно.
+			if explicit {
+				fmt.Printf("%s/%d: %v\n", topic.Name, num, err)
+			}
+			continue
+		}
+		if quit {
+			break
+		}
+		store.Grade(topic.Name, num, progress.SourceQuiz, quizQuality(res), time.Now())
+		if res.Skipped {
+			continue
+		}
+		asked++
+		if res.Correct {
+			correct++
+		}
+	}
+	i18n.Printf("\nРезультат: %d из %d\n", correct, asked)
+}
+
+// quizQuality переводит результат квиза в оценку SM-2.
+func quizQuality(res quiz.Result) int {
+	switch {
+	case res.Correct:
+		return 5
+	case res.Skipped:
+		return 1
+	}
+	return 2
+}
+
+func loadProgress() *progress.Store {
+	path, err := progress.DefaultPath()
+	if err == nil {
+		var store *progress.Store
+		if store, err = progress.Load(path); err == nil {
+			return store
+		}
+	}
+	i18n.Printf("Не удалось загрузить прогресс: %v\n", err)
+	os.Exit(1)
+	return nil
+}
+
+// recordAttempt добавляет в прогресс неоцениваемую попытку. Проверку
+// решения недоступный файл прогресса не срывает: ошибка только печатается.
+func recordAttempt(topic Topic, num int, source string, correct bool) {
+	path, err := progress.DefaultPath()
+	if err == nil {
+		var store *progress.Store
+		if store, err = progress.Load(path); err == nil {
+			store.Record(topic.Name, num, source, correct, time.Now())
+			err = store.Save()
+		}
+	}
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Не удалось сохранить прогресс: %v\n", err)
+	}
+}
+
+func saveProgress(store *progress.Store) {
+	if err := store.Save(); err != nil {
+		i18n.Printf("Не удалось сохранить прогресс: %v\n", err)
+	}
+}
+
+func runReview() {
+	store := loadProgress()
+	defer saveProgress(store)
+
+	due := store.Due(time.Now())
+	if len(due) == 0 {
+		i18n.Println("На сегодня повторений нет.")
+		return
+	}
+	i18n.Printf("К повторению: %d задач\n", len(due))
+
+	session := quiz.NewSession(reg, os.Stdin, os.Stdout)
+	for _, key := range due {
+		name, num, ok := progress.ParseKey(key)
+		topic, found := reg.Topic(name)
+		if !ok || !found || !topic.Has(num) {
+			continue
+		}
+
+		res, quit, err := session.Ask(topic, num)
+		if errors.Is(err, quiz.ErrNoAnswer) {
+			// Задачи на реализацию угадывать нечего — пользователь оценивает себя сам.
+			i18n.Printf("\n%s: вспомните решение (go run main.go %s %d)\n", key, topic.Name, num)
+			fmt.Print(i18n.T("Насколько хорошо помните, 0-5 (пусто — выйти)? "))
+			line, _ := session.In.ReadString('\n')
+			quality, err := strconv.Atoi(strings.TrimSpace(line))
+			if err != nil {
+				return
+			}
+			store.Grade(topic.Name, num, progress.SourceReview, quality, time.Now())
+			continue
+		}
+		if err != nil {
+			fmt.Printf("%s: %v\n", key, err)
+			continue
+		}
+		if quit {
+			return
+		}
+		store.Grade(topic.Name, num, progress.SourceReview, quizQuality(res), time.Now())
+	}
+}
+
+// topicStats — строка stats --format=json.
+type topicStats struct {
+	Topic     string `json:"topic"`
+	Count     int    `json:"count"`
+	Attempted int    `json:"attempted"`
+	Mastered  int    `json:"mastered"`
+	Due       int    `json:"due"`
+	Attempts  int    `json:"attempts"`
+	Correct   int    `json:"correct"`
+	Hints     int    `json:"hints"`
+}
+
+func printStats() {
+	store := loadProgress()
+	now := time.Now()
+
+	if opts.format != output.Text {
+		stats := []topicStats{}
+		for _, topic := range reg.Topics() {
+			st := store.Stats(topic.Name, topic.Numbers, now)
+			stats = append(stats, topicStats{topic.Name, topic.Count, st.Attempted, st.Mastered, st.Due, st.Attempts, st.Correct, st.Hints})
+		}
+		writeJSON("stats", stats)
+		return
+	}
+
+	// Заголовок выровнен вручную: %-12s считает байты, а не символы кириллицы.
+	i18n.Println("Тема            Задач   Начато  Освоено  Сегодня  Точность  Подсказки")
+	for _, topic := range reg.Topics() {
+		st := store.Stats(topic.Name, topic.Numbers, now)
+		accuracy := "-"
+		if st.Attempts > 0 {
+			accuracy = fmt.Sprintf("%d%%", st.Correct*100/st.Attempts)
+		}
+		fmt.Printf("%-12s %8d %8d %8d %8d %9s %10d\n", topic.Name, topic.Count, st.Attempted, st.Mastered, st.Due, accuracy, st.Hints)
+	}
+}
 
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("Ошибка при запуске задачи: %v\n", err)
+func indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
 		}
 	}
+	return strings.Join(lines, "")
 }
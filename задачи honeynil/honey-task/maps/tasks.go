@@ -1,4 +1,4 @@
-// Package maps some task
+// Package maps — Maps в go
 package maps
 
 import (
@@ -161,7 +161,7 @@ func task10() {
 }
 
 // ЗАДАЧА 11: Что выведет?
-// OUTPUT (порядок недетерминирован, "d" может попасть или нет):
+// OUTPUT (порядок недетерминирован, "d" может попасть или нет — не проверяется):
 //
 //	a b c d  ← добавление ключа во время итерации допустимо; новый ключ может быть посещён или нет
 //	map[a:1 b:2 c:3 d:4]
@@ -206,8 +206,8 @@ func task13() {
 // ЗАДАЧА 14: Что выведет?
 // OUTPUT:
 //
-//	A         ← m[{1,2}] = "A"
-//	          ← m[{5,6}] отсутствует, zero value string = ""
+//	A                ← m[{1,2}] = "A"
+//	(пустая строка)  ← m[{5,6}] отсутствует, zero value string = ""
 func task14() {
 	m := map[Point]string{
 		{1, 2}: "A",
@@ -387,11 +387,12 @@ func task26() {
 }
 
 // ЗАДАЧА 27: Что выведет?
-// OUTPUT (детерминировано, т.к. count ограничен):
+// OUTPUT (недетерминировано, не проверяется):
 //
 //	6
 //	map[1:1 2:2 3:3 11:1 12:2 13:3]
-//	← во время итерации добавляются ключи k+10; добавленные ключи не будут посещены повторно
+//	← во время итерации добавляются ключи k+10; добавленный ключ МОЖЕТ быть посещён,
+//	← тогда добавится и k+20 — len(m) будет от 6 до 8 (count ограничивает рост)
 func task27() {
 	m := map[int]int{1: 1, 2: 2, 3: 3}
 	count := 0
@@ -528,8 +529,8 @@ func task35() {
 // ЗАДАЧА 36: Что выведет?
 // OUTPUT:
 //
-//	Alice   ← m[Key{"user", 1}] = "Alice"
-//	        ← m[Key{"user", 3}] отсутствует, zero value string = ""
+//	Alice            ← m[Key{"user", 1}] = "Alice"
+//	(пустая строка)  ← m[Key{"user", 3}] отсутствует, zero value string = ""
 func task36() {
 	type Key struct {
 		name string
@@ -544,7 +545,7 @@ func task36() {
 }
 
 // ЗАДАЧА 37: Что выведет?
-// OUTPUT (недетерминировано):
+// OUTPUT (недетерминировано, не проверяется):
 //
 //	Last key: <один из ключей: "a", "b" или "c">
 //	← последний ключ при итерации — случайный
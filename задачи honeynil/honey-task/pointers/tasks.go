@@ -1,4 +1,4 @@
-// Package pointers smth task
+// Package pointers — Указатели в go
 package pointers
 
 import (
@@ -451,14 +451,15 @@ func task30() {
 }
 
 // ЗАДАЧА 31: Что выведет?
-// OUTPUT:
+// OUTPUT (Go 1.22+):
 //
-//	3 3 3
-//	← Go 1.22+: каждая итерация создаёт новую переменную v → &v уникален для каждой итерации
-//	← В Go < 1.22: все &v указывали бы на одну переменную → вывод был бы "3 3 3" (последнее значение)
-//	← Начиная с Go 1.22, ответ: 1 2 3 (каждая итерация — своя переменная)
+//	1 2 3
+//	← каждая итерация создаёт новую переменную v → &v уникален для каждой итерации
 //
-// ПРИМЕЧАНИЕ: с Go 1.22 выводит "1 2 3"
+// OUTPUT (Go < 1.22):
+//
+//	3 3 3
+//	← все &v указывают на одну переменную → последнее значение
 func task31() {
 	nums := []int{1, 2, 3}
 	var ptrs []*int
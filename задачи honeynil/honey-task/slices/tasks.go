@@ -0,0 +1,561 @@
+// Package slices — Слайсы в go
+package slices
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+type Person struct {
+	name string
+	age  int
+}
+
+const Count = 40
+
+func GetTasks() map[int]func() {
+	return map[int]func(){
+		1: task1, 2: task2, 3: task3, 4: task4, 5: task5, 6: task6, 7: task7, 8: task8, 9: task9, 10: task10,
+		11: task11, 12: task12, 13: task13, 14: task14, 15: task15, 16: task16, 17: task17, 18: task18, 19: task19, 20: task20,
+		21: task21, 22: task22, 23: task23, 24: task24, 25: task25, 26: task26, 27: task27, 28: task28, 29: task29, 30: task30,
+		31: task31, 32: task32, 33: task33, 34: task34, 35: task35, 36: task36, 37: task37, 38: task38, 39: task39, 40: task40,
+	}
+}
+
+// ЗАДАЧА 1: Что выведет?
+// OUTPUT:
+//
+//	true false   ← var s []int — nil слайс; []int{} — пустой, но не nil
+//	0 0          ← len и cap у обоих равны 0
+//
+// TITLE (en): What does this print?
+// EXPLANATION (en): var s []int declares a nil slice, while []int{} is
+// empty but not nil. Both have len and cap 0.
+func task1() {
+	var s1 []int
+	s2 := []int{}
+	fmt.Println(s1 == nil, s2 == nil)
+	fmt.Println(len(s1), len(s2))
+}
+
+// ЗАДАЧА 2: Что выведет?
+// OUTPUT:
+//
+//	[1] 1 1   ← append в nil слайс работает: выделяется новый массив
+//
+// TITLE (en): What does this print?
+// EXPLANATION (en): Append works on a nil slice: it allocates a new
+// backing array.
+func task2() {
+	var s []int
+	s = append(s, 1)
+	fmt.Println(s, len(s), cap(s))
+}
+
+// ЗАДАЧА 3: Что выведет?
+// OUTPUT:
+//
+//	[0 1 2 3 4] [1 2 4 4 8]   ← при нехватке места малый слайс растёт вдвое: 1 → 2 → 4 → 8
+//	← s уходит в fmt.Println и живёт в куче; слайс, не покидающий функцию,
+//	← с Go 1.25 может начать с 32-байтного буфера на стеке (cap 4 сразу)
+//
+// TITLE (en): What does this print?
+// EXPLANATION (en): When a small slice runs out of room, append doubles
+// its capacity: 1 → 2 → 4 → 8. s escapes to the heap via fmt.Println;
+// since Go 1.25 a slice that stays in the function may start with a
+// 32-byte stack buffer (cap 4 right away).
+func task3() {
+	var s []int
+	var caps []int
+	for i := 0; i < 5; i++ {
+		s = append(s, i)
+		caps = append(caps, cap(s))
+	}
+	fmt.Println(s, caps)
+}
+
+// ЗАДАЧА 4: Что выведет?
+// OUTPUT:
+//
+//	[0 0 0 1] 4 6   ← make([]int, 3) — три нуля; append дописывает В КОНЕЦ, cap 3 → 6
+func task4() {
+	s := make([]int, 3)
+	s = append(s, 1)
+	fmt.Println(s, len(s), cap(s))
+}
+
+// ЗАДАЧА 5: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3] 3 3   ← make([]int, 0, 3): len 0, места хватает — реаллокации нет
+func task5() {
+	s := make([]int, 0, 3)
+	s = append(s, 1, 2, 3)
+	fmt.Println(s, len(s), cap(s))
+}
+
+// ЗАДАЧА 6: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3 4 5 6] 6 10   ← нужно 6 > 5: новая ёмкость = 2*5 = 10
+//	[1 2 3 4 5] 5 6      ← нужно 5 > 2*2: берётся ровно 5, затем округление
+//	← до класса размера аллокатора: 5*8 = 40 байт → 48 байт → cap 6
+func task6() {
+	s1 := make([]int, 0, 5)
+	s1 = append(s1, 1, 2, 3, 4, 5, 6)
+	fmt.Println(s1, len(s1), cap(s1))
+
+	s2 := []int{1, 2}
+	s2 = append(s2, 3, 4, 5)
+	fmt.Println(s2, len(s2), cap(s2))
+}
+
+// ЗАДАЧА 7: Что выведет?
+// OUTPUT:
+//
+//	[1 20 3 4 5]   ← b := a[1:3] смотрит в тот же массив: b[0] — это a[1]
+//	[20 3] 2 4     ← len = 3-1 = 2, cap = до конца массива a = 5-1 = 4
+func task7() {
+	a := []int{1, 2, 3, 4, 5}
+	b := a[1:3]
+	b[0] = 20
+	fmt.Println(a)
+	fmt.Println(b, len(b), cap(b))
+}
+
+// ЗАДАЧА 8: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3 100 5]   ← у b есть запас ёмкости: append пишет в a[3] и затирает 4
+func task8() {
+	a := []int{1, 2, 3, 4, 5}
+	b := a[1:3]
+	b = append(b, 100)
+	fmt.Println(a)
+}
+
+// ЗАДАЧА 9: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3 4 5]   ← a[1:3:3] — full slice expression: cap(b) = 3-1 = 2
+//	[20 3 100] 4  ← append без места → новый массив; дальше b и a независимы
+func task9() {
+	a := []int{1, 2, 3, 4, 5}
+	b := a[1:3:3]
+	b = append(b, 100)
+	b[0] = 20
+	fmt.Println(a)
+	fmt.Println(b, cap(b))
+}
+
+// ЗАДАЧА 10: Что выведет?
+// OUTPUT:
+//
+//	[0 0 0] [100 0 0 4]   ← len == cap: append копирует в новый массив, a не меняется
+func task10() {
+	a := make([]int, 3)
+	b := append(a, 4)
+	b[0] = 100
+	fmt.Println(a, b)
+}
+
+// ЗАДАЧА 11: Что выведет?
+// OUTPUT:
+//
+//	2 2   ← у a есть запас: оба append пишут в один и тот же a[3], побеждает второй
+func task11() {
+	a := make([]int, 3, 10)
+	b := append(a, 1)
+	c := append(a, 2)
+	fmt.Println(b[3], c[3])
+}
+
+// ЗАДАЧА 12: Что выведет?
+// OUTPUT:
+//
+//	2 [1 2]   ← copy копирует min(len(dst), len(src)) элементов и возвращает их число
+func task12() {
+	dst := make([]int, 2)
+	n := copy(dst, []int{1, 2, 3})
+	fmt.Println(n, dst)
+}
+
+// ЗАДАЧА 13: Что выведет?
+// OUTPUT:
+//
+//	0 []   ← len(dst) == 0: copy ничего не копирует и НЕ растит слайс, в отличие от append
+func task13() {
+	var dst []int
+	n := copy(dst, []int{1, 2, 3})
+	fmt.Println(n, dst)
+}
+
+// ЗАДАЧА 14: Что выведет?
+// OUTPUT:
+//
+//	[1 1 2 3 4]   ← copy корректно работает с перекрывающимися слайсами (как memmove)
+func task14() {
+	s := []int{1, 2, 3, 4, 5}
+	copy(s[1:], s)
+	fmt.Println(s)
+}
+
+// ЗАДАЧА 15: Что выведет?
+// OUTPUT:
+//
+//	3 [104 195 169]   ← copy из строки в []byte копирует байты: 'é' в UTF-8 — два байта
+func task15() {
+	b := make([]byte, 3)
+	n := copy(b, "héllo")
+	fmt.Println(n, b)
+}
+
+// ЗАДАЧА 16: Что выведет?
+// OUTPUT:
+//
+//	[100 2 3]   ← в функцию копируется заголовок слайса, но массив общий
+func task16() {
+	s := []int{1, 2, 3}
+	modifySlice(s)
+	fmt.Println(s)
+}
+func modifySlice(s []int) {
+	s[0] = 100
+}
+
+// ЗАДАЧА 17: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3] 3   ← append внутри функции меняет только её копию заголовка
+func task17() {
+	s := []int{1, 2, 3}
+	appendSlice(s)
+	fmt.Println(s, len(s))
+}
+func appendSlice(s []int) {
+	s = append(s, 4)
+	_ = s
+}
+
+// ЗАДАЧА 18: Что выведет?
+// OUTPUT:
+//
+//	[100 0 0]     ← len у вызывающего остался 3, но s[0] = 100 записан в общий массив
+//	[100 0 0 4]   ← append тоже писал в общий массив (запас ёмкости был) — виден через s[:4]
+func task18() {
+	s := make([]int, 3, 10)
+	appendAndModify(s)
+	fmt.Println(s)
+	fmt.Println(s[:4])
+}
+func appendAndModify(s []int) {
+	s = append(s, 4)
+	s[0] = 100
+}
+
+// ЗАДАЧА 19: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3 4]   ← через *[]int функция меняет сам заголовок слайса
+func task19() {
+	s := []int{1, 2, 3}
+	appendPtr(&s)
+	fmt.Println(s)
+}
+func appendPtr(s *[]int) {
+	*s = append(*s, 4)
+}
+
+// ЗАДАЧА 20: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3]      ← v — копия элемента, её изменение не трогает слайс
+//	[10 20 30]   ← по индексу меняем сам элемент
+func task20() {
+	s := []int{1, 2, 3}
+	for _, v := range s {
+		v *= 10
+		_ = v
+	}
+	fmt.Println(s)
+	for i := range s {
+		s[i] *= 10
+	}
+	fmt.Println(s)
+}
+
+// ЗАДАЧА 21: Что выведет?
+// OUTPUT:
+//
+//	3 4   ← range вычисляет длину один раз: добавленный элемент не обходится
+func task21() {
+	s := []int{1, 2, 3}
+	n := 0
+	for i := range s {
+		if i == 0 {
+			s = append(s, 4)
+		}
+		n++
+	}
+	fmt.Println(n, len(s))
+}
+
+// ЗАДАЧА 22: Что выведет?
+// OUTPUT:
+//
+//	[1 20 3]   ← range по слайсу читает общий массив: изменение s[1] видно
+//	[1 2 3]    ← range по массиву обходит его КОПИЮ: изменение arr[1] не видно
+func task22() {
+	s := []int{1, 2, 3}
+	var got []int
+	for i, v := range s {
+		if i == 0 {
+			s[1] = 20
+		}
+		got = append(got, v)
+	}
+	fmt.Println(got)
+
+	arr := [3]int{1, 2, 3}
+	got = nil
+	for i, v := range arr {
+		if i == 0 {
+			arr[1] = 20
+		}
+		got = append(got, v)
+	}
+	fmt.Println(got)
+}
+
+// ЗАДАЧА 23: Что выведет?
+// OUTPUT:
+//
+//	null   ← nil слайс сериализуется в null
+//	[]     ← пустой слайс — в []
+func task23() {
+	var s1 []int
+	s2 := []int{}
+	b1, _ := json.Marshal(s1)
+	b2, _ := json.Marshal(s2)
+	fmt.Println(string(b1))
+	fmt.Println(string(b2))
+}
+
+// ЗАДАЧА 24: Что выведет?
+// OUTPUT:
+//
+//	false   ← reflect.DeepEqual различает nil и пустой слайс
+//	true    ← slices.Equal сравнивает только длину и элементы
+func task24() {
+	var s1 []int
+	s2 := []int{}
+	fmt.Println(reflect.DeepEqual(s1, s2))
+	fmt.Println(slices.Equal(s1, s2))
+}
+
+// ЗАДАЧА 25: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3]   ← sort.Ints сортирует на месте
+//	[3 2 1]   ← sort.Reverse оборачивает Less
+func task25() {
+	s := []int{3, 1, 2}
+	sort.Ints(s)
+	fmt.Println(s)
+	sort.Sort(sort.Reverse(sort.IntSlice(s)))
+	fmt.Println(s)
+}
+
+// ЗАДАЧА 26: Что выведет?
+// OUTPUT:
+//
+//	[{Bob 25} {Dave 25} {Alice 30} {Carol 30}]   ← SliceStable сохраняет исходный порядок равных
+func task26() {
+	people := []Person{{"Alice", 30}, {"Bob", 25}, {"Carol", 30}, {"Dave", 25}}
+	sort.SliceStable(people, func(i, j int) bool { return people[i].age < people[j].age })
+	fmt.Println(people)
+}
+
+// ЗАДАЧА 27: Что выведет?
+// OUTPUT:
+//
+//	[1 4 5]         ← slices.Delete удаляет s[1:3] и сдвигает хвост
+//	[1 4 5 0 0]     ← с Go 1.22 освободившийся хвост исходного массива обнуляется
+func task27() {
+	orig := []int{1, 2, 3, 4, 5}
+	s := slices.Delete(orig, 1, 3)
+	fmt.Println(s)
+	fmt.Println(orig)
+}
+
+// ЗАДАЧА 28: Что выведет?
+// OUTPUT:
+//
+//	[1 10 20 2 3]   ← slices.Insert вставляет перед индексом 1
+//	2 true -1       ← Index возвращает позицию, -1 если элемента нет
+func task28() {
+	s := []int{1, 2, 3}
+	s = slices.Insert(s, 1, 10, 20)
+	fmt.Println(s)
+	fmt.Println(slices.Index(s, 20), slices.Contains(s, 3), slices.Index(s, 42))
+}
+
+// ЗАДАЧА 29: Что выведет?
+// OUTPUT:
+//
+//	2 false   ← BinarySearch возвращает место вставки, если элемента нет
+//	3 true
+func task29() {
+	s := []int{1, 3, 5, 7}
+	i, found := slices.BinarySearch(s, 4)
+	fmt.Println(i, found)
+	i, found = slices.BinarySearch(s, 7)
+	fmt.Println(i, found)
+}
+
+// ЗАДАЧА 30: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3 1]   ← Compact схлопывает только СОСЕДНИЕ повторы, как uniq
+func task30() {
+	s := []int{1, 1, 2, 2, 2, 3, 1}
+	s = slices.Compact(s)
+	fmt.Println(s)
+}
+
+// ЗАДАЧА 31: Что выведет?
+// OUTPUT:
+//
+//	2 2   ← slices.Clip обрезает ёмкость до длины — следующий append не затрёт чужие данные
+//	2 10  ← slices.Grow гарантирует место ещё под 8 элементов
+func task31() {
+	s := make([]int, 2, 10)
+	s = slices.Clip(s)
+	fmt.Println(len(s), cap(s))
+	s = slices.Grow(s, 8)
+	fmt.Println(len(s), cap(s))
+}
+
+// ЗАДАЧА 32: Что выведет?
+// OUTPUT:
+//
+//	true false   ← Clone сохраняет nil-овость: клон nil — nil, клон пустого — пустой
+//	[1 2 3]      ← клон независим от оригинала
+func task32() {
+	var s1 []int
+	s2 := []int{}
+	fmt.Println(slices.Clone(s1) == nil, slices.Clone(s2) == nil)
+
+	orig := []int{1, 2, 3}
+	c := slices.Clone(orig)
+	c[0] = 100
+	fmt.Println(orig)
+}
+
+// ЗАДАЧА 33: Что выведет?
+// OUTPUT:
+//
+//	[3 2 1] 3 1   ← Reverse — на месте; Max/Min паникуют на пустом слайсе
+func task33() {
+	s := []int{1, 2, 3}
+	slices.Reverse(s)
+	fmt.Println(s, slices.Max(s), slices.Min(s))
+}
+
+// ЗАДАЧА 34: Что выведет?
+// OUTPUT:
+//
+//	[[1 0] [1 0]]   ← обе строки — один и тот же слайс row
+func task34() {
+	grid := make([][]int, 2)
+	row := make([]int, 2)
+	grid[0] = row
+	grid[1] = row
+	grid[0][0] = 1
+	fmt.Println(grid)
+}
+
+// ЗАДАЧА 35: Что выведет?
+// OUTPUT:
+//
+//	hello Hello   ← string(b) копирует байты: строка не меняется вместе с b
+func task35() {
+	b := []byte("hello")
+	s := string(b)
+	b[0] = 'H'
+	fmt.Println(s, string(b))
+}
+
+// ЗАДАЧА 36: Что выведет?
+// OUTPUT:
+//
+//	Recovered: runtime error: index out of range [5] with length 3
+func task36() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("Recovered:", r)
+		}
+	}()
+	s := []int{1, 2, 3}
+	i := 5
+	fmt.Println(s[i])
+}
+
+// ЗАДАЧА 37: Что выведет?
+// OUTPUT:
+//
+//	4 5 [0 0 0 0]   ← перерезать слайс можно до cap, а не только до len
+//	Recovered: runtime error: index out of range [3] with length 2
+//	← а вот индексировать — только до len
+func task37() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("Recovered:", r)
+		}
+	}()
+	s := make([]int, 2, 5)
+	t := s[:4]
+	fmt.Println(len(t), cap(t), t)
+	i := 3
+	fmt.Println(s[i])
+}
+
+// ЗАДАЧА 38: Что выведет?
+// OUTPUT:
+//
+//	[1 200 3] [100 2 3]   ← массив копируется при присваивании; arr[:] — слайс поверх arr
+func task38() {
+	arr := [3]int{1, 2, 3}
+	arr2 := arr
+	arr2[0] = 100
+	s := arr[:]
+	s[1] = 200
+	fmt.Println(arr, arr2)
+}
+
+// ЗАДАЧА 39: Что выведет?
+// OUTPUT:
+//
+//	[1 3 4]     ← удаление элемента через append(s[:i], s[i+1:]...)
+//	[1 3 4 4]   ← работа шла в массиве orig: хвост сдвинут, последний элемент остался
+func task39() {
+	orig := []int{1, 2, 3, 4}
+	s := append(orig[:1], orig[2:]...)
+	fmt.Println(s)
+	fmt.Println(orig)
+}
+
+// ЗАДАЧА 40: Что выведет?
+// OUTPUT:
+//
+//	0 100   ← после реаллокации p указывает на СТАРЫЙ массив, s — на новый
+func task40() {
+	s := make([]int, 1)
+	p := &s[0]
+	s = append(s, 2)
+	*p = 100
+	fmt.Println(s[0], *p)
+}
@@ -0,0 +1,503 @@
+// Package structs — Структуры в go
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+type Point struct {
+	x, y int
+}
+
+type Inner struct {
+	value int
+}
+
+func (i Inner) describe() string {
+	return fmt.Sprintf("Inner(%d)", i.value)
+}
+
+func (i *Inner) set(v int) {
+	i.value = v
+}
+
+type Outer struct {
+	Inner
+	name string
+}
+
+type Shadow struct {
+	Inner
+	value int
+}
+
+type Base struct{}
+
+func (Base) name() string { return "Base" }
+
+func (b Base) hello() string { return "Hello from " + b.name() }
+
+type Derived struct {
+	Base
+}
+
+func (Derived) name() string { return "Derived" }
+
+type Counter struct {
+	count int
+}
+
+func (c Counter) get() int { return c.count }
+
+func (c *Counter) getPtr() int { return c.count }
+
+type Speaker interface {
+	speak() string
+}
+
+type Dog struct{}
+
+func (d *Dog) speak() string { return "Woof" }
+
+type Cat struct{}
+
+func (c Cat) speak() string { return "Meow" }
+
+type Key struct {
+	v any
+}
+
+type Account struct {
+	mu      sync.Mutex
+	balance int
+}
+
+type Tree struct {
+	left *Tree
+	val  int
+}
+
+func (t *Tree) sum() int {
+	if t == nil {
+		return 0
+	}
+	return t.val + t.left.sum()
+}
+
+type User struct {
+	Name     string `json:"name"`
+	Age      int    `json:"age,omitempty"`
+	Email    string `json:"-"`
+	password string
+}
+
+type Meta struct {
+	Created string `json:"created"`
+}
+
+type Post struct {
+	Meta
+	Title string `json:"title"`
+}
+
+const Count = 30
+
+func GetTasks() map[int]func() {
+	return map[int]func(){
+		1: task1, 2: task2, 3: task3, 4: task4, 5: task5, 6: task6, 7: task7, 8: task8, 9: task9, 10: task10,
+		11: task11, 12: task12, 13: task13, 14: task14, 15: task15, 16: task16, 17: task17, 18: task18, 19: task19, 20: task20,
+		21: task21, 22: task22, 23: task23, 24: task24, 25: task25, 26: task26, 27: task27, 28: task28, 29: task29, 30: task30,
+	}
+}
+
+// ЗАДАЧА 1: Что выведет?
+// OUTPUT:
+//
+//	{0 0}        ← zero value структуры — zero value всех полей
+//	{x:1 y:0}    ← %+v печатает имена полей; неуказанные поля литерала — нули
+func task1() {
+	var p Point
+	fmt.Println(p)
+	fmt.Printf("%+v\n", Point{x: 1})
+}
+
+// ЗАДАЧА 2: Что выведет?
+// OUTPUT:
+//
+//	1 100   ← p2 := p1 копирует структуру целиком; p1 не меняется
+func task2() {
+	p1 := Point{x: 1, y: 2}
+	p2 := p1
+	p2.x = 100
+	fmt.Println(p1.x, p2.x)
+}
+
+// ЗАДАЧА 3: Что выведет?
+// OUTPUT:
+//
+//	42 Inner(42)   ← поле и метод Inner продвигаются в Outer: o.value == o.Inner.value
+func task3() {
+	o := Outer{Inner: Inner{value: 42}, name: "test"}
+	fmt.Println(o.value, o.describe())
+}
+
+// ЗАДАЧА 4: Что выведет?
+// OUTPUT:
+//
+//	100   ← o.set — продвинутый метод с pointer receiver; o адресуем → (&o.Inner).set(100)
+func task4() {
+	o := Outer{name: "test"}
+	o.set(100)
+	fmt.Println(o.Inner.value)
+}
+
+// ЗАДАЧА 5: Что выведет?
+// OUTPUT:
+//
+//	2 1   ← собственное поле value затеняет Inner.value; до встроенного — через s.Inner
+func task5() {
+	s := Shadow{Inner: Inner{value: 1}, value: 2}
+	fmt.Println(s.value, s.Inner.value)
+}
+
+// ЗАДАЧА 6: Что выведет?
+// OUTPUT:
+//
+//	Derived
+//	Hello from Base   ← встраивание — не наследование: hello() вызывает Base.name(),
+//	← у Base нет ссылки на Derived, виртуальных методов в Go нет
+func task6() {
+	d := Derived{}
+	fmt.Println(d.name())
+	fmt.Println(d.hello())
+}
+
+// ЗАДАЧА 7: Что выведет?
+// OUTPUT:
+//
+//	false   ← speak у *Dog: в method set значения Dog его нет → Dog не Speaker
+//	true    ← *Dog реализует Speaker
+func task7() {
+	var x any = Dog{}
+	_, ok := x.(Speaker)
+	fmt.Println(ok)
+	x = &Dog{}
+	_, ok = x.(Speaker)
+	fmt.Println(ok)
+}
+
+// ЗАДАЧА 8: Что выведет?
+// OUTPUT:
+//
+//	true true   ← speak у Cat (value receiver): в method set входит и у Cat, и у *Cat
+func task8() {
+	var x any = Cat{}
+	_, ok1 := x.(Speaker)
+	x = &Cat{}
+	_, ok2 := x.(Speaker)
+	fmt.Println(ok1, ok2)
+}
+
+// ЗАДАЧА 9: Что выведет?
+// OUTPUT:
+//
+//	1 2   ← method value c.get копирует c в момент вычисления (value receiver);
+//	← c.getPtr запоминает &c и видит последующие изменения
+func task9() {
+	c := Counter{count: 1}
+	get := c.get
+	getPtr := c.getPtr
+	c.count = 2
+	fmt.Println(get(), getPtr())
+}
+
+// ЗАДАЧА 10: Что выведет?
+// OUTPUT:
+//
+//	[1 2 3]   ← for _, c := range копирует элемент: c.count++ меняет копию
+//	[2 3 4]   ← по индексу меняется сам элемент
+func task10() {
+	counters := []Counter{{1}, {2}, {3}}
+	for _, c := range counters {
+		c.count++
+	}
+	fmt.Println(counts(counters))
+	for i := range counters {
+		counters[i].count++
+	}
+	fmt.Println(counts(counters))
+}
+func counts(cs []Counter) []int {
+	out := make([]int, len(cs))
+	for i, c := range cs {
+		out[i] = c.count
+	}
+	return out
+}
+
+// ЗАДАЧА 11: Что выведет?
+// OUTPUT:
+//
+//	true false   ← структуры сравнимы, если сравнимы все поля; сравнение поэлементное
+func task11() {
+	fmt.Println(Point{1, 2} == Point{1, 2}, Point{1, 2} == Point{2, 1})
+}
+
+// ЗАДАЧА 12: Что выведет?
+// OUTPUT:
+//
+//	2 b   ← сравнимая структура годится в ключ map: Point{1, 2} — один и тот же ключ
+func task12() {
+	m := map[Point]string{}
+	m[Point{1, 2}] = "a"
+	m[Point{1, 2}] = "b"
+	m[Point{2, 1}] = "c"
+	fmt.Println(len(m), m[Point{1, 2}])
+}
+
+// ЗАДАЧА 13: Что выведет?
+// OUTPUT:
+//
+//	true
+//	Recovered: runtime error: comparing uncomparable type []int
+//	← поле any компилируется, но сравнение падает, если внутри несравнимый тип
+func task13() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("Recovered:", r)
+		}
+	}()
+	fmt.Println(Key{1} == Key{1})
+	fmt.Println(Key{[]int{1}} == Key{[]int{1}})
+}
+
+// ЗАДАЧА 14: Что выведет?
+// OUTPUT:
+//
+//	Recovered: runtime error: hash of unhashable type []int
+//	← то же с ключом map: хешировать слайс внутри any нельзя
+func task14() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("Recovered:", r)
+		}
+	}()
+	m := map[Key]int{}
+	m[Key{[]int{1}}] = 1
+	fmt.Println(len(m))
+}
+
+// ЗАДАЧА 15: Что выведет?
+// OUTPUT:
+//
+//	0 0   ← struct{} и [0]int занимают 0 байт
+func task15() {
+	fmt.Println(unsafe.Sizeof(struct{}{}), unsafe.Sizeof([0]int{}))
+}
+
+// ЗАДАЧА 16: Что выведет?
+// OUTPUT:
+//
+//	map[a:{} b:{}] true false   ← map[string]struct{} — множество без затрат на значения
+func task16() {
+	set := map[string]struct{}{}
+	for _, s := range []string{"a", "b", "a"} {
+		set[s] = struct{}{}
+	}
+	_, hasA := set["a"]
+	_, hasC := set["c"]
+	fmt.Println(set, hasA, hasC)
+}
+
+// ЗАДАЧА 17: Что выведет (64-битная платформа)?
+// OUTPUT:
+//
+//	24   ← bool(1) + 7 байт выравнивания + int64(8) + bool(1) + 7 байт в конце
+//	16   ← int64(8) + bool(1) + bool(1) + 6 байт: поля от больших к меньшим экономят память
+func task17() {
+	type bad struct {
+		a bool
+		b int64
+		c bool
+	}
+	type good struct {
+		b int64
+		a bool
+		c bool
+	}
+	fmt.Println(unsafe.Sizeof(bad{}))
+	fmt.Println(unsafe.Sizeof(good{}))
+}
+
+// ЗАДАЧА 18: Что выведет (64-битная платформа)?
+// OUTPUT:
+//
+//	8 16   ← struct{} в начале ничего не стоит, а последним полем — добивается до 8 байт:
+//	← иначе &s.z указывал бы за пределы структуры
+func task18() {
+	type first struct {
+		z struct{}
+		a int64
+	}
+	type last struct {
+		a int64
+		z struct{}
+	}
+	fmt.Println(unsafe.Sizeof(first{}), unsafe.Sizeof(last{}))
+}
+
+// ЗАДАЧА 19: Что выведет (64-битная платформа)?
+// OUTPUT:
+//
+//	0 4 8   ← unsafe.Offsetof: int32 выравнивается на 4, int64 — на 8
+func task19() {
+	type s struct {
+		a int8
+		b int32
+		c int64
+	}
+	var v s
+	fmt.Println(unsafe.Offsetof(v.a), unsafe.Offsetof(v.b), unsafe.Offsetof(v.c))
+}
+
+// ЗАДАЧА 20: Что выведет?
+// OUTPUT:
+//
+//	{X:1 Y:2}
+//	true   ← анонимную структуру можно присвоить и сравнить с именованной,
+//	← если поля совпадают по именам, типам и порядку
+func task20() {
+	type P struct{ X, Y int }
+	a := struct{ X, Y int }{1, 2}
+	var p P = a
+	fmt.Printf("%+v\n", p)
+	fmt.Println(a == p)
+}
+
+// ЗАДАЧА 21: Что выведет?
+// OUTPUT:
+//
+//	[{go 1} {rust 2}]   ← слайс анонимных структур — частый приём в табличных тестах
+func task21() {
+	cases := []struct {
+		name string
+		n    int
+	}{
+		{"go", 1},
+		{"rust", 2},
+	}
+	fmt.Println(cases)
+}
+
+// ЗАДАЧА 22: Что выведет?
+// OUTPUT:
+//
+//	{"name":"Alice"}   ← Age=0 и omitempty → поле пропущено; json:"-" и неэкспортируемые не сериализуются
+func task22() {
+	u := User{Name: "Alice", Email: "a@example.com", password: "secret"}
+	b, _ := json.Marshal(u)
+	fmt.Println(string(b))
+}
+
+// ЗАДАЧА 23: Что выведет?
+// OUTPUT:
+//
+//	{Name:Bob Age:30 Email: password:}   ← ключи сопоставляются без учёта регистра;
+//	← "-" и неэкспортируемые поля при разборе игнорируются
+func task23() {
+	var u User
+	err := json.Unmarshal([]byte(`{"NAME":"Bob","age":30,"Email":"x","password":"y"}`), &u)
+	fmt.Printf("%+v\n", u)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// ЗАДАЧА 24: Что выведет?
+// OUTPUT:
+//
+//	{"created":"today","title":"Hi"}   ← поля встроенной структуры без тега поднимаются на верхний уровень
+func task24() {
+	p := Post{Meta: Meta{Created: "today"}, Title: "Hi"}
+	b, _ := json.Marshal(p)
+	fmt.Println(string(b))
+}
+
+// ЗАДАЧА 25: Что выведет?
+// OUTPUT:
+//
+//	{}   ← у структуры только неэкспортируемые поля — json их не видит
+func task25() {
+	b, _ := json.Marshal(Point{1, 2})
+	fmt.Println(string(b))
+}
+
+// ЗАДАЧА 26: Что выведет?
+// OUTPUT:
+//
+//	false true   ← append перевыделил массив и скопировал Account вместе с ЗАБЛОКИРОВАННЫМ mu:
+//	← копия мьютекса сохраняет его состояние. go vet (copylocks) ловит явные копии,
+//	← но не копирование внутри append — храните []*Account
+func task26() {
+	accounts := make([]Account, 1)
+	accounts[0].mu.Lock()
+	accounts = append(accounts, Account{})
+	fmt.Println(accounts[0].mu.TryLock(), accounts[1].mu.TryLock())
+}
+
+// ЗАДАЧА 27: Что выведет?
+// OUTPUT:
+//
+//	true 150   ← []*Account: append копирует указатели, мьютексы остаются на месте
+func task27() {
+	accounts := []*Account{{balance: 100}}
+	accounts[0].mu.Lock()
+	accounts = append(accounts, &Account{})
+	accounts[0].balance += 50
+	accounts[0].mu.Unlock()
+	fmt.Println(accounts[0].mu.TryLock(), accounts[0].balance)
+}
+
+// ЗАДАЧА 28: Что выведет?
+// OUTPUT:
+//
+//	[100 2] [100 2]   ← копия структуры поверхностная: слайс внутри общий
+//	[1 2] [100 2]     ← массив копируется целиком
+func task28() {
+	type withSlice struct{ data []int }
+	type withArray struct{ data [2]int }
+
+	s1 := withSlice{data: []int{1, 2}}
+	s2 := s1
+	s2.data[0] = 100
+	fmt.Println(s1.data, s2.data)
+
+	a1 := withArray{data: [2]int{1, 2}}
+	a2 := a1
+	a2.data[0] = 100
+	fmt.Println(a1.data, a2.data)
+}
+
+// ЗАДАЧА 29: Что выведет?
+// OUTPUT:
+//
+//	0 6   ← метод с pointer receiver можно вызвать на nil: sum сам проверяет t == nil
+func task29() {
+	var t *Tree
+	fmt.Println(t.sum(), (&Tree{val: 1, left: &Tree{val: 5}}).sum())
+}
+
+// ЗАДАЧА 30: Что выведет?
+// OUTPUT:
+//
+//	true   ← new(Point) и &Point{} — одно и то же: указатель на zero value
+//	false  ← но это два разных объекта
+func task30() {
+	p1 := new(Point)
+	p2 := &Point{}
+	fmt.Println(*p1 == *p2)
+	fmt.Println(p1 == p2)
+}